@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
@@ -23,6 +25,22 @@ func main() {
 		log.Fatalf("Failed to initialize logger: %v\n", err)
 	}
 
+	if len(os.Args) > 1 {
+		err := runCommand(ctx, cfg, logger, os.Args[1:])
+		if err != nil {
+			logger.Error("Command failed", "command", os.Args[1], "error", err)
+		}
+
+		// логгер дописывает очередь только после отмены контекста
+		cancel()
+		logger.Shutdown()
+
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	application, err := app.New(cfg, logger)
 	if err != nil {
 		logger.Error("Application init failed", "error", err)
@@ -34,3 +52,20 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func runCommand(ctx context.Context, cfg *config.Config, logger *logger.Logger, args []string) error {
+	switch args[0] {
+	case "restore":
+		if len(args) < 2 {
+			return errors.New("usage: tunes restore <snapshot.tar.gz>")
+		}
+		return app.Restore(ctx, cfg, logger, args[1])
+	case "slugs":
+		return app.BackfillSlugs(ctx, cfg, logger)
+	case "ratings":
+		fix := len(args) > 1 && args[1] == "--fix"
+		return app.CheckRatings(ctx, cfg, logger, fix)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
@@ -8,26 +8,47 @@ import (
 	"github.com/maYkiss56/tunes/internal/delivery/api"
 	"github.com/maYkiss56/tunes/internal/delivery/api/album"
 	"github.com/maYkiss56/tunes/internal/delivery/api/artist"
+	"github.com/maYkiss56/tunes/internal/delivery/api/audit"
+	"github.com/maYkiss56/tunes/internal/delivery/api/comment"
+	"github.com/maYkiss56/tunes/internal/delivery/api/feed"
+	"github.com/maYkiss56/tunes/internal/delivery/api/follow"
 	"github.com/maYkiss56/tunes/internal/delivery/api/genre"
+	"github.com/maYkiss56/tunes/internal/delivery/api/library"
+	"github.com/maYkiss56/tunes/internal/delivery/api/lookup"
+	"github.com/maYkiss56/tunes/internal/delivery/api/lyrics"
+	"github.com/maYkiss56/tunes/internal/delivery/api/notification"
+	"github.com/maYkiss56/tunes/internal/delivery/api/playlist"
+	"github.com/maYkiss56/tunes/internal/delivery/api/rating"
+	"github.com/maYkiss56/tunes/internal/delivery/api/release"
 	"github.com/maYkiss56/tunes/internal/delivery/api/review"
+	"github.com/maYkiss56/tunes/internal/delivery/api/scrobble"
+	"github.com/maYkiss56/tunes/internal/delivery/api/snapshot"
 	"github.com/maYkiss56/tunes/internal/delivery/api/song"
+	"github.com/maYkiss56/tunes/internal/delivery/api/trash"
 	"github.com/maYkiss56/tunes/internal/delivery/api/user"
 	"github.com/maYkiss56/tunes/internal/logger"
 	"github.com/maYkiss56/tunes/internal/middleware"
 	"github.com/maYkiss56/tunes/internal/repository"
 	"github.com/maYkiss56/tunes/internal/server"
 	"github.com/maYkiss56/tunes/internal/service"
+	"github.com/maYkiss56/tunes/pkg/client/mail"
 	"github.com/maYkiss56/tunes/pkg/client/postgresql"
 )
 
 type App struct {
-	cfg        *config.Config
-	httpServer *server.HTTPServer
-	db         *postgresql.PgClient
-	logger     *logger.Logger
+	cfg                 *config.Config
+	httpServer          *server.HTTPServer
+	db                  *postgresql.PgClient
+	trashService        *service.TrashService
+	songService         *service.SongService
+	ratingService       *service.RatingService
+	reviewService       *service.ReviewService
+	notificationService *service.NotificationService
+	releaseService      *service.ReleaseService
+	logger              *logger.Logger
 }
 
-func New(cfg *config.Config, logger *logger.Logger) (*App, error) {
+func newDBClient(cfg *config.Config, logger *logger.Logger) (*postgresql.PgClient, error) {
 	pgCfg := postgresql.NewPgConfig(
 		cfg.PostgreSQL.Username,
 		cfg.PostgreSQL.Password,
@@ -43,33 +64,121 @@ func New(cfg *config.Config, logger *logger.Logger) (*App, error) {
 		return nil, fmt.Errorf("db client init failed: %w", err)
 	}
 
+	return dbClient, nil
+}
+
+func New(cfg *config.Config, logger *logger.Logger) (*App, error) {
+	dbClient, err := newDBClient(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	logger.Info("try get pool")
 	pool := dbClient.GetPool()
+	uow := repository.NewUnitOfWork(pool, logger)
+
+	auditRepo := repository.NewAuditRepository(pool, logger)
+	auditService := service.NewAuditService(auditRepo, logger)
+	auditHandler := audit.NewHandler(auditService, logger)
 
 	userRepo := repository.NewUserRepository(pool, logger)
 	userService := service.NewUserService(userRepo, logger)
 	userHandler := user.NewHandler(userService, logger)
 
+	notificationRepo := repository.NewNotificationRepository(pool, logger)
+	notificationService := service.NewNotificationService(notificationRepo, logger)
+	notificationHandler := notification.NewHandler(notificationService, logger)
+
+	mentionRepo := repository.NewMentionRepository(pool, logger)
+	mentionService := service.NewMentionService(mentionRepo, notificationService, logger)
+
 	artistRepo := repository.NewArtistRepository(pool, logger)
-	artistService := service.NewArtistService(artistRepo, logger)
+	artistService := service.NewArtistService(artistRepo, mentionService, auditService, uow, logger)
 	artistHandler := artist.NewHandler(artistService, logger)
 
+	libraryRepo := repository.NewLibraryRepository(pool, logger)
+	libraryService := service.NewLibraryService(libraryRepo, uow, logger)
+	libraryHandler := library.NewHandler(libraryService, logger)
+
 	albumRepo := repository.NewAlbumRepository(pool, logger)
-	albumService := service.NewAlbumService(albumRepo, logger)
+	albumService := service.NewAlbumService(albumRepo, libraryRepo, auditService, uow, logger)
 	albumHandler := album.NewHandler(albumService, logger)
 
 	songRepo := repository.NewSongRepository(pool, logger)
-	songService := service.NewSongService(songRepo, logger)
+	songService := service.NewSongService(songRepo, libraryRepo, auditService, uow, logger)
 	songHandler := song.NewHandler(songService, logger)
 
+	lyricsRepo := repository.NewLyricsRepository(pool, logger)
+	lyricsService := service.NewLyricsService(lyricsRepo, songService, auditService, uow, logger)
+	lyricsHandler := lyrics.NewHandler(lyricsService, logger)
+
 	genreRepo := repository.NewGenreRepository(pool, logger)
-	genreService := service.NewGenreService(genreRepo, logger)
+	genreService := service.NewGenreService(genreRepo, auditService, uow, logger)
 	genreHandler := genre.NewHandler(genreService, logger)
 
 	reviewRepo := repository.NewReviewRepository(pool, logger, userRepo, songRepo)
-	reviewService := service.NewReviewService(reviewRepo, songRepo, logger)
+	reviewService := service.NewReviewService(
+		reviewRepo, songRepo, albumRepo, artistRepo, mentionService, notificationService, uow, auditService, logger,
+	)
 	reviewHandler := review.NewHandler(reviewService, logger)
 
+	playlistRepo := repository.NewPlaylistRepository(pool, logger)
+
+	feedRepo := repository.NewFeedRepository(pool, logger)
+	feedService := service.NewFeedService(
+		feedRepo, reviewRepo, playlistRepo, cfg.Feed.CacheTTL, cfg.Feed.CacheSize, logger,
+	)
+	feedHandler := feed.NewHandler(feedService, logger)
+
+	followRepo := repository.NewFollowRepository(pool, logger)
+	followService := service.NewFollowService(followRepo, feedService, notificationService, uow, logger)
+	followHandler := follow.NewHandler(followService, logger)
+
+	var mailer service.Mailer
+	if cfg.Mail.Host != "" {
+		mailer = mail.NewClient(mail.NewConfig(
+			cfg.Mail.Host,
+			cfg.Mail.Port,
+			cfg.Mail.Username,
+			cfg.Mail.Password,
+			cfg.Mail.From,
+		))
+	}
+
+	releaseRepo := repository.NewReleaseRepository(pool, logger)
+	releaseService := service.NewReleaseService(
+		releaseRepo, notificationService, uow, mailer, cfg.Mail.SiteURL, logger,
+	)
+	releaseHandler := release.NewHandler(releaseService, logger)
+
+	playlistService := service.NewPlaylistService(playlistRepo, followRepo, uow, logger)
+	playlistHandler := playlist.NewHandler(playlistService, logger)
+
+	scrobbleRepo := repository.NewScrobbleRepository(pool, logger)
+	scrobbleService := service.NewScrobbleService(scrobbleRepo, uow, cfg.Scrobble.Secret, logger)
+	scrobbleHandler := scrobble.NewHandler(scrobbleService, logger)
+
+	commentRepo := repository.NewCommentRepository(pool, logger)
+	commentService := service.NewCommentService(
+		commentRepo, reviewService, notificationService, auditService, uow, logger,
+	)
+	commentHandler := comment.NewHandler(commentService, logger)
+
+	snapshotRepo := repository.NewSnapshotRepository(pool, logger)
+	snapshotService := service.NewSnapshotService(snapshotRepo, logger)
+	snapshotHandler := snapshot.NewHandler(snapshotService, logger)
+
+	lookupService := service.NewLookupService(songRepo, albumRepo, artistRepo, logger)
+	lookupHandler := lookup.NewHandler(lookupService, logger)
+
+	trashRepo := repository.NewTrashRepository(pool, logger)
+	trashService := service.NewTrashService(trashRepo, cfg.Trash.Retention, logger)
+	trashHandler := trash.NewHandler(trashService, logger)
+
+	ratingRepo := repository.NewRatingRepository(pool, logger)
+	ratingService := service.NewRatingService(ratingRepo, cfg.RatingCheck.BatchSize, logger)
+	ratingHandler := rating.NewHandler(ratingService, logger)
+
 	router := api.NewRouter(
 		userHandler,
 		songHandler,
@@ -77,6 +186,20 @@ func New(cfg *config.Config, logger *logger.Logger) (*App, error) {
 		albumHandler,
 		genreHandler,
 		reviewHandler,
+		snapshotHandler,
+		lookupHandler,
+		trashHandler,
+		auditHandler,
+		lyricsHandler,
+		ratingHandler,
+		commentHandler,
+		notificationHandler,
+		followHandler,
+		feedHandler,
+		releaseHandler,
+		playlistHandler,
+		scrobbleHandler,
+		libraryHandler,
 		logger,
 	)
 
@@ -89,10 +212,16 @@ func New(cfg *config.Config, logger *logger.Logger) (*App, error) {
 	}
 
 	return &App{
-		cfg:        cfg,
-		httpServer: httpServer,
-		db:         dbClient,
-		logger:     logger,
+		cfg:                 cfg,
+		httpServer:          httpServer,
+		db:                  dbClient,
+		trashService:        trashService,
+		songService:         songService,
+		ratingService:       ratingService,
+		reviewService:       reviewService,
+		releaseService:      releaseService,
+		notificationService: notificationService,
+		logger:              logger,
 	}, nil
 }
 
@@ -107,6 +236,13 @@ func (a *App) Run(ctx context.Context) error {
 	serverErr := make(chan error, 1)
 
 	go a.httpServer.Start(serverErr)
+	go a.trashService.RunPurge(ctx, a.cfg.Trash.PurgeInterval)
+	go a.songService.RunRankRefresh(ctx, a.cfg.Ranking.RefreshInterval)
+	go a.ratingService.RunCheck(ctx, a.cfg.RatingCheck.Interval, a.cfg.RatingCheck.Fix)
+	go a.reviewService.RunPublishScheduled(ctx, a.cfg.Drafts.PublishInterval)
+	go a.notificationService.RunListener(ctx)
+	go a.releaseService.RunDispatch(ctx, a.cfg.Releases.DispatchInterval)
+	go a.releaseService.RunDigests(ctx, a.cfg.Releases.DigestInterval)
 
 	select {
 	case err := <-serverErr:
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/maYkiss56/tunes/internal/config"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/repository"
+	"github.com/maYkiss56/tunes/internal/service"
+)
+
+// Restore восстанавливает каталог из архива снапшота в пустую базу
+func Restore(ctx context.Context, cfg *config.Config, logger *logger.Logger, path string) error {
+	dbClient, err := newDBClient(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	snapshotRepo := repository.NewSnapshotRepository(dbClient.GetPool(), logger)
+	snapshotService := service.NewSnapshotService(snapshotRepo, logger)
+
+	res, err := snapshotService.Restore(ctx, f)
+	if err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+
+	logger.Info("Snapshot restored",
+		"genres", len(res.Genres),
+		"artists", len(res.Artists),
+		"albums", len(res.Albums),
+		"songs", len(res.Songs),
+		"lyrics", res.Lyrics,
+		"reviews", res.Reviews,
+		"skipped_reviews", res.SkippedReviews,
+	)
+
+	return nil
+}
+
+// BackfillSlugs выдаёт слаги артистам, альбомам и песням, созданным до их появления
+func BackfillSlugs(ctx context.Context, cfg *config.Config, logger *logger.Logger) error {
+	dbClient, err := newDBClient(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	slugRepo := repository.NewSlugRepository(dbClient.GetPool(), logger)
+
+	n, err := slugRepo.Backfill(ctx)
+	if err != nil {
+		return fmt.Errorf("backfill slugs: %w", err)
+	}
+
+	logger.Info("Slugs assigned", "count", n)
+
+	return nil
+}
+
+// CheckRatings сверяет счётчики песен, альбомов и исполнителей с рецензиями, при fix исправляет расхождения
+func CheckRatings(ctx context.Context, cfg *config.Config, logger *logger.Logger, fix bool) error {
+	dbClient, err := newDBClient(cfg, logger)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Close()
+
+	ratingRepo := repository.NewRatingRepository(dbClient.GetPool(), logger)
+	ratingService := service.NewRatingService(ratingRepo, cfg.RatingCheck.BatchSize, logger)
+
+	if _, err = ratingService.Check(ctx, fix); err != nil {
+		return fmt.Errorf("check ratings: %w", err)
+	}
+
+	return nil
+}
@@ -31,6 +31,47 @@ type Config struct {
 		Database string `yaml:"database"`
 		SSLMode  string `yaml:"sslmode"`
 	} `yaml:"postgre"`
+	Trash struct {
+		Retention     time.Duration `yaml:"retention" env-default:"720h"`
+		PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+	} `yaml:"trash"`
+	Ranking struct {
+		RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"15m"`
+	} `yaml:"ranking"`
+	RatingCheck struct {
+		Interval  time.Duration `yaml:"interval" env-default:"24h"`
+		Fix       bool          `yaml:"fix" env-default:"true"`
+		BatchSize int           `yaml:"batch_size" env-default:"500"`
+	} `yaml:"rating_check"`
+	Drafts struct {
+		PublishInterval time.Duration `yaml:"publish_interval" env-default:"1m"`
+	} `yaml:"drafts"`
+	Feed struct {
+		// CacheTTL время жизни первой страницы ленты в кэше
+		CacheTTL time.Duration `yaml:"cache_ttl" env-default:"30s"`
+		// CacheSize число пользователей, чьи ленты держатся в кэше
+		CacheSize int `yaml:"cache_size" env-default:"10000"`
+	} `yaml:"feed"`
+	Releases struct {
+		// DispatchInterval период рассылки уведомлений о новых релизах
+		DispatchInterval time.Duration `yaml:"dispatch_interval" env-default:"1m"`
+		// DigestInterval период проверки, кому пора отправить сводку
+		DigestInterval time.Duration `yaml:"digest_interval" env-default:"1h"`
+	} `yaml:"releases"`
+	// Mail без Host сводки на почту не отправляются
+	Mail struct {
+		Host     string `yaml:"host"`
+		Port     string `yaml:"port" env-default:"587"`
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+		From     string `yaml:"from"`
+		// SiteURL адрес сайта для ссылок в письмах
+		SiteURL string `yaml:"site_url"`
+	} `yaml:"mail"`
+	Scrobble struct {
+		// Secret общий секрет клиентов для проверки api_sig; без него протокол скробблеров отключён
+		Secret string `yaml:"secret"`
+	} `yaml:"scrobble"`
 }
 
 const configPath = "configs/config.local.yaml"
@@ -2,6 +2,7 @@ package album
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -9,16 +10,22 @@ import (
 
 	domain "github.com/maYkiss56/tunes/internal/domain/album"
 	"github.com/maYkiss56/tunes/internal/domain/album/dto"
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
+	"github.com/maYkiss56/tunes/internal/domain/slug"
+	"github.com/maYkiss56/tunes/internal/domain/trash"
 	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
 	"github.com/maYkiss56/tunes/internal/utilites"
 )
 
 type AlbumService interface {
 	CreateAlbum(ctx context.Context, album *domain.Album) error
-	GetAllAlbums(ctx context.Context) ([]dto.Response, error)
+	GetAllAlbums(ctx context.Context, viewerID int) ([]dto.Response, error)
 	GetAlbumByID(ctx context.Context, id int) (*dto.Response, error)
+	GetAlbumByRef(ctx context.Context, viewerID int, ref string) (*dto.Response, error)
 	UpdateAlbum(ctx context.Context, id int, update dto.UpdateAlbumRequest) error
 	DeleteAlbum(ctx context.Context, id int) error
+	RestoreAlbum(ctx context.Context, id int) error
 }
 
 type Handler struct {
@@ -71,15 +78,33 @@ func (h *Handler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	req := dto.CreateAlbumRequest{
+		Title:    title,
+		ImageURL: imagePath,
+		ArtistID: artistID,
+		UPC:      r.FormValue("upc"),
+		MBID:     r.FormValue("mbid"),
+	}
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	newAlbum, err := domain.NewAlbum(title, imagePath, artistID)
 	if err != nil {
 		h.logger.Error("invalid input album", "error", err)
 		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
+	newAlbum.UPC = req.UPC
+	newAlbum.MBID = req.MBID
 
 	if err := h.service.CreateAlbum(r.Context(), newAlbum); err != nil {
 		h.logger.Error("failed to create album", "error", err)
+		if errors.Is(err, identifier.ErrDuplicate) {
+			utilites.RenderError(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to create album")
 		return
 	}
@@ -91,7 +116,7 @@ func (h *Handler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAllAlbums(w http.ResponseWriter, r *http.Request) {
-	albums, err := h.service.GetAllAlbums(r.Context())
+	albums, err := h.service.GetAllAlbums(r.Context(), session.UserID(r.Context()))
 	if err != nil {
 		h.logger.Error("failed to get albums", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get albums")
@@ -107,15 +132,17 @@ func (h *Handler) GetAllAlbums(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAlbumByID(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if err != nil {
-		h.logger.Error("invalid album id", "error", err)
-		utilites.RenderError(w, r, http.StatusBadRequest, "invalid album id")
-		return
-	}
-
-	a, err := h.service.GetAlbumByID(r.Context(), id)
+	a, err := h.service.GetAlbumByRef(r.Context(), session.UserID(r.Context()), chi.URLParam(r, "id"))
 	if err != nil {
+		var moved *slug.MovedError
+		if errors.As(err, &moved) {
+			http.Redirect(w, r, "/api/albums/"+moved.Slug, http.StatusMovedPermanently)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
 		h.logger.Error("failed to get album by id", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get album by id")
 		return
@@ -169,8 +196,27 @@ func (h *Handler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
 		req.ArtistID = &artistID
 	}
 
+	// наличие поля с пустым значением сбрасывает идентификатор
+	if r.Form.Has("upc") {
+		upc := r.FormValue("upc")
+		req.UPC = &upc
+	}
+	if r.Form.Has("mbid") {
+		mbid := r.FormValue("mbid")
+		req.MBID = &mbid
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if err := h.service.UpdateAlbum(r.Context(), id, req); err != nil {
 		h.logger.Error("failed to update album", "error", err)
+		if errors.Is(err, identifier.ErrDuplicate) {
+			utilites.RenderError(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to update album")
 		return
 	}
@@ -195,9 +241,34 @@ func (h *Handler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.DeleteAlbum(r.Context(), id); err != nil {
 		h.logger.Error("failed to delete album", "error", err)
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to delete album")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *Handler) RestoreAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("invalid album id", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid album id")
+		return
+	}
+
+	if err := h.service.RestoreAlbum(r.Context(), id); err != nil {
+		h.logger.Error("failed to restore album", "error", err)
+		if errors.Is(err, trash.ErrNotInTrash) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to restore album")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -8,6 +8,8 @@ import (
 
 func RegisterPublicRoutes(r chi.Router, handler *Handler) {
 	r.Route("/", func(r chi.Router) {
+		r.Use(middleware.OptionalAuthMiddleware)
+
 		r.Get("/", handler.GetAllAlbums)
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", handler.GetAlbumByID)
@@ -25,6 +27,7 @@ func RegisterAdminRoutes(r chi.Router, handler *Handler) {
 		r.Route("/{id}", func(r chi.Router) {
 			r.Patch("/", handler.UpdateAlbum)
 			r.Delete("/", handler.DeleteAlbum)
+			r.Post("/restore", handler.RestoreAlbum)
 		})
 	})
 }
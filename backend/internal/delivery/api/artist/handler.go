@@ -3,6 +3,8 @@ package artist
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -10,6 +12,9 @@ import (
 
 	domain "github.com/maYkiss56/tunes/internal/domain/artist"
 	"github.com/maYkiss56/tunes/internal/domain/artist/dto"
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
+	"github.com/maYkiss56/tunes/internal/domain/slug"
+	"github.com/maYkiss56/tunes/internal/domain/trash"
 	"github.com/maYkiss56/tunes/internal/logger"
 	"github.com/maYkiss56/tunes/internal/utilites"
 )
@@ -18,8 +23,12 @@ type ArtistService interface {
 	CreateArtist(ctx context.Context, artist *domain.Artist) error
 	GetAllArtists(ctx context.Context) ([]*domain.Artist, error)
 	GetArtistByID(ctx context.Context, id int) (*domain.Artist, error)
+	GetArtistByRef(ctx context.Context, ref string) (*domain.Artist, error)
 	UpdateArtist(ctx context.Context, id int, update dto.UpdateArtistRequest) error
 	DeleteArtist(ctx context.Context, id int) error
+	RestoreArtist(ctx context.Context, id int) error
+	FindDuplicates(ctx context.Context, threshold float64) ([]domain.Duplicate, error)
+	MergeArtists(ctx context.Context, sourceID, targetID int) (*domain.MergeResult, error)
 }
 
 type Handler struct {
@@ -53,9 +62,14 @@ func (h *Handler) CreateArtist(w http.ResponseWriter, r *http.Request) {
 		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
+	newArtist.MBID = req.MBID
 
 	if err := h.service.CreateArtist(r.Context(), newArtist); err != nil {
 		h.logger.Error("failed to create artist", "error", err)
+		if errors.Is(err, identifier.ErrDuplicate) {
+			utilites.RenderError(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to create artist")
 		return
 	}
@@ -79,21 +93,28 @@ func (h *Handler) GetAllArtists(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetArtistByID(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.Atoi(chi.URLParam(r, "id"))
-	if err != nil {
-		h.logger.Error("invalid artist id", "error", err)
-		utilites.RenderError(w, r, http.StatusBadRequest, "invalid artist id")
-		return
-	}
-
-	a, err := h.service.GetArtistByID(r.Context(), id)
+	a, err := h.service.GetArtistByRef(r.Context(), chi.URLParam(r, "id"))
 	if err != nil {
+		var moved *domain.MovedError
+		if errors.As(err, &moved) {
+			http.Redirect(w, r, fmt.Sprintf("/api/artists/%d", moved.ID), http.StatusMovedPermanently)
+			return
+		}
+		var slugMoved *slug.MovedError
+		if errors.As(err, &slugMoved) {
+			http.Redirect(w, r, "/api/artists/"+slugMoved.Slug, http.StatusMovedPermanently)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
 		h.logger.Error("failed to get artist by id", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get artist by id")
 		return
 	}
 
-	utilites.RenderJSON(w, r, http.StatusOK, dto.Response(*a))
+	utilites.RenderJSON(w, r, http.StatusOK, dto.ToResponse(*a))
 }
 
 func (h *Handler) UpdateArtist(w http.ResponseWriter, r *http.Request) {
@@ -119,6 +140,10 @@ func (h *Handler) UpdateArtist(w http.ResponseWriter, r *http.Request) {
 
 	if err = h.service.UpdateArtist(r.Context(), id, req); err != nil {
 		h.logger.Error("failed to update artist", "error", err)
+		if errors.Is(err, identifier.ErrDuplicate) {
+			utilites.RenderError(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to update artist")
 		return
 	}
@@ -143,9 +168,111 @@ func (h *Handler) DeleteArtist(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.DeleteArtist(r.Context(), id); err != nil {
 		h.logger.Error("failed to delete artist", "error", err)
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to delete artist")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *Handler) RestoreArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("invalid artist id", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid artist id")
+		return
+	}
+
+	if err := h.service.RestoreArtist(r.Context(), id); err != nil {
+		h.logger.Error("failed to restore artist", "error", err)
+		if errors.Is(err, trash.ErrNotInTrash) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to restore artist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) FindDuplicates(w http.ResponseWriter, r *http.Request) {
+	threshold := domain.DefaultSimilarity
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t <= 0 || t > 1 {
+			utilites.RenderError(w, r, http.StatusBadRequest, "threshold must be in (0, 1]")
+			return
+		}
+		threshold = t
+	}
+
+	dups, err := h.service.FindDuplicates(r.Context(), threshold)
+	if err != nil {
+		h.logger.Error("failed to find duplicate artists", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to find duplicate artists")
+		return
+	}
+
+	res := make([]dto.DuplicateResponse, 0, len(dups))
+	for _, d := range dups {
+		res = append(res, dto.ToDuplicateResponse(d))
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) MergeArtist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("invalid artist id", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid artist id")
+		return
+	}
+
+	var req dto.MergeArtistRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.MergeArtists(r.Context(), id, req.Into)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrMergeSelf):
+			utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrNotFound):
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+		default:
+			h.logger.Error("failed to merge artists", "error", err)
+			utilites.RenderError(w, r, http.StatusInternalServerError, "failed to merge artists")
+		}
+		return
+	}
+
+	merged, err := h.service.GetArtistByID(r.Context(), res.TargetID)
+	if err != nil {
+		h.logger.Error("failed to get merged artist", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get merged artist")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, dto.MergeResponse{
+		Artist:       dto.ToResponse(*merged),
+		MergedID:     res.SourceID,
+		SongsMoved:   res.Songs,
+		AlbumsMoved:  res.Albums,
+		ReviewsMoved: res.Reviews,
+	})
+}
@@ -21,9 +21,12 @@ func RegisterAdminRoutes(r chi.Router, handler *Handler) {
 		r.Use(middleware.AdminOnlyMiddleware)
 
 		r.Post("/", handler.CreateArtist)
+		r.Get("/duplicates", handler.FindDuplicates)
 		r.Route("/{id}", func(r chi.Router) {
 			r.Patch("/", handler.UpdateArtist)
 			r.Delete("/", handler.DeleteArtist)
+			r.Post("/restore", handler.RestoreArtist)
+			r.Post("/merge", handler.MergeArtist)
 		})
 	})
 }
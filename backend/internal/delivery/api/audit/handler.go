@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/audit"
+	"github.com/maYkiss56/tunes/internal/domain/audit/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type AuditService interface {
+	ListEntries(ctx context.Context, filter domain.Filter) ([]dto.Response, error)
+}
+
+type Handler struct {
+	service AuditService
+	logger  *logger.Logger
+}
+
+func NewHandler(service AuditService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var req dto.ListRequest
+	var err error
+
+	ints := map[string]*int{
+		"actor_id":  &req.ActorID,
+		"entity_id": &req.EntityID,
+		"limit":     &req.Limit,
+		"offset":    &req.Offset,
+	}
+	for name, dst := range ints {
+		if v := q.Get(name); v != "" {
+			if *dst, err = strconv.Atoi(v); err != nil {
+				utilites.RenderError(w, r, http.StatusBadRequest, "invalid "+name+" parameter")
+				return
+			}
+		}
+	}
+
+	times := map[string]*time.Time{
+		"from": &req.From,
+		"to":   &req.To,
+	}
+	for name, dst := range times {
+		if v := q.Get(name); v != "" {
+			if *dst, err = time.Parse(time.RFC3339, v); err != nil {
+				utilites.RenderError(w, r, http.StatusBadRequest, "invalid "+name+" parameter, expected RFC3339")
+				return
+			}
+		}
+	}
+
+	req.Entity = q.Get("entity")
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := h.service.ListEntries(r.Context(), req.ToFilter())
+	if err != nil {
+		h.logger.Error("failed to list audit entries", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to list audit entries")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, entries)
+}
@@ -0,0 +1,235 @@
+package comment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/comment"
+	"github.com/maYkiss56/tunes/internal/domain/comment/dto"
+	"github.com/maYkiss56/tunes/internal/domain/moderation"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type CommentService interface {
+	CreateComment(ctx context.Context, c *domain.Comment, parentID *int) (*dto.Response, error)
+	GetThread(ctx context.Context, reviewID int, req dto.ListRequest) (*dto.ThreadResponse, error)
+	GetModerationQueue(ctx context.Context, status moderation.Status, req dto.ListRequest) ([]dto.Response, error)
+	UpdateComment(ctx context.Context, id int, body string) (*dto.Response, error)
+	DeleteComment(ctx context.Context, id int) error
+	ModerateComment(ctx context.Context, id int, req dto.ModerateCommentRequest) (*dto.Response, error)
+}
+
+type Handler struct {
+	service CommentService
+	logger  *logger.Logger
+}
+
+func NewHandler(service CommentService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) GetThread(w http.ResponseWriter, r *http.Request) {
+	reviewID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.GetThread(r.Context(), reviewID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get comments")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	reviewID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c, err := domain.NewComment(reviewID, s.UserID, req.Body)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.CreateComment(r.Context(), c, req.ParentID)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to create comment")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusCreated, *res)
+}
+
+func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "commentID"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.UpdateComment(r.Context(), id, req.Body)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to update comment")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "commentID"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+
+	if err = h.service.DeleteComment(r.Context(), id); err != nil {
+		h.renderServiceError(w, r, err, "failed to delete comment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetModerationQueue по умолчанию выводит комментарии, ожидающие модерации
+func (h *Handler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	status := moderation.StatusPending
+	if v := r.URL.Query().Get("status"); v != "" {
+		status = moderation.Status(v)
+		if !status.IsValid() {
+			utilites.RenderError(w, r, http.StatusBadRequest, "invalid status")
+			return
+		}
+	}
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.GetModerationQueue(r.Context(), status, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get comments")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, res)
+}
+
+func (h *Handler) ModerateComment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+
+	var req dto.ModerateCommentRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.ModerateComment(r.Context(), id, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to moderate comment")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) renderServiceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrReviewNotFound),
+		errors.Is(err, domain.ErrParentNotFound):
+		utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		utilites.RenderError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrTooDeep):
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(msg, "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, msg)
+	}
+}
+
+func parseListRequest(r *http.Request) (dto.ListRequest, error) {
+	var req dto.ListRequest
+
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid offset parameter")
+		}
+		req.Offset = offset
+	}
+
+	return req, req.Validate()
+}
@@ -0,0 +1,30 @@
+package comment
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+// RegisterPublicRoutes монтируется на /api/reviews/{id}/comments
+func RegisterPublicRoutes(r chi.Router, handler *Handler) {
+	r.Get("/", handler.GetThread)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Post("/", handler.CreateComment)
+		r.Patch("/{commentID}", handler.UpdateComment)
+		r.Delete("/{commentID}", handler.DeleteComment)
+	})
+}
+
+func RegisterAdminRoutes(r chi.Router, handler *Handler) {
+	r.Route("/", func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.AdminOnlyMiddleware)
+
+		r.Get("/", handler.GetModerationQueue)
+		r.Post("/{id}/moderate", handler.ModerateComment)
+	})
+}
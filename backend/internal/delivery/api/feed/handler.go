@@ -0,0 +1,72 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/feed"
+	"github.com/maYkiss56/tunes/internal/domain/feed/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type FeedService interface {
+	GetFeed(ctx context.Context, userID int, req dto.Request) (*dto.PageResponse, error)
+}
+
+type Handler struct {
+	service FeedService
+	logger  *logger.Logger
+}
+
+func NewHandler(service FeedService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetFeed выводит страницу ленты подписок; следующая запрашивается с ?cursor=next_cursor
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.GetFeed(r.Context(), s.UserID, req)
+	if err != nil {
+		h.logger.Error("failed to get feed", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get feed")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *page)
+}
+
+func parseRequest(r *http.Request) (dto.Request, error) {
+	var req dto.Request
+
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := domain.DecodeCursor(v)
+		if err != nil {
+			return req, err
+		}
+		req.Cursor = cursor
+	}
+
+	return req, req.Validate()
+}
@@ -0,0 +1,16 @@
+package feed
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+// RegisterRoutes монтируется на /api/feed
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetFeed)
+	})
+}
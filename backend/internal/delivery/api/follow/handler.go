@@ -0,0 +1,201 @@
+package follow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/follow"
+	"github.com/maYkiss56/tunes/internal/domain/follow/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type FollowService interface {
+	GetProfile(ctx context.Context, viewerID, userID int) (*dto.ProfileResponse, error)
+	Follow(ctx context.Context, followerID, followeeID int) error
+	Unfollow(ctx context.Context, followerID, followeeID int) error
+	Block(ctx context.Context, blockerID, blockedID int) error
+	Unblock(ctx context.Context, blockerID, blockedID int) error
+	GetFollowers(ctx context.Context, userID int, req dto.ListRequest) ([]dto.UserResponse, error)
+	GetFollowing(ctx context.Context, userID int, req dto.ListRequest) ([]dto.UserResponse, error)
+	GetBlocked(ctx context.Context, userID int, req dto.ListRequest) ([]dto.UserResponse, error)
+}
+
+type Handler struct {
+	service FollowService
+	logger  *logger.Logger
+}
+
+func NewHandler(service FollowService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetProfile выводит публичный профиль; вошедшему пользователю добавляется его отношение к профилю
+func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	var viewerID int
+	if s := session.FromContext(r.Context()); s != nil {
+		viewerID = s.UserID
+	}
+
+	profile, err := h.service.GetProfile(r.Context(), viewerID, id)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get profile")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *profile)
+}
+
+func (h *Handler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	h.renderList(w, r, h.service.GetFollowers)
+}
+
+func (h *Handler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	h.renderList(w, r, h.service.GetFollowing)
+}
+
+func (h *Handler) Follow(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, h.service.Follow, http.StatusOK)
+}
+
+func (h *Handler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, h.service.Unfollow, http.StatusNoContent)
+}
+
+func (h *Handler) Block(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, h.service.Block, http.StatusOK)
+}
+
+func (h *Handler) Unblock(w http.ResponseWriter, r *http.Request) {
+	h.act(w, r, h.service.Unblock, http.StatusNoContent)
+}
+
+// GetBlocked выводит пользователей, заблокированных текущим
+func (h *Handler) GetBlocked(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list, err := h.service.GetBlocked(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get blocked users")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, list)
+}
+
+// act выполняет действие текущего пользователя над пользователем из пути;
+// при статусе 200 в ответ выводится обновлённый профиль
+func (h *Handler) act(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, actorID, userID int) error,
+	status int,
+) {
+	s := session.FromContext(r.Context())
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if err = action(r.Context(), s.UserID, id); err != nil {
+		h.renderServiceError(w, r, err, "failed to update relation")
+		return
+	}
+
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+
+	profile, err := h.service.GetProfile(r.Context(), s.UserID, id)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get profile")
+		return
+	}
+
+	utilites.RenderJSON(w, r, status, *profile)
+}
+
+func (h *Handler) renderList(
+	w http.ResponseWriter,
+	r *http.Request,
+	list func(ctx context.Context, userID int, req dto.ListRequest) ([]dto.UserResponse, error),
+) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	users, err := list(r.Context(), id, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get users")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, users)
+}
+
+func (h *Handler) renderServiceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrSelf):
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrBlocked):
+		utilites.RenderError(w, r, http.StatusForbidden, err.Error())
+	default:
+		h.logger.Error(msg, "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, msg)
+	}
+}
+
+func parseListRequest(r *http.Request) (dto.ListRequest, error) {
+	var req dto.ListRequest
+
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid offset parameter")
+		}
+		req.Offset = offset
+	}
+
+	return req, req.Validate()
+}
@@ -0,0 +1,32 @@
+package follow
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+// RegisterRoutes монтируется на /api/users/{id}
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.With(middleware.OptionalAuthMiddleware).Get("/", handler.GetProfile)
+	r.Get("/followers", handler.GetFollowers)
+	r.Get("/following", handler.GetFollowing)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Put("/follow", handler.Follow)
+		r.Delete("/follow", handler.Unfollow)
+		r.Put("/block", handler.Block)
+		r.Delete("/block", handler.Unblock)
+	})
+}
+
+// RegisterProfileRoutes монтируется на /api/profile/blocked
+func RegisterProfileRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetBlocked)
+	})
+}
@@ -2,6 +2,7 @@ package genre
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -9,6 +10,7 @@ import (
 
 	domain "github.com/maYkiss56/tunes/internal/domain/genre"
 	"github.com/maYkiss56/tunes/internal/domain/genre/dto"
+	"github.com/maYkiss56/tunes/internal/domain/trash"
 	"github.com/maYkiss56/tunes/internal/logger"
 	"github.com/maYkiss56/tunes/internal/utilites"
 )
@@ -19,6 +21,7 @@ type GenreService interface {
 	GetGenreByID(ctx context.Context, id int) (*domain.Genre, error)
 	UpdateGenre(ctx context.Context, id int, update dto.UpdateGenreRequest) error
 	DeleteGenre(ctx context.Context, id int) error
+	RestoreGenre(ctx context.Context, id int) error
 }
 
 type Handler struct {
@@ -177,9 +180,34 @@ func (h *Handler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.DeleteGenre(r.Context(), id); err != nil {
 		h.logger.Error("failed to delete genre", "error", err)
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to delete genre")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *Handler) RestoreGenre(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("invalid genre id", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid genre id")
+		return
+	}
+
+	if err := h.service.RestoreGenre(r.Context(), id); err != nil {
+		h.logger.Error("failed to restore genre", "error", err)
+		if errors.Is(err, trash.ErrNotInTrash) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to restore genre")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
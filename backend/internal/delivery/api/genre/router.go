@@ -24,6 +24,7 @@ func RegisterAdminRoutes(r chi.Router, handler *Handler) {
 		r.Route("/{id}", func(r chi.Router) {
 			r.Patch("/", handler.UpdateGenre)
 			r.Delete("/", handler.DeleteGenre)
+			r.Post("/restore", handler.RestoreGenre)
 		})
 	})
 }
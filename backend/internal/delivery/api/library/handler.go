@@ -0,0 +1,165 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/library"
+	"github.com/maYkiss56/tunes/internal/domain/library/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type LibraryService interface {
+	GetLibrary(ctx context.Context, userID int, req dto.ListRequest) ([]dto.ItemResponse, error)
+	GetStatus(ctx context.Context, userID int, kind domain.Kind, id int) (*dto.StatusResponse, error)
+	AddToShelf(ctx context.Context, userID int, kind domain.Kind, id int, shelf domain.Shelf) (*dto.StatusResponse, error)
+	RemoveFromShelf(ctx context.Context, userID int, kind domain.Kind, id int, shelf domain.Shelf) (*dto.StatusResponse, error)
+}
+
+type Handler struct {
+	service LibraryService
+	logger  *logger.Logger
+}
+
+func NewHandler(service LibraryService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetLibrary выводит библиотеку текущего пользователя; ?shelf и ?kind сужают выборку
+func (h *Handler) GetLibrary(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.service.GetLibrary(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get library")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, items)
+}
+
+func (h *Handler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	kind, id, err := parseTarget(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.GetStatus(r.Context(), s.UserID, kind, id)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get library status")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) AddToShelf(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	kind, id, err := parseTarget(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.AddToShelf(r.Context(), s.UserID, kind, id, domain.Shelf(chi.URLParam(r, "shelf")))
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to add to library")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) RemoveFromShelf(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	kind, id, err := parseTarget(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.RemoveFromShelf(r.Context(), s.UserID, kind, id, domain.Shelf(chi.URLParam(r, "shelf")))
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to remove from library")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) renderServiceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrTargetNotFound):
+		utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidShelf),
+		errors.Is(err, domain.ErrInvalidKind):
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(msg, "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, msg)
+	}
+}
+
+// parseTarget разбирает /{kind}/{id}, где kind во множественном числе
+func parseTarget(r *http.Request) (domain.Kind, int, error) {
+	var kind domain.Kind
+	switch chi.URLParam(r, "kind") {
+	case "songs":
+		kind = domain.KindSong
+	case "albums":
+		kind = domain.KindAlbum
+	default:
+		return "", 0, errors.New("kind must be songs or albums")
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		return "", 0, errors.New("invalid id")
+	}
+
+	return kind, id, nil
+}
+
+func parseListRequest(r *http.Request) (dto.ListRequest, error) {
+	var req dto.ListRequest
+
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid offset parameter")
+		}
+		req.Offset = offset
+	}
+	req.Shelf = domain.Shelf(q.Get("shelf"))
+	req.Kind = domain.Kind(q.Get("kind"))
+
+	return req, req.Validate()
+}
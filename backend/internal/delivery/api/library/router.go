@@ -0,0 +1,20 @@
+package library
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+// RegisterRoutes монтируется на /api/profile/library; {kind} — songs или albums,
+// {shelf} — favorite, want или listened
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetLibrary)
+		r.Get("/{kind}/{id}", handler.GetStatus)
+		r.Put("/{kind}/{id}/{shelf}", handler.AddToShelf)
+		r.Delete("/{kind}/{id}/{shelf}", handler.RemoveFromShelf)
+	})
+}
@@ -0,0 +1,54 @@
+package lookup
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/maYkiss56/tunes/internal/domain/lookup/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type LookupService interface {
+	Lookup(ctx context.Context, req dto.Request) ([]dto.Response, error)
+}
+
+type Handler struct {
+	service LookupService
+	logger  *logger.Logger
+}
+
+func NewHandler(service LookupService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) Lookup(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := dto.Request{
+		ISRC: q.Get("isrc"),
+		UPC:  q.Get("upc"),
+		MBID: q.Get("mbid"),
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.Lookup(r.Context(), req)
+	if err != nil {
+		h.logger.Error("failed to lookup by external id", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to lookup")
+		return
+	}
+
+	if len(results) == 0 {
+		utilites.RenderError(w, r, http.StatusNotFound, "nothing found")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, results)
+}
@@ -0,0 +1,9 @@
+package lookup
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterPublicRoutes(r chi.Router, handler *Handler) {
+	r.Get("/", handler.Lookup)
+}
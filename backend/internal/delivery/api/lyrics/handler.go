@@ -0,0 +1,111 @@
+package lyrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/lyrics"
+	"github.com/maYkiss56/tunes/internal/domain/lyrics/dto"
+	"github.com/maYkiss56/tunes/internal/domain/slug"
+	"github.com/maYkiss56/tunes/internal/domain/song"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type LyricsService interface {
+	GetLyrics(ctx context.Context, songRef string) (*dto.Response, error)
+	UpsertLyrics(ctx context.Context, songID int, req dto.UpsertLyricsRequest) (*dto.Response, error)
+	DeleteLyrics(ctx context.Context, songID int) error
+}
+
+type Handler struct {
+	service LyricsService
+	logger  *logger.Logger
+}
+
+func NewHandler(service LyricsService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) GetLyrics(w http.ResponseWriter, r *http.Request) {
+	res, err := h.service.GetLyrics(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		var moved *slug.MovedError
+		switch {
+		case errors.As(err, &moved):
+			http.Redirect(w, r, "/api/songs/"+moved.Slug+"/lyrics", http.StatusMovedPermanently)
+		case errors.Is(err, song.ErrNotFound), errors.Is(err, domain.ErrNotFound):
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+		default:
+			h.logger.Error("failed to get lyrics", "error", err)
+			utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get lyrics")
+		}
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) UpsertLyrics(w http.ResponseWriter, r *http.Request) {
+	songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("invalid song id", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid song id")
+		return
+	}
+
+	var req dto.UpsertLyricsRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.UpsertLyrics(r.Context(), songID, req)
+	if err != nil {
+		if errors.Is(err, song.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		h.logger.Error("failed to save lyrics", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to save lyrics")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) DeleteLyrics(w http.ResponseWriter, r *http.Request) {
+	songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("invalid song id", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid song id")
+		return
+	}
+
+	if err = h.service.DeleteLyrics(r.Context(), songID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		h.logger.Error("failed to delete lyrics", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to delete lyrics")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,21 @@
+package lyrics
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+func RegisterPublicRoutes(r chi.Router, handler *Handler) {
+	r.Get("/", handler.GetLyrics)
+}
+
+func RegisterAdminRoutes(r chi.Router, handler *Handler) {
+	r.Route("/", func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.AdminOnlyMiddleware)
+
+		r.Put("/", handler.UpsertLyrics)
+		r.Delete("/", handler.DeleteLyrics)
+	})
+}
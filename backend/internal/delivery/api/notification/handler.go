@@ -0,0 +1,152 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/notification"
+	"github.com/maYkiss56/tunes/internal/domain/notification/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type NotificationService interface {
+	GetNotifications(ctx context.Context, userID int, req dto.ListRequest) ([]dto.Response, error)
+	GetNotificationsAfter(ctx context.Context, userID, afterID int) ([]dto.Response, error)
+	CountUnread(ctx context.Context, userID int) (*dto.UnreadResponse, error)
+	MarkRead(ctx context.Context, userID int, req dto.ReadRequest) (*dto.ReadResponse, error)
+	MarkOneRead(ctx context.Context, userID, id int) error
+	Subscribe(userID int) (<-chan dto.Response, func(), error)
+}
+
+type Handler struct {
+	service NotificationService
+	logger  *logger.Logger
+}
+
+func NewHandler(service NotificationService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetNotifications выводит уведомления текущего пользователя, новые первыми;
+// ?unread=true оставляет непрочитанные, ?type= один вид
+func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notifications, err := h.service.GetNotifications(r.Context(), s.UserID, req)
+	if err != nil {
+		h.logger.Error("failed to get notifications", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get notifications")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, notifications)
+}
+
+func (h *Handler) CountUnread(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	res, err := h.service.CountUnread(r.Context(), s.UserID)
+	if err != nil {
+		h.logger.Error("failed to count unread notifications", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to count unread notifications")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+// MarkRead отмечает прочитанными уведомления из {"ids": [...]}; без тела или с пустым списком все
+func (h *Handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	var req dto.ReadRequest
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.MarkRead(r.Context(), s.UserID, req)
+	if err != nil {
+		h.logger.Error("failed to mark notifications read", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to mark notifications read")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) MarkOneRead(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid notification id")
+		return
+	}
+
+	if err = h.service.MarkOneRead(r.Context(), s.UserID, id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		h.logger.Error("failed to mark notification read", "id", id, "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to mark notification read")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseListRequest(r *http.Request) (dto.ListRequest, error) {
+	var req dto.ListRequest
+
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid offset parameter")
+		}
+		req.Offset = offset
+	}
+	if v := q.Get("unread"); v != "" {
+		unread, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, errors.New("invalid unread parameter")
+		}
+		req.Unread = unread
+	}
+	req.Type = domain.Type(q.Get("type"))
+
+	return req, req.Validate()
+}
@@ -0,0 +1,20 @@
+package notification
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+// RegisterRoutes монтируется на /api/notifications
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetNotifications)
+		r.Get("/unread-count", handler.CountUnread)
+		r.Get("/stream", handler.Stream)
+		r.Post("/read", handler.MarkRead)
+		r.Post("/{id}/read", handler.MarkOneRead)
+	})
+}
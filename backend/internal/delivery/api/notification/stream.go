@@ -0,0 +1,137 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/notification"
+	"github.com/maYkiss56/tunes/internal/domain/notification/dto"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+const (
+	// heartbeatInterval комментарий держит соединение через прокси и проверяет сессию
+	heartbeatInterval = 25 * time.Second
+	// retryMillis через столько браузер переподключает оборванный поток
+	retryMillis = 3000
+)
+
+// Stream отдаёт новые уведомления текущего пользователя как Server-Sent Events.
+// Id события равен id уведомления: браузер передаёт последний в Last-Event-ID
+// при переподключении, и пропущенные уведомления досылаются первыми.
+// Поток закрывается при выходе из системы и по истечении сессии.
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	lastID := 0
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			utilites.RenderError(w, r, http.StatusBadRequest, "invalid Last-Event-ID header")
+			return
+		}
+		lastID = id
+	}
+
+	// подписка до досылки, чтобы не потерять созданное между ними
+	events, cancel, err := h.service.Subscribe(s.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTooManyStreams) {
+			utilites.RenderError(w, r, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		h.logger.Error("failed to open notification stream", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to open notification stream")
+		return
+	}
+	defer cancel()
+
+	var missed []dto.Response
+	if lastID > 0 {
+		if missed, err = h.service.GetNotificationsAfter(r.Context(), s.UserID, lastID); err != nil {
+			h.logger.Error("failed to get missed notifications", "error", err)
+			utilites.RenderError(w, r, http.StatusInternalServerError, "failed to open notification stream")
+			return
+		}
+	}
+
+	// у потока нет общего срока записи, иначе сервер оборвёт его по WriteTimeout
+	rc := http.NewResponseController(w)
+	if err = rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Error("failed to reset write deadline", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+
+	sent := make(map[int]bool, len(missed))
+	for _, n := range missed {
+		if err = writeEvent(w, n); err != nil {
+			return
+		}
+		sent[n.ID] = true
+	}
+	if err = rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	expired := time.NewTimer(time.Until(s.ExpiresAt))
+	defer expired.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-expired.C:
+			return
+
+		case <-heartbeat.C:
+			if current, ok := session.GetSession(s.ID); !ok || current.ExpiresAt.Before(time.Now()) {
+				return
+			}
+			if _, err = fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+
+		case n, ok := <-events:
+			if !ok {
+				// поток закрыт сервером, браузер переподключится с Last-Event-ID
+				return
+			}
+			if sent[n.ID] {
+				continue
+			}
+			if err = writeEvent(w, n); err != nil {
+				return
+			}
+		}
+
+		if err = rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, n dto.Response) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", n.ID, data)
+	return err
+}
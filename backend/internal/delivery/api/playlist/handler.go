@@ -0,0 +1,433 @@
+package playlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/playlist"
+	"github.com/maYkiss56/tunes/internal/domain/playlist/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type PlaylistService interface {
+	CreateList(ctx context.Context, userID int, req dto.CreateRequest) (*dto.Response, error)
+	GetLists(ctx context.Context, req dto.ListRequest) ([]dto.Response, error)
+	GetUserLists(ctx context.Context, userID int, req dto.ListRequest) ([]dto.Response, error)
+	GetList(ctx context.Context, viewerID, id int) (*dto.Response, error)
+	UpdateList(ctx context.Context, userID, id int, req dto.UpdateRequest) (*dto.Response, error)
+	DeleteList(ctx context.Context, userID, id int) error
+	AddItem(ctx context.Context, userID, id int, req dto.AddItemRequest) (*dto.ItemResponse, error)
+	UpdateItem(ctx context.Context, userID, id, itemID int, req dto.UpdateItemRequest) (*dto.ItemResponse, error)
+	DeleteItem(ctx context.Context, userID, id, itemID int) error
+	Reorder(ctx context.Context, userID, id int, req dto.OrderRequest) ([]dto.ItemResponse, error)
+	LikeList(ctx context.Context, userID, id int) (*dto.LikeResponse, error)
+	UnlikeList(ctx context.Context, userID, id int) (*dto.LikeResponse, error)
+	GetEditors(ctx context.Context, viewerID, id int) ([]dto.UserResponse, error)
+	AddEditor(ctx context.Context, userID, id, editorID int) ([]dto.UserResponse, error)
+	RemoveEditor(ctx context.Context, userID, id, editorID int) error
+}
+
+type Handler struct {
+	service PlaylistService
+	logger  *logger.Logger
+}
+
+func NewHandler(service PlaylistService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetLists выводит публичные списки; ?sort=popular по отметкам,
+// ?user_id=, ?song_id= и ?album_id= сужают подборку
+func (h *Handler) GetLists(w http.ResponseWriter, r *http.Request) {
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lists, err := h.service.GetLists(r.Context(), req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get lists")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, lists)
+}
+
+// GetMyLists выводит списки текущего пользователя вместе со скрытыми и совместными
+func (h *Handler) GetMyLists(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	lists, err := h.service.GetUserLists(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get lists")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, lists)
+}
+
+func (h *Handler) CreateList(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	var req dto.CreateRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.CreateList(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to create list")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusCreated, *res)
+}
+
+// GetList открывает список с позициями; закрытый виден только владельцу и соавторам
+func (h *Handler) GetList(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+
+	var viewerID int
+	if s := session.FromContext(r.Context()); s != nil {
+		viewerID = s.UserID
+	}
+
+	res, err := h.service.GetList(r.Context(), viewerID, id)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get list")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.UpdateList(r.Context(), s.UserID, id, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to update list")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteList(r.Context(), s.UserID, id); err != nil {
+		h.renderServiceError(w, r, err, "failed to delete list")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) AddItem(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+
+	var req dto.AddItemRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.AddItem(r.Context(), s.UserID, id, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to add list item")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusCreated, *res)
+}
+
+func (h *Handler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+	itemID, ok := parseID(w, r, "itemID", "invalid item id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateItemRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.UpdateItem(r.Context(), s.UserID, id, itemID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to update list item")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+	itemID, ok := parseID(w, r, "itemID", "invalid item id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteItem(r.Context(), s.UserID, id, itemID); err != nil {
+		h.renderServiceError(w, r, err, "failed to delete list item")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reorder принимает {"item_ids": [...]} со всеми позициями списка в новом порядке
+func (h *Handler) Reorder(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+
+	var req dto.OrderRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, err := h.service.Reorder(r.Context(), s.UserID, id, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to reorder list")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, items)
+}
+
+func (h *Handler) LikeList(w http.ResponseWriter, r *http.Request) {
+	h.like(w, r, h.service.LikeList, "failed to like list")
+}
+
+func (h *Handler) UnlikeList(w http.ResponseWriter, r *http.Request) {
+	h.like(w, r, h.service.UnlikeList, "failed to unlike list")
+}
+
+func (h *Handler) GetEditors(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+
+	var viewerID int
+	if s := session.FromContext(r.Context()); s != nil {
+		viewerID = s.UserID
+	}
+
+	editors, err := h.service.GetEditors(r.Context(), viewerID, id)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get list editors")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, editors)
+}
+
+func (h *Handler) AddEditor(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+	editorID, ok := parseID(w, r, "userID", "invalid user id")
+	if !ok {
+		return
+	}
+
+	editors, err := h.service.AddEditor(r.Context(), s.UserID, id, editorID)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to add list editor")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, editors)
+}
+
+// RemoveEditor убирает соавтора; соавтор может убрать из списка себя
+func (h *Handler) RemoveEditor(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+	editorID, ok := parseID(w, r, "userID", "invalid user id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveEditor(r.Context(), s.UserID, id, editorID); err != nil {
+		h.renderServiceError(w, r, err, "failed to remove list editor")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) like(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, userID, id int) (*dto.LikeResponse, error),
+	msg string,
+) {
+	s := session.FromContext(r.Context())
+
+	id, ok := parseID(w, r, "id", "invalid list id")
+	if !ok {
+		return
+	}
+
+	res, err := action(r.Context(), s.UserID, id)
+	if err != nil {
+		h.renderServiceError(w, r, err, msg)
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
+
+func (h *Handler) renderServiceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrItemNotFound),
+		errors.Is(err, domain.ErrTargetNotFound),
+		errors.Is(err, domain.ErrUserNotFound):
+		utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrBlocked):
+		utilites.RenderError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrItemExists),
+		errors.Is(err, domain.ErrOwnerEditor),
+		errors.Is(err, domain.ErrTooManyItems):
+		utilites.RenderError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrInvalidOrder):
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(msg, "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, msg)
+	}
+}
+
+func parseID(w http.ResponseWriter, r *http.Request, param, msg string) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, param))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, msg)
+		return 0, false
+	}
+	return id, true
+}
+
+func parseListRequest(r *http.Request) (dto.ListRequest, error) {
+	var req dto.ListRequest
+
+	q := r.URL.Query()
+	for name, dst := range map[string]*int{
+		"limit":    &req.Limit,
+		"offset":   &req.Offset,
+		"user_id":  &req.UserID,
+		"song_id":  &req.SongID,
+		"album_id": &req.AlbumID,
+	} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return req, errors.New("invalid " + name + " parameter")
+			}
+			*dst = n
+		}
+	}
+	req.Sort = q.Get("sort")
+
+	return req, req.Validate()
+}
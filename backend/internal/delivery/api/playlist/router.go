@@ -0,0 +1,47 @@
+package playlist
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+// RegisterRoutes монтируется на /api/lists
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Get("/", handler.GetLists)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.OptionalAuthMiddleware)
+
+		r.Get("/{id}", handler.GetList)
+		r.Get("/{id}/editors", handler.GetEditors)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Post("/", handler.CreateList)
+		r.Patch("/{id}", handler.UpdateList)
+		r.Delete("/{id}", handler.DeleteList)
+
+		r.Post("/{id}/items", handler.AddItem)
+		r.Put("/{id}/items/order", handler.Reorder)
+		r.Patch("/{id}/items/{itemID}", handler.UpdateItem)
+		r.Delete("/{id}/items/{itemID}", handler.DeleteItem)
+
+		r.Put("/{id}/like", handler.LikeList)
+		r.Delete("/{id}/like", handler.UnlikeList)
+
+		r.Put("/{id}/editors/{userID}", handler.AddEditor)
+		r.Delete("/{id}/editors/{userID}", handler.RemoveEditor)
+	})
+}
+
+// RegisterProfileRoutes монтируется на /api/profile/lists
+func RegisterProfileRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetMyLists)
+	})
+}
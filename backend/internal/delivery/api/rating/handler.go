@@ -0,0 +1,56 @@
+package rating
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/rating"
+	"github.com/maYkiss56/tunes/internal/domain/rating/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type RatingService interface {
+	Check(ctx context.Context, fix bool) (*domain.CheckResult, error)
+	Metrics() string
+}
+
+type Handler struct {
+	service RatingService
+	logger  *logger.Logger
+}
+
+func NewHandler(service RatingService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetDrift только сообщает о расхождениях счётчиков
+func (h *Handler) GetDrift(w http.ResponseWriter, r *http.Request) {
+	h.check(w, r, false)
+}
+
+// Repair пересчитывает разошедшиеся счётчики
+func (h *Handler) Repair(w http.ResponseWriter, r *http.Request) {
+	h.check(w, r, true)
+}
+
+func (h *Handler) check(w http.ResponseWriter, r *http.Request, fix bool) {
+	res, err := h.service.Check(r.Context(), fix)
+	if err != nil {
+		h.logger.Error("failed to check counters", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to check counters")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, dto.ToCheckResponse(*res))
+}
+
+func (h *Handler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, h.service.Metrics())
+}
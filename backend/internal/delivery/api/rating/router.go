@@ -0,0 +1,18 @@
+package rating
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+func RegisterAdminRoutes(r chi.Router, handler *Handler) {
+	r.Route("/", func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.AdminOnlyMiddleware)
+
+		r.Get("/drift", handler.GetDrift)
+		r.Post("/repair", handler.Repair)
+		r.Get("/metrics", handler.GetMetrics)
+	})
+}
@@ -0,0 +1,180 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/release"
+	"github.com/maYkiss56/tunes/internal/domain/release/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type ReleaseService interface {
+	FollowArtist(ctx context.Context, userID, artistID int) (*dto.FollowResponse, error)
+	UnfollowArtist(ctx context.Context, userID, artistID int) error
+	GetArtistFollow(ctx context.Context, userID, artistID int) (*dto.FollowResponse, error)
+	GetReleases(ctx context.Context, userID int, req dto.ListRequest) ([]dto.Response, error)
+	GetDigest(ctx context.Context, userID int) (*dto.DigestResponse, error)
+	SetDigest(ctx context.Context, userID int, req dto.DigestRequest) (*dto.DigestResponse, error)
+}
+
+type Handler struct {
+	service ReleaseService
+	logger  *logger.Logger
+}
+
+func NewHandler(service ReleaseService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) GetArtistFollow(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	artistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid artist id")
+		return
+	}
+
+	res, err := h.service.GetArtistFollow(r.Context(), s.UserID, artistID)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get artist follow")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) FollowArtist(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	artistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid artist id")
+		return
+	}
+
+	res, err := h.service.FollowArtist(r.Context(), s.UserID, artistID)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to follow artist")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) UnfollowArtist(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	artistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid artist id")
+		return
+	}
+
+	if err = h.service.UnfollowArtist(r.Context(), s.UserID, artistID); err != nil {
+		h.renderServiceError(w, r, err, "failed to unfollow artist")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetReleases выводит свежие релизы исполнителей из подписок текущего пользователя
+func (h *Handler) GetReleases(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	releases, err := h.service.GetReleases(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get releases")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, releases)
+}
+
+func (h *Handler) GetDigest(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	res, err := h.service.GetDigest(r.Context(), s.UserID)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get digest settings")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+// SetDigest меняет частоту писем со сводкой релизов: off, daily или weekly
+func (h *Handler) SetDigest(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	var req dto.DigestRequest
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.SetDigest(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to update digest settings")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) renderServiceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrArtistNotFound):
+		utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+	default:
+		h.logger.Error(msg, "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, msg)
+	}
+}
+
+func parseListRequest(r *http.Request) (dto.ListRequest, error) {
+	var req dto.ListRequest
+
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid offset parameter")
+		}
+		req.Offset = offset
+	}
+
+	return req, req.Validate()
+}
@@ -0,0 +1,29 @@
+package release
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+// RegisterArtistRoutes монтируется на /api/artists/{id}/follow
+func RegisterArtistRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetArtistFollow)
+		r.Put("/", handler.FollowArtist)
+		r.Delete("/", handler.UnfollowArtist)
+	})
+}
+
+// RegisterProfileRoutes монтируется на /api/profile/releases
+func RegisterProfileRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetReleases)
+		r.Get("/digest", handler.GetDigest)
+		r.Put("/digest", handler.SetDigest)
+	})
+}
@@ -3,11 +3,16 @@ package review
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/maYkiss56/tunes/internal/domain/mention"
 	domain "github.com/maYkiss56/tunes/internal/domain/review"
 	"github.com/maYkiss56/tunes/internal/domain/review/dto"
 	"github.com/maYkiss56/tunes/internal/logger"
@@ -17,11 +22,21 @@ import (
 
 type ReviewService interface {
 	CreateReview(ctx context.Context, review *domain.Review) error
-	GetAllReviews(ctx context.Context) ([]dto.Response, error)
+	UpsertReview(ctx context.Context, review *domain.Review) (bool, error)
+	GetAllReviews(ctx context.Context, sort string) ([]dto.Response, error)
+	GetReviewsByTarget(ctx context.Context, target domain.Target, sort string) ([]dto.Response, error)
+	GetReviewsMentioning(ctx context.Context, kind mention.Kind, id int) ([]dto.Response, error)
 	GetAllReviewsByUserID(ctx context.Context, id int) ([]dto.Response, error)
 	GetReviewByID(ctx context.Context, id int) (*dto.Response, error)
 	UpdateReview(ctx context.Context, id int, update dto.UpdateReviewRequest) error
 	DeleteReview(ctx context.Context, id int) error
+	VoteReview(ctx context.Context, reviewID, userID int, helpful *bool) (*dto.Response, error)
+	ReactToReview(ctx context.Context, reviewID, userID int, emoji string) (*dto.Response, error)
+	GetHistory(ctx context.Context, id int) (*dto.HistoryResponse, error)
+	RestoreRevision(ctx context.Context, id, revision int) (*dto.Response, error)
+	ModerateReview(ctx context.Context, id int, req dto.ModerateReviewRequest) (*dto.Response, error)
+	GetDrafts(ctx context.Context, userID int) ([]dto.Response, error)
+	PublishReview(ctx context.Context, id, userID int, at *time.Time) (*dto.Response, error)
 }
 
 type Handler struct {
@@ -54,14 +69,22 @@ func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newReview, err := domain.NewReview(req.UserID, req.SongID, req.Body, req.IsLike, req.IsValid)
+	target, _ := req.Target()
+
+	newReview, err := domain.NewReview(req.UserID, target, req.Body, req.IsLike, req.IsValid, req.Score)
 	if err != nil {
 		h.logger.Error("invalid input review", "error", err)
 		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
+	newReview.Stage(req.Draft, req.PublishAt, time.Now())
 
 	if err := h.service.CreateReview(r.Context(), newReview); err != nil {
+		var exists *domain.ExistsError
+		if errors.As(err, &exists) {
+			renderExists(w, r, exists)
+			return
+		}
 		h.logger.Error("failed to create review", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to create review")
 		return
@@ -70,8 +93,133 @@ func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
 	utilites.RenderJSON(w, r, http.StatusCreated, *newReview)
 }
 
+// GetTargetReviews выводит рецензии на объект вида t из параметра пути id
+func (h *Handler) GetTargetReviews(t domain.TargetType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utilites.RenderError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid %s id", t))
+			return
+		}
+
+		sort := r.URL.Query().Get("sort")
+		if !domain.IsValidSort(sort) {
+			utilites.RenderError(w, r, http.StatusBadRequest, domain.ErrInvalidSort.Error())
+			return
+		}
+
+		reviews, err := h.service.GetReviewsByTarget(r.Context(), domain.Target{Type: t, ID: id}, sort)
+		if err != nil {
+			h.logger.Error("failed to get reviews", "target", t, "id", id, "error", err)
+			utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get reviews")
+			return
+		}
+
+		utilites.RenderJSON(w, r, http.StatusOK, reviews)
+	}
+}
+
+// GetMentioningReviews выводит рецензии, упоминающие сущность вида kind из параметра пути id
+func (h *Handler) GetMentioningReviews(kind mention.Kind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utilites.RenderError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid %s id", kind))
+			return
+		}
+
+		reviews, err := h.service.GetReviewsMentioning(r.Context(), kind, id)
+		if err != nil {
+			h.logger.Error("failed to get mentioning reviews", "kind", kind, "id", id, "error", err)
+			utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get reviews")
+			return
+		}
+
+		utilites.RenderJSON(w, r, http.StatusOK, reviews)
+	}
+}
+
+// UpsertMyReview создаёт или перезаписывает рецензию текущего пользователя
+// на объект вида t из параметра пути id
+func (h *Handler) UpsertMyReview(t domain.TargetType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.upsertMyReview(w, r, t)
+	}
+}
+
+func (h *Handler) upsertMyReview(w http.ResponseWriter, r *http.Request, t domain.TargetType) {
+	s := session.FromContext(r.Context())
+
+	targetID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("invalid target id", "target", t, "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid %s id", t))
+		return
+	}
+
+	var req dto.CreateReviewRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	target := domain.Target{Type: t, ID: targetID}
+	req.UserID = s.UserID
+	req.SetTarget(target)
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	review, err := domain.NewReview(req.UserID, target, req.Body, req.IsLike, req.IsValid, req.Score)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	// у существующей рецензии стадия публикации не меняется
+	review.Stage(req.Draft, req.PublishAt, time.Now())
+
+	created, err := h.service.UpsertReview(r.Context(), review)
+	if err != nil {
+		h.logger.Error("failed to save review", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to save review")
+		return
+	}
+
+	res, err := h.service.GetReviewByID(r.Context(), review.ID)
+	if err != nil {
+		h.logger.Error("failed to get review", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get review")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	utilites.RenderJSON(w, r, status, *res)
+}
+
+// renderExists отвечает 409 со ссылкой на уже существующую рецензию
+func renderExists(w http.ResponseWriter, r *http.Request, exists *domain.ExistsError) {
+	w.Header().Set("Location", fmt.Sprintf("/api/reviews/%d", exists.ID))
+	utilites.RenderJSON(w, r, http.StatusConflict, map[string]any{
+		"error":     "review already exists",
+		"review_id": exists.ID,
+	})
+}
+
 func (h *Handler) GetAllReviews(w http.ResponseWriter, r *http.Request) {
-	reviews, err := h.service.GetAllReviews(r.Context())
+	sort := r.URL.Query().Get("sort")
+	if !domain.IsValidSort(sort) {
+		utilites.RenderError(w, r, http.StatusBadRequest, domain.ErrInvalidSort.Error())
+		return
+	}
+
+	reviews, err := h.service.GetAllReviews(r.Context(), sort)
 	if err != nil {
 		h.logger.Error("failed to get reviews", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get reviews")
@@ -114,8 +262,7 @@ func (h *Handler) GetReviewByID(w http.ResponseWriter, r *http.Request) {
 
 	review, err := h.service.GetReviewByID(r.Context(), id)
 	if err != nil {
-		h.logger.Error("failed to get review by id", "error", err)
-		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get review by id")
+		h.renderServiceError(w, r, err, "failed to get review by id")
 		return
 	}
 
@@ -138,9 +285,13 @@ func (h *Handler) UpdateReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if err = h.service.UpdateReview(r.Context(), id, req); err != nil {
-		h.logger.Error("failed to update review", "error", err)
-		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to update review")
+		h.renderServiceError(w, r, err, "failed to update review")
 		return
 	}
 
@@ -163,10 +314,223 @@ func (h *Handler) DeleteReview(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.DeleteReview(r.Context(), id); err != nil {
-		h.logger.Error("failed to delete review", "error", err)
-		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to delete review")
+		h.renderServiceError(w, r, err, "failed to delete review")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *Handler) VoteReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	var req dto.VoteRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.vote(w, r, id, req.Helpful)
+}
+
+func (h *Handler) DeleteVote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	h.vote(w, r, id, nil)
+}
+
+func (h *Handler) vote(w http.ResponseWriter, r *http.Request, id int, helpful *bool) {
+	s := session.FromContext(r.Context())
+
+	res, err := h.service.VoteReview(r.Context(), id, s.UserID, helpful)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to vote")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) ReactToReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	var req dto.ReactionRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.react(w, r, id, req.Emoji)
+}
+
+func (h *Handler) DeleteReaction(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	h.react(w, r, id, "")
+}
+
+func (h *Handler) react(w http.ResponseWriter, r *http.Request, id int, emoji string) {
+	s := session.FromContext(r.Context())
+
+	res, err := h.service.ReactToReview(r.Context(), id, s.UserID, emoji)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to save reaction")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) renderServiceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrRevisionNotFound):
+		utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrOwnReview), errors.Is(err, domain.ErrNotAuthor):
+		utilites.RenderError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrAlreadyPublished):
+		utilites.RenderError(w, r, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error(msg, "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, msg)
+	}
+}
+
+func (h *Handler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	res, err := h.service.GetHistory(r.Context(), id)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get review history")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	revision, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid revision")
+		return
+	}
+
+	res, err := h.service.RestoreRevision(r.Context(), id, revision)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to restore review revision")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+// ModerateReview одобряет или отклоняет рецензию, для отклонения нужна причина
+func (h *Handler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	var req dto.ModerateReviewRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.ModerateReview(r.Context(), id, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to moderate review")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+// GetDrafts выводит черновики и запланированные рецензии текущего пользователя
+func (h *Handler) GetDrafts(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	drafts, err := h.service.GetDrafts(r.Context(), s.UserID)
+	if err != nil {
+		h.logger.Error("failed to get drafts", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get drafts")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, drafts)
+}
+
+// PublishReview публикует черновик сразу или планирует публикацию на publish_at
+func (h *Handler) PublishReview(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	// тело необязательно
+	var req dto.PublishRequest
+	defer r.Body.Close()
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	res, err := h.service.PublishReview(r.Context(), id, s.UserID, req.PublishAt)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to publish review")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
@@ -3,12 +3,16 @@ package review
 import (
 	"github.com/go-chi/chi/v5"
 
+	"github.com/maYkiss56/tunes/internal/domain/mention"
+	domain "github.com/maYkiss56/tunes/internal/domain/review"
 	"github.com/maYkiss56/tunes/internal/middleware"
 )
 
 func RegisterPublicRoutes(r chi.Router, handler *Handler) {
 	r.Get("/", handler.GetAllReviews)
-	r.Get("/{id}", handler.GetReviewByID)
+	// автор видит свой черновик и его историю по id
+	r.With(middleware.OptionalAuthMiddleware).Get("/{id}", handler.GetReviewByID)
+	r.With(middleware.OptionalAuthMiddleware).Get("/{id}/history", handler.GetHistory)
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware)
@@ -16,6 +20,47 @@ func RegisterPublicRoutes(r chi.Router, handler *Handler) {
 		r.Post("/", handler.CreateReview)
 		r.Get("/user/{id}", handler.GetAllReviewsByUserID)
 		r.Patch("/{id}", handler.UpdateReview)
+		r.Post("/{id}/publish", handler.PublishReview)
 		r.Delete("/{id}", handler.DeleteReview)
+		r.Put("/{id}/vote", handler.VoteReview)
+		r.Delete("/{id}/vote", handler.DeleteVote)
+		r.Put("/{id}/reaction", handler.ReactToReview)
+		r.Delete("/{id}/reaction", handler.DeleteReaction)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.AdminOnlyMiddleware)
+
+		r.Post("/{id}/history/{revision}/restore", handler.RestoreRevision)
+		r.Post("/{id}/moderate", handler.ModerateReview)
+	})
+}
+
+// RegisterTargetRoutes монтируется на /api/{songs,albums,artists}/{id}/reviews
+func RegisterTargetRoutes(r chi.Router, handler *Handler, target domain.TargetType) {
+	r.Get("/", handler.GetTargetReviews(target))
+}
+
+// RegisterMentionRoutes монтируется на /api/{songs,albums,artists}/{id}/mentions
+func RegisterMentionRoutes(r chi.Router, handler *Handler, kind mention.Kind) {
+	r.Get("/", handler.GetMentioningReviews(kind))
+}
+
+// RegisterMyReviewRoutes монтируется на /api/{songs,albums,artists}/{id}/my-review
+func RegisterMyReviewRoutes(r chi.Router, handler *Handler, target domain.TargetType) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Put("/", handler.UpsertMyReview(target))
+	})
+}
+
+// RegisterProfileRoutes монтируется на /api/profile/drafts
+func RegisterProfileRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetDrafts)
 	})
 }
@@ -7,10 +7,26 @@ import (
 
 	albumHandler "github.com/maYkiss56/tunes/internal/delivery/api/album"
 	artistHandler "github.com/maYkiss56/tunes/internal/delivery/api/artist"
+	auditHandler "github.com/maYkiss56/tunes/internal/delivery/api/audit"
+	commentHandler "github.com/maYkiss56/tunes/internal/delivery/api/comment"
+	feedHandler "github.com/maYkiss56/tunes/internal/delivery/api/feed"
+	followHandler "github.com/maYkiss56/tunes/internal/delivery/api/follow"
 	genreHandler "github.com/maYkiss56/tunes/internal/delivery/api/genre"
+	libraryHandler "github.com/maYkiss56/tunes/internal/delivery/api/library"
+	lookupHandler "github.com/maYkiss56/tunes/internal/delivery/api/lookup"
+	lyricsHandler "github.com/maYkiss56/tunes/internal/delivery/api/lyrics"
+	notificationHandler "github.com/maYkiss56/tunes/internal/delivery/api/notification"
+	playlistHandler "github.com/maYkiss56/tunes/internal/delivery/api/playlist"
+	ratingHandler "github.com/maYkiss56/tunes/internal/delivery/api/rating"
+	releaseHandler "github.com/maYkiss56/tunes/internal/delivery/api/release"
 	reviewHandler "github.com/maYkiss56/tunes/internal/delivery/api/review"
+	scrobbleHandler "github.com/maYkiss56/tunes/internal/delivery/api/scrobble"
+	snapshotHandler "github.com/maYkiss56/tunes/internal/delivery/api/snapshot"
 	songHandler "github.com/maYkiss56/tunes/internal/delivery/api/song"
+	trashHandler "github.com/maYkiss56/tunes/internal/delivery/api/trash"
 	userHandler "github.com/maYkiss56/tunes/internal/delivery/api/user"
+	"github.com/maYkiss56/tunes/internal/domain/mention"
+	reviewDomain "github.com/maYkiss56/tunes/internal/domain/review"
 	"github.com/maYkiss56/tunes/internal/logger"
 	"github.com/maYkiss56/tunes/internal/middleware"
 )
@@ -22,6 +38,20 @@ func NewRouter(
 	album *albumHandler.Handler,
 	genre *genreHandler.Handler,
 	review *reviewHandler.Handler,
+	snapshot *snapshotHandler.Handler,
+	lookup *lookupHandler.Handler,
+	trash *trashHandler.Handler,
+	audit *auditHandler.Handler,
+	lyrics *lyricsHandler.Handler,
+	rating *ratingHandler.Handler,
+	comment *commentHandler.Handler,
+	notification *notificationHandler.Handler,
+	follow *followHandler.Handler,
+	feed *feedHandler.Handler,
+	release *releaseHandler.Handler,
+	playlist *playlistHandler.Handler,
+	scrobble *scrobbleHandler.Handler,
+	library *libraryHandler.Handler,
 	logger *logger.Logger,
 ) chi.Router {
 	r := chi.NewRouter()
@@ -42,6 +72,14 @@ func NewRouter(
 	songHandler.RegisterAdminRoutes(songAdminRouter, song)
 	r.Mount("/api/admin/songs", songAdminRouter)
 
+	lyricsRouter := chi.NewRouter()
+	lyricsHandler.RegisterPublicRoutes(lyricsRouter, lyrics)
+	r.Mount("/api/songs/{id}/lyrics", lyricsRouter)
+
+	lyricsAdminRouter := chi.NewRouter()
+	lyricsHandler.RegisterAdminRoutes(lyricsAdminRouter, lyrics)
+	r.Mount("/api/admin/songs/{id}/lyrics", lyricsAdminRouter)
+
 	artistRouter := chi.NewRouter()
 	artistHandler.RegisterPublicRoutes(artistRouter, artist)
 	r.Mount("/api/artists", artistRouter)
@@ -69,5 +107,99 @@ func NewRouter(
 	reviewRouter := chi.NewRouter()
 	reviewHandler.RegisterPublicRoutes(reviewRouter, review)
 	r.Mount("/api/reviews", reviewRouter)
+
+	for prefix, target := range map[string]reviewDomain.TargetType{
+		"/api/songs":   reviewDomain.TargetSong,
+		"/api/albums":  reviewDomain.TargetAlbum,
+		"/api/artists": reviewDomain.TargetArtist,
+	} {
+		targetReviewRouter := chi.NewRouter()
+		reviewHandler.RegisterTargetRoutes(targetReviewRouter, review, target)
+		r.Mount(prefix+"/{id}/reviews", targetReviewRouter)
+
+		mentionRouter := chi.NewRouter()
+		reviewHandler.RegisterMentionRoutes(mentionRouter, review, mention.Kind(target))
+		r.Mount(prefix+"/{id}/mentions", mentionRouter)
+
+		myReviewRouter := chi.NewRouter()
+		reviewHandler.RegisterMyReviewRoutes(myReviewRouter, review, target)
+		r.Mount(prefix+"/{id}/my-review", myReviewRouter)
+	}
+
+	draftRouter := chi.NewRouter()
+	reviewHandler.RegisterProfileRoutes(draftRouter, review)
+	r.Mount("/api/profile/drafts", draftRouter)
+
+	commentRouter := chi.NewRouter()
+	commentHandler.RegisterPublicRoutes(commentRouter, comment)
+	r.Mount("/api/reviews/{id}/comments", commentRouter)
+
+	commentAdminRouter := chi.NewRouter()
+	commentHandler.RegisterAdminRoutes(commentAdminRouter, comment)
+	r.Mount("/api/admin/comments", commentAdminRouter)
+
+	followRouter := chi.NewRouter()
+	followHandler.RegisterRoutes(followRouter, follow)
+	r.Mount("/api/users/{id}", followRouter)
+
+	blockedRouter := chi.NewRouter()
+	followHandler.RegisterProfileRoutes(blockedRouter, follow)
+	r.Mount("/api/profile/blocked", blockedRouter)
+
+	artistFollowRouter := chi.NewRouter()
+	releaseHandler.RegisterArtistRoutes(artistFollowRouter, release)
+	r.Mount("/api/artists/{id}/follow", artistFollowRouter)
+
+	releaseRouter := chi.NewRouter()
+	releaseHandler.RegisterProfileRoutes(releaseRouter, release)
+	r.Mount("/api/profile/releases", releaseRouter)
+
+	playlistRouter := chi.NewRouter()
+	playlistHandler.RegisterRoutes(playlistRouter, playlist)
+	r.Mount("/api/lists", playlistRouter)
+
+	myListsRouter := chi.NewRouter()
+	playlistHandler.RegisterProfileRoutes(myListsRouter, playlist)
+	r.Mount("/api/profile/lists", myListsRouter)
+
+	scrobbleRouter := chi.NewRouter()
+	scrobbleHandler.RegisterProtocolRoutes(scrobbleRouter, scrobble)
+	r.Mount("/2.0", scrobbleRouter)
+
+	listeningRouter := chi.NewRouter()
+	scrobbleHandler.RegisterProfileRoutes(listeningRouter, scrobble)
+	r.Mount("/api/profile/scrobbles", listeningRouter)
+
+	libraryRouter := chi.NewRouter()
+	libraryHandler.RegisterRoutes(libraryRouter, library)
+	r.Mount("/api/profile/library", libraryRouter)
+
+	feedRouter := chi.NewRouter()
+	feedHandler.RegisterRoutes(feedRouter, feed)
+	r.Mount("/api/feed", feedRouter)
+
+	notificationRouter := chi.NewRouter()
+	notificationHandler.RegisterRoutes(notificationRouter, notification)
+	r.Mount("/api/notifications", notificationRouter)
+
+	lookupRouter := chi.NewRouter()
+	lookupHandler.RegisterPublicRoutes(lookupRouter, lookup)
+	r.Mount("/api/lookup", lookupRouter)
+
+	snapshotAdminRouter := chi.NewRouter()
+	snapshotHandler.RegisterAdminRoutes(snapshotAdminRouter, snapshot)
+	r.Mount("/api/admin/export", snapshotAdminRouter)
+
+	trashAdminRouter := chi.NewRouter()
+	trashHandler.RegisterAdminRoutes(trashAdminRouter, trash)
+	r.Mount("/api/admin/trash", trashAdminRouter)
+
+	auditAdminRouter := chi.NewRouter()
+	auditHandler.RegisterAdminRoutes(auditAdminRouter, audit)
+	r.Mount("/api/admin/audit", auditAdminRouter)
+
+	ratingAdminRouter := chi.NewRouter()
+	ratingHandler.RegisterAdminRoutes(ratingAdminRouter, rating)
+	r.Mount("/api/admin/ratings", ratingAdminRouter)
 	return r
 }
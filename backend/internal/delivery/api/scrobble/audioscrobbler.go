@@ -0,0 +1,396 @@
+package scrobble
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/scrobble"
+)
+
+// Коды ошибок протокола Last.fm
+const (
+	lfmInvalidMethod    = 3
+	lfmAuthFailed       = 4
+	lfmInvalidParams    = 6
+	lfmOperationFailed  = 8
+	lfmInvalidSession   = 9
+	lfmInvalidAPIKey    = 10
+	lfmServiceOffline   = 11
+	lfmInvalidSignature = 13
+	lfmTokenNotApproved = 14
+	lfmTokenExpired     = 15
+)
+
+// maxFormSize пакет из 50 прослушиваний с запасом
+const maxFormSize = 1 << 20
+
+type lfmText struct {
+	Corrected string `xml:"corrected,attr" json:"corrected"`
+	Text      string `xml:",chardata" json:"#text"`
+}
+
+type lfmIgnored struct {
+	Code string `xml:"code,attr" json:"code"`
+	Text string `xml:",chardata" json:"#text"`
+}
+
+type lfmTrack struct {
+	Track          lfmText    `xml:"track" json:"track"`
+	Artist         lfmText    `xml:"artist" json:"artist"`
+	Album          lfmText    `xml:"album" json:"album"`
+	AlbumArtist    lfmText    `xml:"albumArtist" json:"albumArtist"`
+	Timestamp      string     `xml:"timestamp,omitempty" json:"timestamp,omitempty"`
+	IgnoredMessage lfmIgnored `xml:"ignoredMessage" json:"ignoredMessage"`
+}
+
+type lfmScrobbles struct {
+	Accepted int        `xml:"accepted,attr"`
+	Ignored  int        `xml:"ignored,attr"`
+	Scrobble []lfmTrack `xml:"scrobble"`
+}
+
+type lfmSession struct {
+	Name       string `xml:"name" json:"name"`
+	Key        string `xml:"key" json:"key"`
+	Subscriber int    `xml:"subscriber" json:"subscriber"`
+}
+
+type lfmError struct {
+	Code    int    `xml:"code,attr"`
+	Message string `xml:",chardata"`
+}
+
+// lfmResponse ответ в XML, как его отдаёт Last.fm без format=json
+type lfmResponse struct {
+	XMLName    xml.Name      `xml:"lfm"`
+	Status     string        `xml:"status,attr"`
+	Error      *lfmError     `xml:"error,omitempty"`
+	Token      string        `xml:"token,omitempty"`
+	Session    *lfmSession   `xml:"session,omitempty"`
+	Scrobbles  *lfmScrobbles `xml:"scrobbles,omitempty"`
+	NowPlaying *lfmTrack     `xml:"nowplaying,omitempty"`
+}
+
+// Audioscrobbler принимает запросы скробблеров по протоколу Last.fm API 2.0:
+// auth.getToken, auth.getSession, auth.getMobileSession, track.scrobble и
+// track.updateNowPlaying. Без format=json ответ отдаётся в XML.
+func (h *Handler) Audioscrobbler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormSize)
+	if err := r.ParseForm(); err != nil {
+		renderLFMError(w, false, lfmInvalidParams, "Invalid parameters - malformed request")
+		return
+	}
+
+	params := make(map[string]string, len(r.Form))
+	for name, values := range r.Form {
+		params[name] = values[0]
+	}
+	asJSON := params["format"] == "json"
+
+	if !h.service.Enabled() {
+		renderLFMError(w, asJSON, lfmServiceOffline, "Service Offline - scrobbling is not configured on this server")
+		return
+	}
+	if params["api_key"] == "" {
+		renderLFMError(w, asJSON, lfmInvalidAPIKey, "Invalid API key - You must be granted a valid key")
+		return
+	}
+	if !h.service.CheckSignature(params) {
+		renderLFMError(w, asJSON, lfmInvalidSignature, "Invalid method signature supplied")
+		return
+	}
+
+	method := strings.ToLower(params["method"])
+	if method != "auth.gettoken" && method != "auth.getsession" && r.Method != http.MethodPost {
+		renderLFMError(w, asJSON, lfmInvalidMethod, "Invalid Method - this method must be called with POST")
+		return
+	}
+
+	switch method {
+	case "auth.gettoken":
+		h.lfmGetToken(w, r, params, asJSON)
+	case "auth.getsession":
+		h.lfmGetSession(w, r, params, asJSON)
+	case "auth.getmobilesession":
+		h.lfmGetMobileSession(w, r, params, asJSON)
+	case "track.scrobble":
+		h.lfmScrobble(w, r, params, asJSON)
+	case "track.updatenowplaying":
+		h.lfmUpdateNowPlaying(w, r, params, asJSON)
+	default:
+		renderLFMError(w, asJSON, lfmInvalidMethod, "Invalid Method - No method with that name in this package")
+	}
+}
+
+func (h *Handler) lfmGetToken(w http.ResponseWriter, r *http.Request, params map[string]string, asJSON bool) {
+	token, err := h.service.CreateToken(r.Context(), params["api_key"])
+	if err != nil {
+		h.renderLFMServiceError(w, asJSON, err, "failed to create scrobble token")
+		return
+	}
+
+	renderLFM(w, asJSON, lfmResponse{Token: token}, map[string]any{"token": token})
+}
+
+func (h *Handler) lfmGetSession(w http.ResponseWriter, r *http.Request, params map[string]string, asJSON bool) {
+	if params["token"] == "" {
+		renderLFMError(w, asJSON, lfmInvalidParams, "Invalid parameters - token is required")
+		return
+	}
+
+	session, err := h.service.GetSession(r.Context(), params["token"])
+	if err != nil {
+		h.renderLFMServiceError(w, asJSON, err, "failed to get scrobble session")
+		return
+	}
+
+	renderSession(w, asJSON, session)
+}
+
+func (h *Handler) lfmGetMobileSession(w http.ResponseWriter, r *http.Request, params map[string]string, asJSON bool) {
+	if params["username"] == "" || params["password"] == "" {
+		renderLFMError(w, asJSON, lfmInvalidParams, "Invalid parameters - username and password are required")
+		return
+	}
+
+	session, err := h.service.MobileSession(r.Context(), params["username"], params["password"], params["api_key"])
+	if err != nil {
+		h.renderLFMServiceError(w, asJSON, err, "failed to get mobile scrobble session")
+		return
+	}
+
+	renderSession(w, asJSON, session)
+}
+
+func (h *Handler) lfmScrobble(w http.ResponseWriter, r *http.Request, params map[string]string, asJSON bool) {
+	session, ok := h.lfmAuthenticate(w, r, params, asJSON)
+	if !ok {
+		return
+	}
+
+	tracks, err := parseScrobbles(params)
+	if err != nil {
+		renderLFMError(w, asJSON, lfmInvalidParams, "Invalid parameters - "+err.Error())
+		return
+	}
+
+	results, err := h.service.Scrobble(r.Context(), session.UserID, tracks)
+	if err != nil {
+		h.renderLFMServiceError(w, asJSON, err, "failed to scrobble")
+		return
+	}
+
+	body := lfmScrobbles{Scrobble: make([]lfmTrack, 0, len(results))}
+	for _, res := range results {
+		if res.Ignored == domain.IgnoredNone {
+			body.Accepted++
+		} else {
+			body.Ignored++
+		}
+		body.Scrobble = append(body.Scrobble, toLFMTrack(res, true))
+	}
+
+	// Last.fm отдаёт одно прослушивание объектом, несколько массивом
+	var list any = body.Scrobble
+	if len(body.Scrobble) == 1 {
+		list = body.Scrobble[0]
+	}
+
+	renderLFM(w, asJSON, lfmResponse{Scrobbles: &body}, map[string]any{
+		"scrobbles": map[string]any{
+			"scrobble": list,
+			"@attr": map[string]int{
+				"accepted": body.Accepted,
+				"ignored":  body.Ignored,
+			},
+		},
+	})
+}
+
+func (h *Handler) lfmUpdateNowPlaying(w http.ResponseWriter, r *http.Request, params map[string]string, asJSON bool) {
+	session, ok := h.lfmAuthenticate(w, r, params, asJSON)
+	if !ok {
+		return
+	}
+
+	t := domain.Track{
+		Artist:   params["artist"],
+		Track:    params["track"],
+		Album:    params["album"],
+		MBID:     params["mbid"],
+		Duration: parseDuration(params["duration"]),
+	}
+	if t.Artist == "" || t.Track == "" {
+		renderLFMError(w, asJSON, lfmInvalidParams, "Invalid parameters - artist and track are required")
+		return
+	}
+
+	res, err := h.service.UpdateNowPlaying(r.Context(), session.UserID, t)
+	if err != nil {
+		h.renderLFMServiceError(w, asJSON, err, "failed to update now playing")
+		return
+	}
+
+	track := toLFMTrack(*res, false)
+	renderLFM(w, asJSON, lfmResponse{NowPlaying: &track}, map[string]any{"nowplaying": track})
+}
+
+func (h *Handler) lfmAuthenticate(
+	w http.ResponseWriter,
+	r *http.Request,
+	params map[string]string,
+	asJSON bool,
+) (*domain.Session, bool) {
+	if params["sk"] == "" {
+		renderLFMError(w, asJSON, lfmInvalidParams, "Invalid parameters - sk is required")
+		return nil, false
+	}
+
+	session, err := h.service.Authenticate(r.Context(), params["sk"])
+	if err != nil {
+		h.renderLFMServiceError(w, asJSON, err, "failed to authenticate scrobbler")
+		return nil, false
+	}
+
+	return session, true
+}
+
+func (h *Handler) renderLFMServiceError(w http.ResponseWriter, asJSON bool, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrAuthFailed):
+		renderLFMError(w, asJSON, lfmAuthFailed, "Authentication Failed - "+err.Error())
+	case errors.Is(err, domain.ErrTokenNotFound):
+		renderLFMError(w, asJSON, lfmAuthFailed, "Invalid authentication token supplied")
+	case errors.Is(err, domain.ErrInvalidSession):
+		renderLFMError(w, asJSON, lfmInvalidSession, "Invalid session key - Please re-authenticate")
+	case errors.Is(err, domain.ErrTokenNotApproved):
+		renderLFMError(w, asJSON, lfmTokenNotApproved, "Unauthorized Token - This token has not been authorized")
+	case errors.Is(err, domain.ErrTokenExpired):
+		renderLFMError(w, asJSON, lfmTokenExpired, "This token has expired")
+	default:
+		h.logger.Error(msg, "error", err)
+		renderLFMError(w, asJSON, lfmOperationFailed, "Operation failed - Most likely the backend service failed")
+	}
+}
+
+// parseScrobbles разбирает пакет artist[i], track[i], timestamp[i]...;
+// одиночное прослушивание может прийти и без индексов
+func parseScrobbles(params map[string]string) ([]domain.Track, error) {
+	if _, ok := params["artist[0]"]; !ok {
+		if _, ok = params["artist"]; ok {
+			t, err := parseScrobble(params, "")
+			if err != nil {
+				return nil, err
+			}
+			return []domain.Track{t}, nil
+		}
+		return nil, errors.New("artist, track and timestamp are required")
+	}
+
+	if _, ok := params["artist["+strconv.Itoa(domain.MaxBatch)+"]"]; ok {
+		return nil, errors.New("at most " + strconv.Itoa(domain.MaxBatch) + " scrobbles per request")
+	}
+
+	tracks := make([]domain.Track, 0, 1)
+	for i := 0; i < domain.MaxBatch; i++ {
+		suffix := "[" + strconv.Itoa(i) + "]"
+		if _, ok := params["artist"+suffix]; !ok {
+			break
+		}
+
+		t, err := parseScrobble(params, suffix)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, t)
+	}
+
+	return tracks, nil
+}
+
+func parseScrobble(params map[string]string, suffix string) (domain.Track, error) {
+	ts, err := strconv.ParseInt(params["timestamp"+suffix], 10, 64)
+	if err != nil {
+		return domain.Track{}, errors.New("timestamp" + suffix + " must be a unix time")
+	}
+
+	return domain.Track{
+		Artist:   params["artist"+suffix],
+		Track:    params["track"+suffix],
+		Album:    params["album"+suffix],
+		MBID:     params["mbid"+suffix],
+		Duration: parseDuration(params["duration"+suffix]),
+		PlayedAt: time.Unix(ts, 0),
+	}, nil
+}
+
+// parseDuration длительность в секундах; неверная считается отсутствующей
+func parseDuration(v string) *int {
+	d, err := strconv.Atoi(v)
+	if err != nil || d <= 0 {
+		return nil
+	}
+	return &d
+}
+
+func toLFMTrack(res domain.Result, withTimestamp bool) lfmTrack {
+	t := lfmTrack{
+		Track:          lfmText{Corrected: "0", Text: res.Track.Track},
+		Artist:         lfmText{Corrected: "0", Text: res.Track.Artist},
+		Album:          lfmText{Corrected: "0", Text: res.Track.Album},
+		AlbumArtist:    lfmText{Corrected: "0"},
+		IgnoredMessage: lfmIgnored{Code: strconv.Itoa(int(res.Ignored)), Text: res.Ignored.Message()},
+	}
+	if withTimestamp {
+		t.Timestamp = strconv.FormatInt(res.Track.PlayedAt.Unix(), 10)
+	}
+	return t
+}
+
+func renderSession(w http.ResponseWriter, asJSON bool, s *domain.Session) {
+	body := lfmSession{Name: s.Username, Key: s.Key}
+	renderLFM(w, asJSON, lfmResponse{Session: &body}, map[string]any{"session": body})
+}
+
+func renderLFM(w http.ResponseWriter, asJSON bool, x lfmResponse, j any) {
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(j)
+		return
+	}
+
+	x.Status = "ok"
+	writeLFMXML(w, http.StatusOK, x)
+}
+
+func renderLFMError(w http.ResponseWriter, asJSON bool, code int, message string) {
+	status := http.StatusBadRequest
+	switch code {
+	case lfmAuthFailed, lfmInvalidSession, lfmInvalidAPIKey, lfmInvalidSignature, lfmTokenNotApproved, lfmTokenExpired:
+		status = http.StatusForbidden
+	case lfmOperationFailed:
+		status = http.StatusInternalServerError
+	}
+
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": code, "message": message})
+		return
+	}
+
+	writeLFMXML(w, status, lfmResponse{Status: "failed", Error: &lfmError{Code: code, Message: message}})
+}
+
+func writeLFMXML(w http.ResponseWriter, status int, x lfmResponse) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(x)
+}
@@ -0,0 +1,246 @@
+package scrobble
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/scrobble"
+	"github.com/maYkiss56/tunes/internal/domain/scrobble/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type ScrobbleService interface {
+	Enabled() bool
+	CheckSignature(params map[string]string) bool
+	CreateToken(ctx context.Context, client string) (string, error)
+	AuthorizeToken(ctx context.Context, userID int, req dto.AuthorizeRequest) error
+	GetSession(ctx context.Context, token string) (*domain.Session, error)
+	MobileSession(ctx context.Context, login, password, client string) (*domain.Session, error)
+	Authenticate(ctx context.Context, key string) (*domain.Session, error)
+	Scrobble(ctx context.Context, userID int, tracks []domain.Track) ([]domain.Result, error)
+	UpdateNowPlaying(ctx context.Context, userID int, t domain.Track) (*domain.Result, error)
+	GetScrobblers(ctx context.Context, userID int) ([]dto.ScrobblerResponse, error)
+	DeleteScrobbler(ctx context.Context, userID, id int) error
+	GetScrobbles(ctx context.Context, userID int, req dto.ListRequest) ([]dto.Response, error)
+	GetNowPlaying(ctx context.Context, userID int) (*dto.NowPlayingResponse, error)
+	GetSummary(ctx context.Context, userID int, req dto.StatsRequest) (*dto.SummaryResponse, error)
+	GetTopArtists(ctx context.Context, userID int, req dto.StatsRequest) ([]dto.ArtistStatResponse, error)
+	GetTopSongs(ctx context.Context, userID int, req dto.StatsRequest) ([]dto.SongStatResponse, error)
+}
+
+type Handler struct {
+	service ScrobbleService
+	logger  *logger.Logger
+}
+
+func NewHandler(service ScrobbleService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetScrobbles выводит историю прослушиваний текущего пользователя, новые первыми
+func (h *Handler) GetScrobbles(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scrobbles, err := h.service.GetScrobbles(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get scrobbles")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, scrobbles)
+}
+
+// GetNowPlaying отдаёт текущий трек; 204, если сейчас ничего не играет
+func (h *Handler) GetNowPlaying(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	res, err := h.service.GetNowPlaying(r.Context(), s.UserID)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get now playing")
+		return
+	}
+	if res == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+// GetSummary сводка прослушиваний за ?period=week|month|year|all
+func (h *Handler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseStatsRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.GetSummary(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get listening stats")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, *res)
+}
+
+func (h *Handler) GetTopArtists(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseStatsRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.service.GetTopArtists(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get top artists")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, stats)
+}
+
+func (h *Handler) GetTopSongs(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	req, err := parseStatsRequest(r)
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.service.GetTopSongs(r.Context(), s.UserID, req)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get top songs")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, stats)
+}
+
+// GetScrobblers выводит подключённые скробблеры текущего пользователя
+func (h *Handler) GetScrobblers(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	scrobblers, err := h.service.GetScrobblers(r.Context(), s.UserID)
+	if err != nil {
+		h.renderServiceError(w, r, err, "failed to get scrobblers")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, scrobblers)
+}
+
+func (h *Handler) DeleteScrobbler(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid scrobbler id")
+		return
+	}
+
+	if err = h.service.DeleteScrobbler(r.Context(), s.UserID, id); err != nil {
+		h.renderServiceError(w, r, err, "failed to delete scrobbler")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AuthorizeScrobbler подтверждает {"token": "..."} из auth.getToken настольного клиента;
+// после этого клиент получает ключ через auth.getSession
+func (h *Handler) AuthorizeScrobbler(w http.ResponseWriter, r *http.Request) {
+	s := session.FromContext(r.Context())
+
+	var req dto.AuthorizeRequest
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Error("invalid request body", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.AuthorizeToken(r.Context(), s.UserID, req); err != nil {
+		h.renderServiceError(w, r, err, "failed to authorize scrobbler")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) renderServiceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrSessionNotFound),
+		errors.Is(err, domain.ErrTokenNotFound):
+		utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrTokenExpired):
+		utilites.RenderError(w, r, http.StatusGone, err.Error())
+	default:
+		h.logger.Error(msg, "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, msg)
+	}
+}
+
+func parseListRequest(r *http.Request) (dto.ListRequest, error) {
+	var req dto.ListRequest
+
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid offset parameter")
+		}
+		req.Offset = offset
+	}
+
+	return req, req.Validate()
+}
+
+func parseStatsRequest(r *http.Request) (dto.StatsRequest, error) {
+	var req dto.StatsRequest
+
+	q := r.URL.Query()
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, errors.New("invalid limit parameter")
+		}
+		req.Limit = limit
+	}
+	req.Period = domain.Period(q.Get("period"))
+
+	return req, req.Validate()
+}
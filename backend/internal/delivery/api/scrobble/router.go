@@ -0,0 +1,31 @@
+package scrobble
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+// RegisterProtocolRoutes монтируется на /2.0: в скробблере указывается
+// адрес сайта с /2.0/ вместо ws.audioscrobbler.com
+func RegisterProtocolRoutes(r chi.Router, handler *Handler) {
+	r.Get("/", handler.Audioscrobbler)
+	r.Post("/", handler.Audioscrobbler)
+}
+
+// RegisterProfileRoutes монтируется на /api/profile/scrobbles
+func RegisterProfileRoutes(r chi.Router, handler *Handler) {
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+
+		r.Get("/", handler.GetScrobbles)
+		r.Get("/now-playing", handler.GetNowPlaying)
+		r.Get("/stats", handler.GetSummary)
+		r.Get("/stats/artists", handler.GetTopArtists)
+		r.Get("/stats/songs", handler.GetTopSongs)
+
+		r.Get("/scrobblers", handler.GetScrobblers)
+		r.Post("/scrobblers/authorize", handler.AuthorizeScrobbler)
+		r.Delete("/scrobblers/{id}", handler.DeleteScrobbler)
+	})
+}
@@ -0,0 +1,68 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type SnapshotService interface {
+	Export(ctx context.Context, w io.Writer, includeReviews bool) error
+}
+
+type Handler struct {
+	service SnapshotService
+	logger  *logger.Logger
+}
+
+func NewHandler(service SnapshotService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	includeReviews := false
+	if reviewsStr := r.URL.Query().Get("reviews"); reviewsStr != "" {
+		var err error
+		includeReviews, err = strconv.ParseBool(reviewsStr)
+		if err != nil {
+			h.logger.Error("invalid reviews parameter", "error", err)
+			utilites.RenderError(w, r, http.StatusBadRequest, "invalid reviews parameter")
+			return
+		}
+	}
+
+	fileName := fmt.Sprintf("tunes-snapshot-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+	cw := &countingWriter{w: w}
+	if err := h.service.Export(r.Context(), cw, includeReviews); err != nil {
+		h.logger.Error("failed to export snapshot", "error", err)
+		// если архив уже начал передаваться, статус изменить нельзя
+		if cw.n == 0 {
+			w.Header().Del("Content-Disposition")
+			utilites.RenderError(w, r, http.StatusInternalServerError, "failed to export snapshot")
+		}
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
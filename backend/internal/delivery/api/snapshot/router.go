@@ -0,0 +1,16 @@
+package snapshot
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"github.com/maYkiss56/tunes/internal/middleware"
+)
+
+func RegisterAdminRoutes(r chi.Router, handler *Handler) {
+	r.Route("/", func(r chi.Router) {
+		r.Use(middleware.AuthMiddleware)
+		r.Use(middleware.AdminOnlyMiddleware)
+
+		r.Get("/", handler.Export)
+	})
+}
@@ -2,26 +2,34 @@ package song
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
+	"github.com/maYkiss56/tunes/internal/domain/slug"
 	domain "github.com/maYkiss56/tunes/internal/domain/song"
 	"github.com/maYkiss56/tunes/internal/domain/song/dto"
+	"github.com/maYkiss56/tunes/internal/domain/trash"
 	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
 	"github.com/maYkiss56/tunes/internal/utilites"
 )
 
 type SongService interface {
 	CreateSong(ctx context.Context, song *domain.Song) error
-	GetAllSongsSortedByRating(ctx context.Context) ([]dto.Response, error)
-	GetTopSongs(ctx context.Context, timeRange string, limit int) ([]dto.Response, error)
-	GetAllSongs(ctx context.Context) ([]dto.Response, error)
+	GetAllSongsSortedByRating(ctx context.Context, viewerID int, rank domain.Rank) ([]dto.Response, error)
+	GetTopSongs(ctx context.Context, viewerID int, timeRange string, limit int, rank domain.Rank) ([]dto.Response, error)
+	GetAllSongs(ctx context.Context, viewerID int) ([]dto.Response, error)
 	GetSongByID(ctx context.Context, id int) (*dto.Response, error)
+	GetSongDetails(ctx context.Context, viewerID int, ref string) (*dto.Response, error)
+	SearchSongs(ctx context.Context, viewerID int, q string, limit int) ([]dto.SearchResult, error)
 	UpdateSong(ctx context.Context, id int, update dto.UpdateSongRequest) error
 	DeleteSong(ctx context.Context, id int) error
+	RestoreSong(ctx context.Context, id int) error
 }
 
 type Handler struct {
@@ -102,15 +110,37 @@ func (h *Handler) CreateSong(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	req := dto.CreateSongRequest{
+		Title:       title,
+		FullTitle:   fullTitle,
+		ImageURL:    imagePath,
+		ReleaseDate: releaseDate,
+		GenreID:     genreID,
+		ArtistID:    artistID,
+		AlbumID:     albumID,
+		ISRC:        r.FormValue("isrc"),
+		MBID:        r.FormValue("mbid"),
+	}
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	newSong, err := domain.NewSong(title, fullTitle, imagePath, releaseDate, genreID, artistID, albumID)
 	if err != nil {
 		h.logger.Error("invalid input song", "error", err)
 		utilites.RenderError(w, r, http.StatusBadRequest, "invalid unput song")
 		return
 	}
+	newSong.ISRC = req.ISRC
+	newSong.MBID = req.MBID
 
 	if err := h.service.CreateSong(r.Context(), newSong); err != nil {
 		h.logger.Error("faile to create song", "error", err)
+		if errors.Is(err, identifier.ErrDuplicate) {
+			utilites.RenderError(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to create song")
 		return
 	}
@@ -122,7 +152,13 @@ func (h *Handler) CreateSong(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAllSongsSortedByRating(w http.ResponseWriter, r *http.Request) {
-	songs, err := h.service.GetAllSongsSortedByRating(r.Context())
+	rank, err := domain.ParseRank(r.URL.Query().Get("rank"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	songs, err := h.service.GetAllSongsSortedByRating(r.Context(), session.UserID(r.Context()), rank)
 	if err != nil {
 		h.logger.Error("failed to get songs sorted by rating", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get songs")
@@ -142,10 +178,15 @@ func (h *Handler) GetTopSongs(w http.ResponseWriter, r *http.Request) {
 		timeRange = "all"
 	}
 
+	rank, err := domain.ParseRank(r.URL.Query().Get("rank"))
+	if err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	limitStr := r.URL.Query().Get("limit")
 	limit := 100
 	if limitStr != "" {
-		var err error
 		limit, err = strconv.Atoi(limitStr)
 		if err != nil {
 			h.logger.Error("invalid limit parameter", "error", err)
@@ -154,7 +195,7 @@ func (h *Handler) GetTopSongs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	songs, err := h.service.GetTopSongs(r.Context(), timeRange, limit)
+	songs, err := h.service.GetTopSongs(r.Context(), session.UserID(r.Context()), timeRange, limit, rank)
 	if err != nil {
 		h.logger.Error("failed to get top songs", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get top songs")
@@ -165,7 +206,7 @@ func (h *Handler) GetTopSongs(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetAllSongs(w http.ResponseWriter, r *http.Request) {
-	songs, err := h.service.GetAllSongs(r.Context())
+	songs, err := h.service.GetAllSongs(r.Context(), session.UserID(r.Context()))
 	if err != nil {
 		h.logger.Error("failed to get songs", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get songs")
@@ -179,16 +220,46 @@ func (h *Handler) GetAllSongs(w http.ResponseWriter, r *http.Request) {
 	utilites.RenderJSON(w, r, http.StatusOK, songList)
 }
 
-func (h *Handler) GetSongByID(w http.ResponseWriter, r *http.Request) {
-	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+func (h *Handler) SearchSongs(w http.ResponseWriter, r *http.Request) {
+	req := dto.SearchRequest{Query: r.URL.Query().Get("q")}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			h.logger.Error("invalid limit parameter", "error", err)
+			utilites.RenderError(w, r, http.StatusBadRequest, "invalid limit parameter")
+			return
+		}
+		req.Limit = limit
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.SearchSongs(r.Context(), session.UserID(r.Context()), req.Query, req.Limit)
 	if err != nil {
-		h.logger.Error("invalid song id", "error", err)
-		utilites.RenderError(w, r, http.StatusBadRequest, "invalid song id")
+		h.logger.Error("failed to search songs", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to search songs")
 		return
 	}
 
-	s, err := h.service.GetSongByID(r.Context(), id)
+	utilites.RenderJSON(w, r, http.StatusOK, results)
+}
+
+func (h *Handler) GetSongByID(w http.ResponseWriter, r *http.Request) {
+	s, err := h.service.GetSongDetails(r.Context(), session.UserID(r.Context()), chi.URLParam(r, "id"))
 	if err != nil {
+		var moved *slug.MovedError
+		if errors.As(err, &moved) {
+			http.Redirect(w, r, "/api/songs/"+moved.Slug, http.StatusMovedPermanently)
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
 		h.logger.Error("failed to get song", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get song")
 		return
@@ -273,8 +344,27 @@ func (h *Handler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 		req.AlbumID = &albumID
 	}
 
+	// наличие поля с пустым значением сбрасывает идентификатор
+	if r.Form.Has("isrc") {
+		isrc := r.FormValue("isrc")
+		req.ISRC = &isrc
+	}
+	if r.Form.Has("mbid") {
+		mbid := r.FormValue("mbid")
+		req.MBID = &mbid
+	}
+
+	if err := req.Validate(); err != nil {
+		utilites.RenderError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if err := h.service.UpdateSong(r.Context(), id, req); err != nil {
 		h.logger.Error("failed to update song", "error", err)
+		if errors.Is(err, identifier.ErrDuplicate) {
+			utilites.RenderError(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to update song")
 		return
 	}
@@ -299,9 +389,34 @@ func (h *Handler) DeleteSong(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.service.DeleteSong(r.Context(), id); err != nil {
 		h.logger.Error("failed to delete song", "error", err)
+		if errors.Is(err, domain.ErrNotFound) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to delete song")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *Handler) RestoreSong(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("invalid song id", "error", err)
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid song id")
+		return
+	}
+
+	if err := h.service.RestoreSong(r.Context(), id); err != nil {
+		h.logger.Error("failed to restore song", "error", err)
+		if errors.Is(err, trash.ErrNotInTrash) {
+			utilites.RenderError(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to restore song")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
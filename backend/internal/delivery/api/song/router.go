@@ -8,9 +8,12 @@ import (
 
 func RegisterPublicRoutes(r chi.Router, handler *Handler) {
 	r.Route("/", func(r chi.Router) {
+		r.Use(middleware.OptionalAuthMiddleware)
+
 		r.Get("/", handler.GetAllSongs)
 		r.Get("/sorted-by-rating", handler.GetAllSongsSortedByRating)
 		r.Get("/top", handler.GetTopSongs)
+		r.Get("/search", handler.SearchSongs)
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", handler.GetSongByID)
 		})
@@ -26,6 +29,7 @@ func RegisterAdminRoutes(r chi.Router, handler *Handler) {
 		r.Route("/{id}", func(r chi.Router) {
 			r.Patch("/", handler.UpdateSong)
 			r.Delete("/", handler.DeleteSong)
+			r.Post("/restore", handler.RestoreSong)
 		})
 	})
 }
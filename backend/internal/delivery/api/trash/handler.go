@@ -0,0 +1,44 @@
+package trash
+
+import (
+	"context"
+	"net/http"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/trash"
+	"github.com/maYkiss56/tunes/internal/domain/trash/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type TrashService interface {
+	ListTrash(ctx context.Context, entity string) ([]dto.Response, error)
+}
+
+type Handler struct {
+	service TrashService
+	logger  *logger.Logger
+}
+
+func NewHandler(service TrashService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	entity := r.URL.Query().Get("entity")
+	if entity != "" && !domain.IsValidEntity(entity) {
+		utilites.RenderError(w, r, http.StatusBadRequest, "invalid entity parameter")
+		return
+	}
+
+	items, err := h.service.ListTrash(r.Context(), entity)
+	if err != nil {
+		h.logger.Error("failed to list trash", "error", err)
+		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to list trash")
+		return
+	}
+
+	utilites.RenderJSON(w, r, http.StatusOK, items)
+}
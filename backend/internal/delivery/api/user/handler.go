@@ -20,7 +20,7 @@ type UserService interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetUserByID(ctx context.Context, id int) (*domain.User, error)
-	GetTopReviewers(ctx context.Context) ([]dto.TopResponse, error)
+	GetTopReviewers(ctx context.Context, by string) ([]dto.TopResponse, error)
 	UpdateUserAvatar(ctx context.Context, id int, req dto.UpdateAvatarRequest) error
 	UpdateUserPassword(ctx context.Context, id int, req dto.UpdatePasswordRequest) error
 	UpdateUserRequest(ctx context.Context, id int, req dto.UpdateUsersRequest) error
@@ -133,7 +133,7 @@ func (h *Handler) ProfileUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utilites.RenderJSON(w, r, http.StatusOK, dto.ToResponse(*user))
+	utilites.RenderJSON(w, r, http.StatusOK, dto.ToProfileResponse(*user))
 }
 
 func (h *Handler) LogoutUser(w http.ResponseWriter, r *http.Request) {
@@ -243,7 +243,13 @@ func (h *Handler) UpdateUserInfo(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetTopReviewers(w http.ResponseWriter, r *http.Request) {
-	reviewers, err := h.service.GetTopReviewers(r.Context())
+	by := r.URL.Query().Get("by")
+	if !domain.IsValidTopBy(by) {
+		utilites.RenderError(w, r, http.StatusBadRequest, domain.ErrInvalidTopBy.Error())
+		return
+	}
+
+	reviewers, err := h.service.GetTopReviewers(r.Context(), by)
 	if err != nil {
 		h.logger.Error("failed to get reviewers", "error", err)
 		utilites.RenderError(w, r, http.StatusInternalServerError, "failed to get reviewers")
@@ -1,10 +1,23 @@
 package album
 
+import (
+	"errors"
+
+	"github.com/maYkiss56/tunes/internal/domain/rating"
+)
+
+var ErrNotFound = errors.New("album not found")
+
 type Album struct {
 	ID       int
 	Title    string
 	ImageURL string
 	ArtistID int
+	UPC      string
+	MBID     string
+	Slug     string
+	// Ratings заполняется при чтении альбома со сводкой рецензий
+	Ratings *rating.Summary
 }
 
 func NewAlbum(title, imageURL string, artistID int) (*Album, error) {
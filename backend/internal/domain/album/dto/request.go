@@ -1,11 +1,17 @@
 package dto
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
+)
 
 type CreateAlbumRequest struct {
 	Title    string `json:"title"`
 	ImageURL string `json:"image_url"`
 	ArtistID int    `json:"artist_id"`
+	UPC      string `json:"upc,omitempty"`
+	MBID     string `json:"mbid,omitempty"`
 }
 
 func (r *CreateAlbumRequest) Validate() error {
@@ -18,6 +24,20 @@ func (r *CreateAlbumRequest) Validate() error {
 	if r.ArtistID == 0 {
 		return errors.New("artist_id is required")
 	}
+	if r.UPC != "" {
+		upc, err := identifier.NormalizeUPC(r.UPC)
+		if err != nil {
+			return err
+		}
+		r.UPC = upc
+	}
+	if r.MBID != "" {
+		mbid, err := identifier.NormalizeMBID(r.MBID)
+		if err != nil {
+			return err
+		}
+		r.MBID = mbid
+	}
 	return nil
 
 }
@@ -26,6 +46,8 @@ type UpdateAlbumRequest struct {
 	Title    *string `json:"title,omitempty"`
 	ImageURL *string `json:"image_url,omitempty"`
 	ArtistID *int    `json:"artist_id,omitempty"`
+	UPC      *string `json:"upc,omitempty"`
+	MBID     *string `json:"mbid,omitempty"`
 }
 
 func (r *UpdateAlbumRequest) Validate() error {
@@ -38,6 +60,21 @@ func (r *UpdateAlbumRequest) Validate() error {
 	if r.ArtistID != nil && *r.ArtistID == 0 {
 		return errors.New("artist_id cannot be empty")
 	}
+	// пустая строка сбрасывает идентификатор
+	if r.UPC != nil && *r.UPC != "" {
+		upc, err := identifier.NormalizeUPC(*r.UPC)
+		if err != nil {
+			return err
+		}
+		r.UPC = &upc
+	}
+	if r.MBID != nil && *r.MBID != "" {
+		mbid, err := identifier.NormalizeMBID(*r.MBID)
+		if err != nil {
+			return err
+		}
+		r.MBID = &mbid
+	}
 
 	return nil
 }
@@ -4,13 +4,22 @@ import (
 	"github.com/maYkiss56/tunes/internal/domain/album"
 	"github.com/maYkiss56/tunes/internal/domain/artist"
 	artistDTO "github.com/maYkiss56/tunes/internal/domain/artist/dto"
+	libraryDTO "github.com/maYkiss56/tunes/internal/domain/library/dto"
+	ratingDTO "github.com/maYkiss56/tunes/internal/domain/rating/dto"
+	"github.com/maYkiss56/tunes/internal/markdown"
 )
 
 type Response struct {
-	ID       int                `json:"id"`
-	Title    string             `json:"title"`
-	ImageURL string             `json:"image_url"`
-	Artist   artistDTO.Response `json:"artist"`
+	ID       int                        `json:"id"`
+	Title    string                     `json:"title"`
+	ImageURL string                     `json:"image_url"`
+	UPC      string                     `json:"upc,omitempty"`
+	MBID     string                     `json:"mbid,omitempty"`
+	Slug     string                     `json:"slug,omitempty"`
+	Artist   artistDTO.Response         `json:"artist"`
+	Ratings  *ratingDTO.SummaryResponse `json:"ratings,omitempty"`
+	// Library отметки вошедшего пользователя в его библиотеке
+	Library *libraryDTO.StatusResponse `json:"library,omitempty"`
 }
 
 func ToResponse(a album.Album, ar artist.Artist) Response {
@@ -18,11 +27,17 @@ func ToResponse(a album.Album, ar artist.Artist) Response {
 		ID:       a.ID,
 		Title:    a.Title,
 		ImageURL: a.ImageURL,
+		UPC:      a.UPC,
+		MBID:     a.MBID,
+		Slug:     a.Slug,
 		Artist: artistDTO.Response{
 			ID:       ar.ID,
 			Nickname: ar.Nickname,
 			BIO:      ar.BIO,
+			BIOHTML:  markdown.Render(ar.BIO),
 			Country:  ar.Country,
+			Slug:     ar.Slug,
 		},
+		Ratings: ratingDTO.ToSummaryResponse(a.Ratings),
 	}
 }
@@ -1,10 +1,23 @@
 package artist
 
+import (
+	"github.com/maYkiss56/tunes/internal/domain/mention"
+	"github.com/maYkiss56/tunes/internal/domain/rating"
+)
+
 type Artist struct {
 	ID       int
 	Nickname string
 	BIO      string
 	Country  string
+	MBID     string
+	Slug     string
+	// FollowerCount число подписчиков на релизы
+	FollowerCount int
+	// Ratings заполняется при чтении исполнителя со сводкой рецензий
+	Ratings *rating.Summary
+	// Mentions упоминания из биографии, заполняются при чтении
+	Mentions []mention.Mention
 }
 
 func NewArtist(nickname, bio, country string) (*Artist, error) {
@@ -1,6 +1,10 @@
 package dto
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
+)
 
 const (
 	NicknameLenght = 150
@@ -14,12 +18,20 @@ type CreateArtistRequest struct {
 	Nickname string `json:"nickname"`
 	BIO      string `json:"bio"`
 	Country  string `json:"country"`
+	MBID     string `json:"mbid,omitempty"`
 }
 
 func (r *CreateArtistRequest) Validate() error {
 	if len(r.Nickname) > NicknameLenght {
 		return ErrNicknameLenght
 	}
+	if r.MBID != "" {
+		mbid, err := identifier.NormalizeMBID(r.MBID)
+		if err != nil {
+			return err
+		}
+		r.MBID = mbid
+	}
 	return nil
 }
 
@@ -27,12 +39,33 @@ type UpdateArtistRequest struct {
 	Nickname *string `json:"nickname,omitempty"`
 	BIO      *string `json:"bio,omitempty"`
 	Country  *string `json:"country,omitempty"`
+	MBID     *string `json:"mbid,omitempty"`
 }
 
 func (r *UpdateArtistRequest) Validate() error {
 	if r.Nickname != nil && len(*r.Nickname) > NicknameLenght {
 		return ErrNicknameLenght
 	}
+	// пустая строка сбрасывает идентификатор
+	if r.MBID != nil && *r.MBID != "" {
+		mbid, err := identifier.NormalizeMBID(*r.MBID)
+		if err != nil {
+			return err
+		}
+		r.MBID = &mbid
+	}
+
+	return nil
+}
+
+type MergeArtistRequest struct {
+	Into int `json:"into"`
+}
+
+func (r *MergeArtistRequest) Validate() error {
+	if r.Into <= 0 {
+		return errors.New("into is required")
+	}
 
 	return nil
 }
@@ -1,19 +1,61 @@
 package dto
 
-import "github.com/maYkiss56/tunes/internal/domain/artist"
+import (
+	"github.com/maYkiss56/tunes/internal/domain/artist"
+	"github.com/maYkiss56/tunes/internal/domain/mention"
+	mentionDTO "github.com/maYkiss56/tunes/internal/domain/mention/dto"
+	ratingDTO "github.com/maYkiss56/tunes/internal/domain/rating/dto"
+	"github.com/maYkiss56/tunes/internal/markdown"
+)
 
 type Response struct {
-	ID       int    `json:"id"`
-	Nickname string `json:"nickname"`
-	BIO      string `json:"bio"`
-	Country  string `json:"country"`
+	ID            int                        `json:"id"`
+	Nickname      string                     `json:"nickname"`
+	BIO           string                     `json:"bio"`
+	BIOHTML       string                     `json:"bio_html"`
+	Mentions      []mentionDTO.Response      `json:"mentions,omitempty"`
+	Country       string                     `json:"country"`
+	MBID          string                     `json:"mbid,omitempty"`
+	Slug          string                     `json:"slug,omitempty"`
+	FollowerCount int                        `json:"follower_count"`
+	Ratings       *ratingDTO.SummaryResponse `json:"ratings,omitempty"`
 }
 
 func ToResponse(a artist.Artist) Response {
 	return Response{
-		ID:       a.ID,
-		Nickname: a.Nickname,
-		BIO:      a.BIO,
-		Country:  a.Country,
+		ID:            a.ID,
+		Nickname:      a.Nickname,
+		BIO:           a.BIO,
+		BIOHTML:       markdown.RenderLinks(a.BIO, mention.Links(a.Mentions)),
+		Mentions:      mentionDTO.ToResponses(a.Mentions),
+		Country:       a.Country,
+		MBID:          a.MBID,
+		Slug:          a.Slug,
+		FollowerCount: a.FollowerCount,
+		Ratings:       ratingDTO.ToSummaryResponse(a.Ratings),
 	}
 }
+
+type DuplicateResponse struct {
+	Artist    Response `json:"artist"`
+	Candidate Response `json:"candidate"`
+	Score     float64  `json:"score"`
+	SameName  bool     `json:"same_name"`
+}
+
+func ToDuplicateResponse(d artist.Duplicate) DuplicateResponse {
+	return DuplicateResponse{
+		Artist:    ToResponse(d.Artist),
+		Candidate: ToResponse(d.Candidate),
+		Score:     d.Score,
+		SameName:  d.SameName,
+	}
+}
+
+type MergeResponse struct {
+	Artist       Response `json:"artist"`
+	MergedID     int      `json:"merged_id"`
+	SongsMoved   int64    `json:"songs_moved"`
+	AlbumsMoved  int64    `json:"albums_moved"`
+	ReviewsMoved int64    `json:"reviews_moved"`
+}
@@ -0,0 +1,135 @@
+package artist
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultSimilarity — порог, начиная с которого пара артистов считается вероятным дублем
+const DefaultSimilarity = 0.85
+
+var (
+	ErrMergeSelf = errors.New("cannot merge artist into itself")
+	ErrNotFound  = errors.New("artist not found")
+)
+
+// MovedError возвращается при обращении к артисту, влитому в другого
+type MovedError struct {
+	ID int
+}
+
+func (e *MovedError) Error() string {
+	return fmt.Sprintf("artist was merged into %d", e.ID)
+}
+
+type Duplicate struct {
+	Artist    Artist
+	Candidate Artist
+	Score     float64
+	SameName  bool
+}
+
+type MergeResult struct {
+	SourceID int
+	TargetID int
+	Songs    int64
+	Albums   int64
+	Reviews  int64
+}
+
+// NormalizeName приводит имя к виду для сравнения: нижний регистр,
+// без знаков препинания, одиночные пробелы, без артикля "the"
+func NormalizeName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "&", " and ")
+
+	var b strings.Builder
+	space := false
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		case unicode.IsSpace(r):
+			space = true
+		}
+	}
+
+	return strings.TrimPrefix(b.String(), "the ")
+}
+
+// Similarity возвращает близость двух нормализованных имён от 0 до 1
+// на основе расстояния Левенштейна
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+// Candidate пара артистов с похожими именами, отобранная базой для точной проверки
+type Candidate struct {
+	Artist Artist
+	Other  Artist
+}
+
+// FindDuplicates проверяет пары кандидатов и возвращает те, у которых
+// нормализованные имена совпадают или близки не ниже threshold
+func FindDuplicates(candidates []Candidate, threshold float64) []Duplicate {
+	dups := make([]Duplicate, 0)
+	for _, c := range candidates {
+		a, b := NormalizeName(c.Artist.Nickname), NormalizeName(c.Other.Nickname)
+		if a == "" || b == "" {
+			continue
+		}
+
+		score := Similarity(a, b)
+		if score < threshold {
+			continue
+		}
+
+		dups = append(dups, Duplicate{
+			Artist:    c.Artist,
+			Candidate: c.Other,
+			Score:     score,
+			SameName:  a == b,
+		})
+	}
+
+	sort.SliceStable(dups, func(i, j int) bool {
+		return dups[i].Score > dups[j].Score
+	})
+
+	return dups
+}
@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+const (
+	EntitySong       = "song"
+	EntityAlbum      = "album"
+	EntityArtist     = "artist"
+	EntityGenre      = "genre"
+	EntityReview     = "review"
+	EntityModeration = "moderation"
+	EntityLyrics     = "lyrics"
+	EntityComment    = "comment"
+)
+
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionRestore  = "restore"
+	ActionModerate = "moderate"
+	ActionMerge    = "merge"
+)
+
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type Entry struct {
+	ID         int
+	ActorID    int
+	ActorEmail string
+	Entity     string
+	EntityID   int
+	Action     string
+	Before     json.RawMessage
+	After      json.RawMessage
+	Changes    map[string]Change
+	CreatedAt  time.Time
+}
+
+// Filter ограничивает выборку журнала, нулевые поля не фильтруют
+type Filter struct {
+	ActorID  int
+	Entity   string
+	EntityID int
+	From     time.Time
+	To       time.Time
+	Limit    int
+	Offset   int
+}
+
+// NewEntry сериализует состояния до и после изменения и вычисляет
+// разницу по полям верхнего уровня
+func NewEntry(actorID int, actorEmail, entity string, entityID int, action string, before, after any) (*Entry, error) {
+	beforeJSON, err := marshalState(before)
+	if err != nil {
+		return nil, err
+	}
+	afterJSON, err := marshalState(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := Diff(beforeJSON, afterJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		ActorID:    actorID,
+		ActorEmail: actorEmail,
+		Entity:     entity,
+		EntityID:   entityID,
+		Action:     action,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		Changes:    changes,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func marshalState(v any) (json.RawMessage, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// Diff сравнивает два JSON-объекта и возвращает изменившиеся поля
+func Diff(before, after json.RawMessage) (map[string]Change, error) {
+	b := make(map[string]any)
+	a := make(map[string]any)
+
+	if len(before) > 0 {
+		if err := json.Unmarshal(before, &b); err != nil {
+			return nil, err
+		}
+	}
+	if len(after) > 0 {
+		if err := json.Unmarshal(after, &a); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make(map[string]Change)
+	for k, bv := range b {
+		av, ok := a[k]
+		if !ok || !reflect.DeepEqual(bv, av) {
+			changes[k] = Change{Before: bv, After: av}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			changes[k] = Change{Before: nil, After: av}
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/audit"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+type ListRequest struct {
+	ActorID  int
+	Entity   string
+	EntityID int
+	From     time.Time
+	To       time.Time
+	Limit    int
+	Offset   int
+}
+
+func (r *ListRequest) Validate() error {
+	if r.ActorID < 0 || r.EntityID < 0 {
+		return errors.New("ids must be positive")
+	}
+	if !r.From.IsZero() && !r.To.IsZero() && r.To.Before(r.From) {
+		return errors.New("to must be after from")
+	}
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+	if r.Offset < 0 {
+		return errors.New("offset must be positive")
+	}
+
+	return nil
+}
+
+func (r *ListRequest) ToFilter() audit.Filter {
+	return audit.Filter{
+		ActorID:  r.ActorID,
+		Entity:   r.Entity,
+		EntityID: r.EntityID,
+		From:     r.From,
+		To:       r.To,
+		Limit:    r.Limit,
+		Offset:   r.Offset,
+	}
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/audit"
+)
+
+type Response struct {
+	ID         int                     `json:"id"`
+	ActorID    int                     `json:"actor_id"`
+	ActorEmail string                  `json:"actor_email"`
+	Entity     string                  `json:"entity"`
+	EntityID   int                     `json:"entity_id"`
+	Action     string                  `json:"action"`
+	Before     json.RawMessage         `json:"before,omitempty"`
+	After      json.RawMessage         `json:"after,omitempty"`
+	Diff       map[string]audit.Change `json:"diff"`
+	CreatedAt  time.Time               `json:"created_at"`
+}
+
+func ToResponse(e audit.Entry) Response {
+	return Response{
+		ID:         e.ID,
+		ActorID:    e.ActorID,
+		ActorEmail: e.ActorEmail,
+		Entity:     e.Entity,
+		EntityID:   e.EntityID,
+		Action:     e.Action,
+		Before:     e.Before,
+		After:      e.After,
+		Diff:       e.Changes,
+		CreatedAt:  e.CreatedAt,
+	}
+}
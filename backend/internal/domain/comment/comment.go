@@ -0,0 +1,130 @@
+package comment
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/moderation"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+)
+
+const (
+	// MaxLength ограничивает длину комментария в байтах
+	MaxLength = 4000
+	// MaxDepth глубина вложенности ответов, корневые комментарии имеют глубину 0
+	MaxDepth = 8
+)
+
+var (
+	ErrEmpty          = errors.New("comment is empty")
+	ErrTooLong        = errors.New("comment is too long")
+	ErrTooDeep        = errors.New("comment thread is too deep")
+	ErrNotFound       = errors.New("comment not found")
+	ErrParentNotFound = errors.New("parent comment not found")
+	ErrReviewNotFound = errors.New("review not found")
+	ErrForbidden      = errors.New("only the author can change the comment")
+)
+
+type Comment struct {
+	ID       int
+	ReviewID int
+	// ParentID == nil у корневого комментария
+	ParentID *int
+	UserID   int
+	User     users.User
+	Body     string
+	Depth    int
+	Status   moderation.Status
+	// Reason причина решения модератора
+	Reason    string
+	EditedAt  *time.Time
+	DeletedAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewComment создаёт корневой комментарий; для ответа вызывается ReplyTo
+func NewComment(reviewID, userID int, body string) (*Comment, error) {
+	body, err := NormalizeBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comment{
+		ReviewID:  reviewID,
+		UserID:    userID,
+		Body:      body,
+		Status:    moderation.StatusApproved,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// ReplyTo делает комментарий ответом на parent той же рецензии
+func (c *Comment) ReplyTo(parent *Comment) error {
+	if parent.ReviewID != c.ReviewID || !parent.IsVisible() {
+		return ErrParentNotFound
+	}
+	if parent.Depth+1 > MaxDepth {
+		return ErrTooDeep
+	}
+
+	c.ParentID = &parent.ID
+	c.Depth = parent.Depth + 1
+
+	return nil
+}
+
+// IsVisible сообщает, что комментарий показывается в ветке и на него можно отвечать
+func (c *Comment) IsVisible() bool {
+	return c.Status == moderation.StatusApproved && c.DeletedAt == nil
+}
+
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+func (c *Comment) IsEdited() bool {
+	return c.EditedAt != nil
+}
+
+func NormalizeBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", ErrEmpty
+	}
+	if len(body) > MaxLength {
+		return "", ErrTooLong
+	}
+	return body, nil
+}
+
+// Node комментарий вместе с ответами
+type Node struct {
+	Comment
+	Replies []*Node
+}
+
+// BuildTree собирает плоский список в дерево, сохраняя порядок комментариев
+// внутри каждого уровня. Ответы, чей родитель не попал в список, отбрасываются.
+func BuildTree(comments []Comment) []*Node {
+	nodes := make(map[int]*Node, len(comments))
+	for _, c := range comments {
+		nodes[c.ID] = &Node{Comment: c}
+	}
+
+	roots := make([]*Node, 0)
+	for _, c := range comments {
+		node := nodes[c.ID]
+		if c.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		if parent, ok := nodes[*c.ParentID]; ok {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+
+	return roots
+}
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/maYkiss56/tunes/internal/domain/comment"
+	"github.com/maYkiss56/tunes/internal/domain/moderation"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type CreateCommentRequest struct {
+	Body     string `json:"body"`
+	ParentID *int   `json:"parent_id,omitempty"`
+}
+
+func (r *CreateCommentRequest) Validate() error {
+	if _, err := comment.NormalizeBody(r.Body); err != nil {
+		return err
+	}
+	if r.ParentID != nil && *r.ParentID <= 0 {
+		return errors.New("invalid parent_id")
+	}
+	return nil
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+func (r *UpdateCommentRequest) Validate() error {
+	body, err := comment.NormalizeBody(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = body
+	return nil
+}
+
+type ModerateCommentRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func (r *ModerateCommentRequest) Validate() error {
+	if !moderation.Status(r.Status).IsValid() {
+		return errors.New("invalid status")
+	}
+	if moderation.Status(r.Status) == moderation.StatusRejected && r.Reason == "" {
+		return errors.New("reason is required")
+	}
+	return nil
+}
+
+// ListRequest постраничный вывод: для ветки страницы считаются по корневым комментариям
+type ListRequest struct {
+	Limit  int
+	Offset int
+}
+
+func (r *ListRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+	if r.Offset < 0 {
+		return errors.New("offset must be positive")
+	}
+	return nil
+}
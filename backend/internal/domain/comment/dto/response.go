@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/comment"
+	userDTO "github.com/maYkiss56/tunes/internal/domain/users/dto"
+)
+
+type Response struct {
+	ID       int  `json:"id"`
+	ReviewID int  `json:"review_id"`
+	ParentID *int `json:"parent_id,omitempty"`
+	// User и Body не отдаются у удалённого комментария, его место в ветке сохраняется ради ответов
+	User      *userDTO.Response `json:"user,omitempty"`
+	Body      string            `json:"body"`
+	Status    string            `json:"status"`
+	Reason    string            `json:"reason,omitempty"`
+	Deleted   bool              `json:"deleted"`
+	Edited    bool              `json:"edited"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Replies   []Response        `json:"replies,omitempty"`
+}
+
+type ThreadResponse struct {
+	Total    int        `json:"total"`
+	Limit    int        `json:"limit"`
+	Offset   int        `json:"offset"`
+	Comments []Response `json:"comments"`
+}
+
+func ToResponse(c comment.Comment) Response {
+	res := Response{
+		ID:        c.ID,
+		ReviewID:  c.ReviewID,
+		ParentID:  c.ParentID,
+		Status:    string(c.Status),
+		Reason:    c.Reason,
+		Deleted:   c.IsDeleted(),
+		Edited:    c.IsEdited(),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+
+	if !c.IsDeleted() {
+		res.Body = c.Body
+		res.User = &userDTO.Response{
+			ID:        c.User.ID,
+			Email:     c.User.Email,
+			Username:  c.User.Username,
+			AvatarURL: c.User.AvatarURL,
+		}
+	}
+
+	return res
+}
+
+func ToThreadResponse(nodes []*comment.Node, total, limit, offset int) ThreadResponse {
+	return ThreadResponse{
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+		Comments: toNodes(nodes),
+	}
+}
+
+func toNodes(nodes []*comment.Node) []Response {
+	res := make([]Response, 0, len(nodes))
+	for _, n := range nodes {
+		r := ToResponse(n.Comment)
+		if len(n.Replies) > 0 {
+			r.Replies = toNodes(n.Replies)
+		}
+		res = append(res, r)
+	}
+	return res
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/maYkiss56/tunes/internal/domain/feed"
+
+const (
+	defaultLimit = 20
+	maxLimit     = 50
+)
+
+// Request страница ленты; Cursor == nil для первой страницы
+type Request struct {
+	Limit  int
+	Cursor *feed.Cursor
+}
+
+func (r *Request) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/feed"
+	playlistDTO "github.com/maYkiss56/tunes/internal/domain/playlist/dto"
+	reviewDTO "github.com/maYkiss56/tunes/internal/domain/review/dto"
+	userDTO "github.com/maYkiss56/tunes/internal/domain/users/dto"
+)
+
+// ItemResponse событие ленты; Review есть у рецензий и оценок, List у списков
+type ItemResponse struct {
+	Kind   feed.Kind             `json:"kind"`
+	At     time.Time             `json:"at"`
+	Actor  userDTO.Response      `json:"actor"`
+	Review *reviewDTO.Response   `json:"review,omitempty"`
+	List   *playlistDTO.Response `json:"list,omitempty"`
+}
+
+type PageResponse struct {
+	Items []ItemResponse `json:"items"`
+	// NextCursor пуст на последней странице
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func ToItemResponse(item feed.Item) ItemResponse {
+	return ItemResponse{
+		Kind: item.Kind,
+		At:   item.At,
+		Actor: userDTO.Response{
+			ID:        item.Actor.ID,
+			Username:  item.Actor.Username,
+			AvatarURL: item.Actor.AvatarURL,
+		},
+	}
+}
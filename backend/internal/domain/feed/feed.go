@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/users"
+)
+
+// Kind вид события ленты
+type Kind string
+
+const (
+	// KindReview подписка опубликовала рецензию
+	KindReview Kind = "review"
+	// KindLike подписка отметила рецензию полезной
+	KindLike Kind = "like"
+	// KindList подписка создала или изменила публичный список
+	KindList Kind = "list"
+)
+
+var ErrInvalidCursor = errors.New("invalid feed cursor")
+
+// Item событие ленты: Actor совершил действие вида Kind в момент At над объектом ObjectID —
+// списком для KindList, рецензией для остальных
+type Item struct {
+	Kind     Kind
+	At       time.Time
+	Actor    users.User
+	ObjectID int
+}
+
+// Cursor позиция в ленте; события упорядочены по (At, Kind, ActorID, ObjectID) по убыванию,
+// и этот набор однозначно задаёт событие
+type Cursor struct {
+	At       time.Time
+	Kind     Kind
+	ActorID  int
+	ObjectID int
+}
+
+// After курсор, с которого начинается следующая страница после события
+func After(item Item) Cursor {
+	return Cursor{At: item.At, Kind: item.Kind, ActorID: item.Actor.ID, ObjectID: item.ObjectID}
+}
+
+// Encode возвращает непрозрачную строку для параметра cursor
+func (c Cursor) Encode() string {
+	raw := fmt.Sprintf("%d:%s:%d:%d", c.At.UnixNano(), c.Kind, c.ActorID, c.ObjectID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 {
+		return nil, ErrInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	actorID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	objectID, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{
+		At:       time.Unix(0, nanos),
+		Kind:     Kind(parts[1]),
+		ActorID:  actorID,
+		ObjectID: objectID,
+	}, nil
+}
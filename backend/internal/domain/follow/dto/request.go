@@ -0,0 +1,27 @@
+package dto
+
+import "errors"
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type ListRequest struct {
+	Limit  int
+	Offset int
+}
+
+func (r *ListRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+	if r.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+
+	return nil
+}
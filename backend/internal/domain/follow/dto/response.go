@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"github.com/maYkiss56/tunes/internal/domain/follow"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+)
+
+// UserResponse пользователь в списках подписчиков и подписок
+type UserResponse struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+func ToUserResponse(u users.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		AvatarURL: u.AvatarURL,
+	}
+}
+
+func ToUserResponses(list []users.User) []UserResponse {
+	res := make([]UserResponse, 0, len(list))
+	for _, u := range list {
+		res = append(res, ToUserResponse(u))
+	}
+	return res
+}
+
+// ProfileResponse публичный профиль; поля отношения заполняются для вошедшего пользователя
+type ProfileResponse struct {
+	UserResponse
+	FollowerCount  int   `json:"follower_count"`
+	FollowingCount int   `json:"following_count"`
+	IsFollowing    *bool `json:"is_following,omitempty"`
+	FollowsYou     *bool `json:"follows_you,omitempty"`
+	IsBlocked      *bool `json:"is_blocked,omitempty"`
+}
+
+// ToProfileResponse принимает rel == nil для анонимного просмотра
+func ToProfileResponse(u users.User, rel *follow.Relation) ProfileResponse {
+	res := ProfileResponse{
+		UserResponse:   ToUserResponse(u),
+		FollowerCount:  u.FollowerCount,
+		FollowingCount: u.FollowingCount,
+	}
+
+	if rel != nil {
+		res.IsFollowing = &rel.Following
+		res.FollowsYou = &rel.FollowsYou
+		res.IsBlocked = &rel.Blocked
+	}
+
+	return res
+}
@@ -0,0 +1,19 @@
+package follow
+
+import "errors"
+
+var (
+	ErrSelf         = errors.New("cannot follow or block yourself")
+	ErrBlocked      = errors.New("user is blocked")
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// Relation отношение просматривающего пользователя к профилю
+type Relation struct {
+	// Following просматривающий подписан на пользователя
+	Following bool
+	// FollowsYou пользователь подписан на просматривающего
+	FollowsYou bool
+	// Blocked просматривающий заблокировал пользователя
+	Blocked bool
+}
@@ -1,5 +1,9 @@
 package genre
 
+import "errors"
+
+var ErrNotFound = errors.New("genre not found")
+
 type Genre struct {
 	ID       int
 	Title    string
@@ -0,0 +1,76 @@
+package identifier
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidISRC = errors.New("invalid isrc: expected CC-XXX-YY-NNNNN")
+	ErrInvalidUPC  = errors.New("invalid upc: expected 12 or 13 digits with a valid check digit")
+	ErrInvalidMBID = errors.New("invalid mbid: expected uuid")
+
+	// ErrDuplicate возвращается репозиториями при нарушении уникальности
+	ErrDuplicate = errors.New("external identifier is already in use")
+)
+
+var isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+
+func strip(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, "-", "")
+	return strings.ReplaceAll(s, " ", "")
+}
+
+// NormalizeISRC приводит ISRC к виду без дефисов в верхнем регистре
+func NormalizeISRC(s string) (string, error) {
+	isrc := strings.ToUpper(strip(s))
+	if !isrcPattern.MatchString(isrc) {
+		return "", ErrInvalidISRC
+	}
+	return isrc, nil
+}
+
+// NormalizeUPC проверяет контрольную цифру UPC-A/EAN-13
+// и возвращает код в виде 13 цифр, чтобы UPC и EAN одного релиза совпадали
+func NormalizeUPC(s string) (string, error) {
+	code := strip(s)
+	if len(code) == 12 {
+		code = "0" + code
+	}
+	if len(code) != 13 {
+		return "", ErrInvalidUPC
+	}
+
+	sum := 0
+	for i, c := range code {
+		if c < '0' || c > '9' {
+			return "", ErrInvalidUPC
+		}
+		d := int(c - '0')
+		if i == 12 {
+			if (10-sum%10)%10 != d {
+				return "", ErrInvalidUPC
+			}
+			break
+		}
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+
+	return code, nil
+}
+
+// NormalizeMBID проверяет MusicBrainz ID и возвращает его в каноническом виде
+func NormalizeMBID(s string) (string, error) {
+	id, err := uuid.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", ErrInvalidMBID
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/maYkiss56/tunes/internal/domain/library"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// ListRequest библиотека текущего пользователя; Shelf и Kind необязательны
+type ListRequest struct {
+	Limit  int
+	Offset int
+	Shelf  library.Shelf
+	Kind   library.Kind
+}
+
+func (r *ListRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+	if r.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+
+	if r.Shelf != "" && !r.Shelf.IsValid() {
+		return library.ErrInvalidShelf
+	}
+	if r.Kind != "" && !r.Kind.IsValid() {
+		return library.ErrInvalidKind
+	}
+
+	return nil
+}
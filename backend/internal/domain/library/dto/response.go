@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/library"
+)
+
+// StatusResponse отметки пользователя; добавляется к песням и альбомам вошедшего пользователя
+type StatusResponse struct {
+	Favorite    bool          `json:"favorite"`
+	FavoritedAt *time.Time    `json:"favorited_at,omitempty"`
+	Shelf       library.Shelf `json:"shelf,omitempty"`
+	ShelvedAt   *time.Time    `json:"shelved_at,omitempty"`
+}
+
+func ToStatusResponse(s library.Status) StatusResponse {
+	return StatusResponse{
+		Favorite:    s.Favorite,
+		FavoritedAt: s.FavoritedAt,
+		Shelf:       s.Shelf,
+		ShelvedAt:   s.ShelvedAt,
+	}
+}
+
+type ArtistResponse struct {
+	ID       int    `json:"id"`
+	Nickname string `json:"nickname"`
+}
+
+type ItemResponse struct {
+	Kind      library.Kind   `json:"kind"`
+	ID        int            `json:"id"`
+	Title     string         `json:"title"`
+	Slug      string         `json:"slug,omitempty"`
+	ImageURL  string         `json:"image_url,omitempty"`
+	Artist    ArtistResponse `json:"artist"`
+	Status    StatusResponse `json:"status"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func ToItemResponses(items []library.Item) []ItemResponse {
+	res := make([]ItemResponse, 0, len(items))
+	for _, i := range items {
+		res = append(res, ItemResponse{
+			Kind:     i.Kind,
+			ID:       i.TargetID,
+			Title:    i.Title,
+			Slug:     i.Slug,
+			ImageURL: i.ImageURL,
+			Artist: ArtistResponse{
+				ID:       i.ArtistID,
+				Nickname: i.ArtistName,
+			},
+			Status:    ToStatusResponse(i.Status),
+			CreatedAt: i.CreatedAt,
+			UpdatedAt: i.UpdatedAt,
+		})
+	}
+	return res
+}
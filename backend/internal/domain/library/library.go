@@ -0,0 +1,61 @@
+package library
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrTargetNotFound = errors.New("song or album not found")
+	ErrInvalidShelf   = errors.New("shelf must be favorite, want or listened")
+	ErrInvalidKind    = errors.New("kind must be song or album")
+)
+
+// Shelf полка библиотеки. Избранное ставится отдельно, а want и listened
+// взаимоисключающие: прослушанное снимается с полки «послушать».
+type Shelf string
+
+const (
+	ShelfFavorite Shelf = "favorite"
+	ShelfWant     Shelf = "want"
+	ShelfListened Shelf = "listened"
+)
+
+func (s Shelf) IsValid() bool {
+	return s == ShelfFavorite || s == ShelfWant || s == ShelfListened
+}
+
+// Kind вид объекта в библиотеке
+type Kind string
+
+const (
+	KindSong  Kind = "song"
+	KindAlbum Kind = "album"
+)
+
+func (k Kind) IsValid() bool {
+	return k == KindSong || k == KindAlbum
+}
+
+// Status отметки пользователя на одной песне или альбоме; нулевой, если их нет
+type Status struct {
+	Favorite    bool
+	FavoritedAt *time.Time
+	// Shelf ShelfWant, ShelfListened или пустая
+	Shelf     Shelf
+	ShelvedAt *time.Time
+}
+
+// Item запись библиотеки с данными объекта для списка в профиле
+type Item struct {
+	Kind       Kind
+	TargetID   int
+	Title      string
+	Slug       string
+	ImageURL   string
+	ArtistID   int
+	ArtistName string
+	Status     Status
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
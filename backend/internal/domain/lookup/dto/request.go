@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
+)
+
+type Request struct {
+	ISRC string `json:"isrc,omitempty"`
+	UPC  string `json:"upc,omitempty"`
+	MBID string `json:"mbid,omitempty"`
+}
+
+// Validate требует ровно один идентификатор и нормализует его
+func (r *Request) Validate() error {
+	set := 0
+	for _, v := range []string{r.ISRC, r.UPC, r.MBID} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one of isrc, upc or mbid is required")
+	}
+
+	var err error
+	switch {
+	case r.ISRC != "":
+		r.ISRC, err = identifier.NormalizeISRC(r.ISRC)
+	case r.UPC != "":
+		r.UPC, err = identifier.NormalizeUPC(r.UPC)
+	case r.MBID != "":
+		r.MBID, err = identifier.NormalizeMBID(r.MBID)
+	}
+
+	return err
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	albumDTO "github.com/maYkiss56/tunes/internal/domain/album/dto"
+	artistDTO "github.com/maYkiss56/tunes/internal/domain/artist/dto"
+	songDTO "github.com/maYkiss56/tunes/internal/domain/song/dto"
+)
+
+const (
+	EntitySong   = "song"
+	EntityAlbum  = "album"
+	EntityArtist = "artist"
+)
+
+type Response struct {
+	Entity string              `json:"entity"`
+	ID     int                 `json:"id"`
+	Song   *songDTO.Response   `json:"song,omitempty"`
+	Album  *albumDTO.Response  `json:"album,omitempty"`
+	Artist *artistDTO.Response `json:"artist,omitempty"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/maYkiss56/tunes/internal/domain/lyrics"
+)
+
+type UpsertLyricsRequest struct {
+	Plain string `json:"plain"`
+	LRC   string `json:"lrc"`
+}
+
+func (r *UpsertLyricsRequest) Validate() error {
+	if strings.TrimSpace(r.Plain) == "" && strings.TrimSpace(r.LRC) == "" {
+		return lyrics.ErrEmpty
+	}
+	if len(r.Plain)+len(r.LRC) > lyrics.MaxLength {
+		return lyrics.ErrTooLong
+	}
+	if strings.TrimSpace(r.LRC) != "" {
+		if _, err := lyrics.ParseLRC(r.LRC); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/lyrics"
+)
+
+type LineResponse struct {
+	TimeMS *int64 `json:"time_ms,omitempty"`
+	Text   string `json:"text"`
+}
+
+type Response struct {
+	SongID    int            `json:"song_id"`
+	Synced    bool           `json:"synced"`
+	Lines     []LineResponse `json:"lines"`
+	Plain     string         `json:"plain"`
+	LRC       string         `json:"lrc,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func ToResponse(l lyrics.Lyrics) Response {
+	lines := l.Lines()
+	res := make([]LineResponse, 0, len(lines))
+	for _, line := range lines {
+		lr := LineResponse{Text: line.Text}
+		if line.Time != nil {
+			ms := line.Time.Milliseconds()
+			lr.TimeMS = &ms
+		}
+		res = append(res, lr)
+	}
+
+	return Response{
+		SongID:    l.SongID,
+		Synced:    l.Synced(),
+		Lines:     res,
+		Plain:     l.Plain,
+		LRC:       l.LRC,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
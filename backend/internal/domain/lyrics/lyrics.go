@@ -0,0 +1,193 @@
+package lyrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxLength ограничивает размер текста песни в байтах
+const MaxLength = 64 * 1024
+
+var (
+	ErrEmpty    = errors.New("lyrics are empty")
+	ErrTooLong  = errors.New("lyrics are too long")
+	ErrNotFound = errors.New("lyrics not found")
+)
+
+// Line — строка текста, Time == nil для несинхронизированного текста
+type Line struct {
+	Time *time.Duration
+	Text string
+}
+
+type Lyrics struct {
+	SongID    int
+	Plain     string
+	LRC       string
+	UpdatedAt time.Time
+}
+
+// NewLyrics ожидает уже проверенный LRC; обычный текст нужен для поиска,
+// поэтому при его отсутствии он восстанавливается из LRC
+func NewLyrics(songID int, plain, lrc string) (*Lyrics, error) {
+	plain = strings.TrimSpace(plain)
+	lrc = strings.TrimSpace(lrc)
+
+	if plain == "" && lrc != "" {
+		lines, err := ParseLRC(lrc)
+		if err != nil {
+			return nil, err
+		}
+		texts := make([]string, 0, len(lines))
+		for _, l := range lines {
+			texts = append(texts, l.Text)
+		}
+		plain = strings.Join(texts, "\n")
+	}
+
+	return &Lyrics{
+		SongID:    songID,
+		Plain:     plain,
+		LRC:       lrc,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+func (l *Lyrics) Synced() bool {
+	return l.LRC != ""
+}
+
+// Lines возвращает строки с метками времени, если есть LRC, иначе строки обычного текста
+func (l *Lyrics) Lines() []Line {
+	if l.Synced() {
+		if lines, err := ParseLRC(l.LRC); err == nil {
+			return lines
+		}
+	}
+
+	raw := strings.Split(l.Plain, "\n")
+	lines := make([]Line, 0, len(raw))
+	for _, text := range raw {
+		lines = append(lines, Line{Text: strings.TrimRight(text, "\r")})
+	}
+
+	return lines
+}
+
+// LRCError описывает ошибку разбора с номером строки
+type LRCError struct {
+	Line int
+	Msg  string
+}
+
+func (e *LRCError) Error() string {
+	return fmt.Sprintf("invalid lrc at line %d: %s", e.Line, e.Msg)
+}
+
+var (
+	tagPattern  = regexp.MustCompile(`^\[([a-z]+):(.*)\]$`)
+	timePattern = regexp.MustCompile(`^\[(\d{1,3}):(\d{2})(?:[.:](\d{1,3}))?\]`)
+)
+
+// ParseLRC разбирает LRC: строки вида [mm:ss.xx]текст, несколько меток
+// на одной строке и тег [offset:±ms]. Остальные теги (ar, ti, al…) пропускаются.
+// Результат отсортирован по времени.
+func ParseLRC(s string) ([]Line, error) {
+	var (
+		lines  []Line
+		offset time.Duration
+	)
+
+	for i, raw := range strings.Split(s, "\n") {
+		n := i + 1
+		row := strings.TrimSpace(raw)
+		if row == "" {
+			continue
+		}
+
+		if m := tagPattern.FindStringSubmatch(row); m != nil && !timePattern.MatchString(row) {
+			if m[1] == "offset" {
+				ms, err := strconv.Atoi(strings.TrimSpace(m[2]))
+				if err != nil {
+					return nil, &LRCError{Line: n, Msg: "invalid offset"}
+				}
+				// положительный offset означает, что текст показывается раньше
+				offset = -time.Duration(ms) * time.Millisecond
+			}
+			continue
+		}
+
+		var stamps []time.Duration
+		for {
+			m := timePattern.FindStringSubmatch(row)
+			if m == nil {
+				break
+			}
+
+			d, err := parseStamp(m[1], m[2], m[3])
+			if err != nil {
+				return nil, &LRCError{Line: n, Msg: err.Error()}
+			}
+			stamps = append(stamps, d)
+			row = row[len(m[0]):]
+		}
+
+		if len(stamps) == 0 {
+			return nil, &LRCError{Line: n, Msg: "missing timestamp"}
+		}
+
+		text := strings.TrimSpace(row)
+		for _, d := range stamps {
+			t := d
+			lines = append(lines, Line{Time: &t, Text: text})
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, &LRCError{Line: 1, Msg: "no timed lines"}
+	}
+
+	for i := range lines {
+		t := *lines[i].Time + offset
+		if t < 0 {
+			t = 0
+		}
+		lines[i].Time = &t
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return *lines[i].Time < *lines[j].Time
+	})
+
+	return lines, nil
+}
+
+func parseStamp(min, sec, frac string) (time.Duration, error) {
+	m, _ := strconv.Atoi(min)
+	s, _ := strconv.Atoi(sec)
+	if s >= 60 {
+		return 0, errors.New("seconds must be below 60")
+	}
+
+	var ms int
+	if frac != "" {
+		f, _ := strconv.Atoi(frac)
+		switch len(frac) {
+		case 1:
+			ms = f * 100
+		case 2:
+			ms = f * 10
+		default:
+			ms = f
+		}
+	}
+
+	return time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second +
+		time.Duration(ms)*time.Millisecond, nil
+}
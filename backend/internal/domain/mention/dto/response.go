@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/maYkiss56/tunes/internal/domain/mention"
+
+type Response struct {
+	Kind  mention.Kind `json:"kind"`
+	ID    int          `json:"id"`
+	Label string       `json:"label"`
+	Href  string       `json:"href"`
+}
+
+func ToResponses(mentions []mention.Mention) []Response {
+	res := make([]Response, 0, len(mentions))
+	for _, m := range mentions {
+		res = append(res, Response{
+			Kind:  m.Kind,
+			ID:    m.ID,
+			Label: m.Label,
+			Href:  m.Href(),
+		})
+	}
+	return res
+}
@@ -0,0 +1,114 @@
+package mention
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/maYkiss56/tunes/internal/markdown"
+)
+
+// Kind вид сущности, на которую ссылается упоминание
+type Kind string
+
+const (
+	KindUser   Kind = "user"
+	KindSong   Kind = "song"
+	KindAlbum  Kind = "album"
+	KindArtist Kind = "artist"
+)
+
+// MaxRefs больше стольких упоминаний из одного текста не сохраняется
+const MaxRefs = 50
+
+func (k Kind) IsValid() bool {
+	switch k {
+	case KindUser, KindSong, KindAlbum, KindArtist:
+		return true
+	default:
+		return false
+	}
+}
+
+// Ref упоминание, как оно записано в тексте: @alice даёт {user alice},
+// [[artist:metallica]] даёт {artist metallica}. Key в нижнем регистре,
+// у песен, альбомов и исполнителей это id или слаг.
+type Ref struct {
+	Kind Kind
+	Key  string
+}
+
+// Token ключ упоминания, по которому markdown.Links находит ссылку
+func (r Ref) Token() string {
+	return string(r.Kind) + ":" + r.Key
+}
+
+// Mention упоминание, сопоставленное с существующей сущностью
+type Mention struct {
+	Kind  Kind
+	ID    int
+	Token string
+	// Label имя пользователя, название песни или альбома, псевдоним исполнителя
+	Label string
+}
+
+// Href адрес страницы упомянутой сущности
+func (m Mention) Href() string {
+	if m.Kind == KindUser {
+		return "/users/" + url.PathEscape(m.Label)
+	}
+	return "/" + string(m.Kind) + "s/" + strconv.Itoa(m.ID)
+}
+
+// Text текст ссылки на упомянутую сущность
+func (m Mention) Text() string {
+	if m.Kind == KindUser {
+		return "@" + m.Label
+	}
+	return m.Label
+}
+
+// Parse находит упоминания в тексте по правилам разметки, поэтому
+// упоминания в коде не учитываются. Повторы и неизвестные виды отбрасываются.
+func Parse(text string) []Ref {
+	var refs []Ref
+
+	for _, token := range markdown.Mentions(text) {
+		kind, key, _ := strings.Cut(token, ":")
+		if !Kind(kind).IsValid() {
+			continue
+		}
+
+		refs = append(refs, Ref{Kind: Kind(kind), Key: key})
+		if len(refs) == MaxRefs {
+			break
+		}
+	}
+
+	return refs
+}
+
+// Links ссылки для рендеринга текста с упоминаниями
+func Links(mentions []Mention) markdown.Links {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	links := make(markdown.Links, len(mentions))
+	for _, m := range mentions {
+		links[m.Token] = markdown.Link{Href: m.Href(), Text: m.Text()}
+	}
+
+	return links
+}
+
+// UserIDs возвращает упомянутых пользователей
+func UserIDs(mentions []Mention) []int {
+	var ids []int
+	for _, m := range mentions {
+		if m.Kind == KindUser {
+			ids = append(ids, m.ID)
+		}
+	}
+	return ids
+}
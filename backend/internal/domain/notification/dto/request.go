@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/maYkiss56/tunes/internal/domain/notification"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	// maxReadIDs больше уведомлений одним запросом не отмечается
+	maxReadIDs = 100
+)
+
+// ListRequest страница уведомлений; Unread оставляет непрочитанные, Type один вид
+type ListRequest struct {
+	Limit  int
+	Offset int
+	Unread bool
+	Type   notification.Type
+}
+
+func (r *ListRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+	if r.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+	if r.Type != "" && !r.Type.IsValid() {
+		return errors.New("invalid notification type")
+	}
+
+	return nil
+}
+
+// ReadRequest отмечает прочитанными уведомления из IDs, пустой список отмечает все
+type ReadRequest struct {
+	IDs []int `json:"ids"`
+}
+
+func (r *ReadRequest) Validate() error {
+	if len(r.IDs) > maxReadIDs {
+		return errors.New("too many notification ids")
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/notification"
+	userDTO "github.com/maYkiss56/tunes/internal/domain/users/dto"
+)
+
+type Response struct {
+	ID        int               `json:"id"`
+	Type      notification.Type `json:"type"`
+	Actor     *userDTO.Response `json:"actor,omitempty"`
+	Payload   json.RawMessage   `json:"payload"`
+	IsRead    bool              `json:"is_read"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func ToResponse(n notification.Notification) Response {
+	res := Response{
+		ID:        n.ID,
+		Type:      n.Type,
+		Payload:   n.Payload,
+		IsRead:    n.ReadAt != nil,
+		CreatedAt: n.CreatedAt,
+	}
+
+	if n.Actor != nil {
+		res.Actor = &userDTO.Response{
+			ID:        n.Actor.ID,
+			Username:  n.Actor.Username,
+			AvatarURL: n.Actor.AvatarURL,
+		}
+	}
+
+	return res
+}
+
+func ToResponses(list []notification.Notification) []Response {
+	res := make([]Response, 0, len(list))
+	for _, n := range list {
+		res = append(res, ToResponse(n))
+	}
+	return res
+}
+
+type UnreadResponse struct {
+	Unread int `json:"unread"`
+}
+
+type ReadResponse struct {
+	Updated int64 `json:"updated"`
+}
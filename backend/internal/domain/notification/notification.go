@@ -0,0 +1,98 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/users"
+)
+
+var (
+	ErrNotFound       = errors.New("notification not found")
+	ErrTooManyStreams = errors.New("too many open notification streams")
+)
+
+// Type вид уведомления, определяет содержимое Payload
+type Type string
+
+const (
+	// TypeReply ответ на комментарий пользователя или комментарий к его рецензии, ReplyPayload
+	TypeReply Type = "reply"
+	// TypeMention упоминание в рецензии или биографии, MentionPayload
+	TypeMention Type = "mention"
+	// TypeReviewApproved и TypeReviewRejected решение модератора по рецензии, ModerationPayload
+	TypeReviewApproved Type = "review_approved"
+	TypeReviewRejected Type = "review_rejected"
+	// TypeFollow новый подписчик, он же ActorID; Payload пуст
+	TypeFollow Type = "follow"
+	// TypeRelease новый релиз исполнителя из подписок, содержимое release.Payload
+	TypeRelease Type = "release"
+)
+
+func (t Type) IsValid() bool {
+	switch t {
+	case TypeReply, TypeMention, TypeReviewApproved, TypeReviewRejected, TypeFollow, TypeRelease:
+		return true
+	}
+	return false
+}
+
+// Event сообщение о новом уведомлении, которое рассылается всем экземплярам
+type Event struct {
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+}
+
+type Notification struct {
+	ID     int
+	UserID int
+	Type   Type
+	// ActorID пользователь, действие которого вызвало уведомление, nil для системных
+	ActorID *int
+	// Actor заполняется при чтении
+	Actor     *users.User
+	Payload   json.RawMessage
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MentionPayload указывает текст, в котором упомянут пользователь:
+// рецензию или биографию исполнителя
+type MentionPayload struct {
+	ReviewID int `json:"review_id,omitempty"`
+	ArtistID int `json:"artist_id,omitempty"`
+}
+
+// ReplyPayload указывает новый комментарий; ParentID nil для комментария к рецензии
+type ReplyPayload struct {
+	ReviewID  int  `json:"review_id"`
+	CommentID int  `json:"comment_id"`
+	ParentID  *int `json:"parent_id,omitempty"`
+}
+
+// ModerationPayload указывает рецензию и причину решения модератора
+type ModerationPayload struct {
+	ReviewID int    `json:"review_id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// New готовит уведомление пользователю userID; actorID 0 означает системное
+func New(userID, actorID int, t Type, payload any) (*Notification, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Notification{
+		UserID:    userID,
+		Type:      t,
+		Payload:   data,
+		CreatedAt: time.Now(),
+	}
+	if actorID != 0 {
+		n.ActorID = &actorID
+	}
+
+	return n, nil
+}
@@ -0,0 +1,180 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/maYkiss56/tunes/internal/domain/playlist"
+)
+
+const (
+	maxTitleLength       = 120
+	maxDescriptionLength = 2000
+	maxNoteLength        = 500
+
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Порядок вывода публичных списков
+const (
+	SortRecent  = "recent"
+	SortPopular = "popular"
+)
+
+type CreateRequest struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Visibility  playlist.Visibility `json:"visibility"`
+	// Ranked по умолчанию true
+	Ranked *bool `json:"ranked,omitempty"`
+}
+
+func (r *CreateRequest) Validate() error {
+	r.Title = strings.TrimSpace(r.Title)
+	if r.Title == "" {
+		return errors.New("title is required")
+	}
+	if len(r.Title) > maxTitleLength {
+		return errors.New("title is too long")
+	}
+	if len(r.Description) > maxDescriptionLength {
+		return errors.New("description is too long")
+	}
+
+	if r.Visibility == "" {
+		r.Visibility = playlist.VisibilityPublic
+	}
+	if !r.Visibility.IsValid() {
+		return errors.New("visibility must be public, unlisted or private")
+	}
+
+	if r.Ranked == nil {
+		ranked := true
+		r.Ranked = &ranked
+	}
+
+	return nil
+}
+
+type UpdateRequest struct {
+	Title       *string              `json:"title,omitempty"`
+	Description *string              `json:"description,omitempty"`
+	Visibility  *playlist.Visibility `json:"visibility,omitempty"`
+	Ranked      *bool                `json:"ranked,omitempty"`
+}
+
+func (r *UpdateRequest) Validate() error {
+	if r.Title != nil {
+		title := strings.TrimSpace(*r.Title)
+		if title == "" {
+			return errors.New("title must not be empty")
+		}
+		if len(title) > maxTitleLength {
+			return errors.New("title is too long")
+		}
+		r.Title = &title
+	}
+	if r.Description != nil && len(*r.Description) > maxDescriptionLength {
+		return errors.New("description is too long")
+	}
+	if r.Visibility != nil && !r.Visibility.IsValid() {
+		return errors.New("visibility must be public, unlisted or private")
+	}
+
+	return nil
+}
+
+// AddItemRequest добавляет song_id или album_id; без position позиция встаёт в конец
+type AddItemRequest struct {
+	SongID   int    `json:"song_id,omitempty"`
+	AlbumID  int    `json:"album_id,omitempty"`
+	Note     string `json:"note"`
+	Position *int   `json:"position,omitempty"`
+}
+
+func (r *AddItemRequest) Validate() error {
+	if (r.SongID > 0) == (r.AlbumID > 0) {
+		return errors.New("exactly one of song_id or album_id is required")
+	}
+	if len(r.Note) > maxNoteLength {
+		return errors.New("note is too long")
+	}
+	if r.Position != nil && *r.Position < 1 {
+		return errors.New("position must be positive")
+	}
+
+	return nil
+}
+
+func (r *AddItemRequest) Target() playlist.Target {
+	if r.AlbumID > 0 {
+		return playlist.Target{Kind: playlist.KindAlbum, ID: r.AlbumID}
+	}
+	return playlist.Target{Kind: playlist.KindSong, ID: r.SongID}
+}
+
+// UpdateItemRequest меняет заметку и переносит позицию на новое место
+type UpdateItemRequest struct {
+	Note     *string `json:"note,omitempty"`
+	Position *int    `json:"position,omitempty"`
+}
+
+func (r *UpdateItemRequest) Validate() error {
+	if r.Note != nil && len(*r.Note) > maxNoteLength {
+		return errors.New("note is too long")
+	}
+	if r.Position != nil && *r.Position < 1 {
+		return errors.New("position must be positive")
+	}
+
+	return nil
+}
+
+// OrderRequest новый порядок всех позиций списка
+type OrderRequest struct {
+	ItemIDs []int `json:"item_ids"`
+}
+
+func (r *OrderRequest) Validate() error {
+	if len(r.ItemIDs) == 0 {
+		return errors.New("item_ids is required")
+	}
+	if len(r.ItemIDs) > playlist.MaxItems {
+		return playlist.ErrInvalidOrder
+	}
+
+	return nil
+}
+
+// ListRequest подборка публичных списков; UserID, SongID и AlbumID сужают её
+// до списков пользователя или списков с этой песней или альбомом
+type ListRequest struct {
+	Limit   int
+	Offset  int
+	Sort    string
+	UserID  int
+	SongID  int
+	AlbumID int
+}
+
+func (r *ListRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+	if r.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+
+	if r.Sort == "" {
+		r.Sort = SortRecent
+	}
+	if r.Sort != SortRecent && r.Sort != SortPopular {
+		return errors.New("sort must be recent or popular")
+	}
+
+	return nil
+}
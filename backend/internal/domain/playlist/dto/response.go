@@ -0,0 +1,128 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/playlist"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+)
+
+type UserResponse struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+func ToUserResponse(u users.User) UserResponse {
+	return UserResponse{
+		ID:        u.ID,
+		Username:  u.Username,
+		AvatarURL: u.AvatarURL,
+	}
+}
+
+func ToUserResponses(list []users.User) []UserResponse {
+	res := make([]UserResponse, 0, len(list))
+	for _, u := range list {
+		res = append(res, ToUserResponse(u))
+	}
+	return res
+}
+
+// Response список; IsLiked и CanEdit заполняются для вошедшего пользователя,
+// Items только при открытии одного списка
+type Response struct {
+	ID          int                 `json:"id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Visibility  playlist.Visibility `json:"visibility"`
+	Ranked      bool                `json:"ranked"`
+	Owner       UserResponse        `json:"owner"`
+	ItemCount   int                 `json:"item_count"`
+	LikeCount   int                 `json:"like_count"`
+	IsLiked     *bool               `json:"is_liked,omitempty"`
+	CanEdit     *bool               `json:"can_edit,omitempty"`
+	Items       []ItemResponse      `json:"items,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+func ToResponse(l playlist.List) Response {
+	return Response{
+		ID:          l.ID,
+		Title:       l.Title,
+		Description: l.Description,
+		Visibility:  l.Visibility,
+		Ranked:      l.Ranked,
+		Owner:       ToUserResponse(l.Owner),
+		ItemCount:   l.ItemCount,
+		LikeCount:   l.LikeCount,
+		CreatedAt:   l.CreatedAt,
+		UpdatedAt:   l.UpdatedAt,
+	}
+}
+
+func ToResponses(list []playlist.List) []Response {
+	res := make([]Response, 0, len(list))
+	for _, l := range list {
+		res = append(res, ToResponse(l))
+	}
+	return res
+}
+
+type ArtistResponse struct {
+	ID       int    `json:"id"`
+	Nickname string `json:"nickname"`
+}
+
+type TargetResponse struct {
+	Kind     playlist.Kind  `json:"kind"`
+	ID       int            `json:"id"`
+	Title    string         `json:"title"`
+	Slug     string         `json:"slug,omitempty"`
+	ImageURL string         `json:"image_url,omitempty"`
+	Artist   ArtistResponse `json:"artist"`
+}
+
+type ItemResponse struct {
+	ID        int            `json:"id"`
+	Position  int            `json:"position"`
+	Note      string         `json:"note"`
+	Target    TargetResponse `json:"target"`
+	AddedBy   *int           `json:"added_by,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func ToItemResponse(i playlist.Item) ItemResponse {
+	return ItemResponse{
+		ID:       i.ID,
+		Position: i.Position,
+		Note:     i.Note,
+		Target: TargetResponse{
+			Kind:     i.Target.Kind,
+			ID:       i.Target.ID,
+			Title:    i.Title,
+			Slug:     i.Slug,
+			ImageURL: i.ImageURL,
+			Artist: ArtistResponse{
+				ID:       i.ArtistID,
+				Nickname: i.ArtistName,
+			},
+		},
+		AddedBy:   i.AddedBy,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func ToItemResponses(items []playlist.Item) []ItemResponse {
+	res := make([]ItemResponse, 0, len(items))
+	for _, i := range items {
+		res = append(res, ToItemResponse(i))
+	}
+	return res
+}
+
+type LikeResponse struct {
+	LikeCount int  `json:"like_count"`
+	IsLiked   bool `json:"is_liked"`
+}
@@ -0,0 +1,129 @@
+package playlist
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/users"
+)
+
+// MaxItems больше позиций в один список не добавляется
+const MaxItems = 500
+
+var (
+	ErrNotFound       = errors.New("list not found")
+	ErrForbidden      = errors.New("not allowed to change this list")
+	ErrItemNotFound   = errors.New("list item not found")
+	ErrItemExists     = errors.New("item is already in the list")
+	ErrTargetNotFound = errors.New("song or album not found")
+	ErrTooManyItems   = errors.New("list is full")
+	ErrInvalidOrder   = errors.New("order must list every item of the list exactly once")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrOwnerEditor    = errors.New("owner is already an editor of the list")
+	ErrBlocked        = errors.New("user is blocked")
+)
+
+// Visibility кому виден список
+type Visibility string
+
+const (
+	// VisibilityPublic список виден всем и выводится в подборках
+	VisibilityPublic Visibility = "public"
+	// VisibilityUnlisted список открывается по ссылке, но в подборках не выводится
+	VisibilityUnlisted Visibility = "unlisted"
+	// VisibilityPrivate список видят только владелец и соавторы
+	VisibilityPrivate Visibility = "private"
+)
+
+func (v Visibility) IsValid() bool {
+	return v == VisibilityPublic || v == VisibilityUnlisted || v == VisibilityPrivate
+}
+
+// Role права пользователя на список
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleEditor
+	RoleOwner
+)
+
+func (r Role) CanEdit() bool {
+	return r >= RoleEditor
+}
+
+type List struct {
+	ID          int
+	OwnerID     int
+	Owner       users.User
+	Title       string
+	Description string
+	Visibility  Visibility
+	Ranked      bool
+	ItemCount   int
+	LikeCount   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewList(ownerID int, title, description string, visibility Visibility, ranked bool) *List {
+	now := time.Now()
+
+	return &List{
+		OwnerID:     ownerID,
+		Title:       title,
+		Description: description,
+		Visibility:  visibility,
+		Ranked:      ranked,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// RoleOf возвращает права пользователя; userID 0 означает анонимный просмотр
+func (l *List) RoleOf(userID int, isEditor bool) Role {
+	switch {
+	case userID == 0:
+		return RoleNone
+	case userID == l.OwnerID:
+		return RoleOwner
+	case isEditor:
+		return RoleEditor
+	}
+	return RoleNone
+}
+
+// VisibleTo сообщает, что пользователь с правами role может открыть список
+func (l *List) VisibleTo(role Role) bool {
+	return l.Visibility != VisibilityPrivate || role.CanEdit()
+}
+
+// Kind вид позиции списка
+type Kind string
+
+const (
+	KindSong  Kind = "song"
+	KindAlbum Kind = "album"
+)
+
+// Target песня или альбом в позиции списка
+type Target struct {
+	Kind Kind
+	ID   int
+}
+
+type Item struct {
+	ID       int
+	ListID   int
+	Target   Target
+	Position int
+	Note     string
+	AddedBy  *int
+	// поля объекта заполняются при чтении
+	Title      string
+	Slug       string
+	ImageURL   string
+	ArtistID   int
+	ArtistName string
+	CreatedAt  time.Time
+}
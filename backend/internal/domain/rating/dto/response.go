@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/rating"
+)
+
+type CountersResponse struct {
+	Likes          int   `json:"likes"`
+	Dislikes       int   `json:"dislikes"`
+	Rating         int   `json:"rating"`
+	ScoreCount     int   `json:"score_count"`
+	ScoreSum       int   `json:"score_sum"`
+	ScoreHistogram []int `json:"score_histogram"`
+}
+
+type SummaryResponse struct {
+	LikeCount    int      `json:"like_count"`
+	DislikeCount int      `json:"dislike_count"`
+	Rating       int      `json:"rating"`
+	ScoreAverage *float64 `json:"score_average"`
+	ScoreCount   int      `json:"score_count"`
+}
+
+// ToSummaryResponse возвращает nil для объектов, прочитанных без сводки
+func ToSummaryResponse(s *rating.Summary) *SummaryResponse {
+	if s == nil {
+		return nil
+	}
+
+	return &SummaryResponse{
+		LikeCount:    s.Likes,
+		DislikeCount: s.Dislikes,
+		Rating:       s.Rating,
+		ScoreAverage: s.ScoreAverage,
+		ScoreCount:   s.ScoreCount,
+	}
+}
+
+type DriftResponse struct {
+	Kind   string           `json:"kind"`
+	ID     int              `json:"id"`
+	Stored CountersResponse `json:"stored"`
+	Actual CountersResponse `json:"actual"`
+}
+
+type CheckResponse struct {
+	Checked    int             `json:"checked"`
+	Drifted    int             `json:"drifted"`
+	Fixed      int64           `json:"fixed"`
+	Drifts     []DriftResponse `json:"drifts"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+}
+
+func ToCheckResponse(r rating.CheckResult) CheckResponse {
+	drifts := make([]DriftResponse, 0, len(r.Drifts))
+	for _, d := range r.Drifts {
+		drifts = append(drifts, DriftResponse{
+			Kind:   string(d.Kind),
+			ID:     d.ID,
+			Stored: toCountersResponse(d.Stored),
+			Actual: toCountersResponse(d.Actual),
+		})
+	}
+
+	return CheckResponse{
+		Checked:    r.Checked,
+		Drifted:    r.Drifted,
+		Fixed:      r.Fixed,
+		Drifts:     drifts,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+	}
+}
+
+func toCountersResponse(c rating.Counters) CountersResponse {
+	return CountersResponse{
+		Likes:          c.Likes,
+		Dislikes:       c.Dislikes,
+		Rating:         c.Rating,
+		ScoreCount:     c.ScoreCount,
+		ScoreSum:       c.ScoreSum,
+		ScoreHistogram: c.ScoreHistogram,
+	}
+}
@@ -0,0 +1,92 @@
+package rating
+
+import (
+	"slices"
+	"time"
+)
+
+// MaxReportedDrifts ограничивает число расхождений в отчёте, счётчик Drifted при этом полный
+const MaxReportedDrifts = 100
+
+// Kind вид объекта с денормализованными счётчиками рецензий
+type Kind string
+
+const (
+	KindSong   Kind = "song"
+	KindAlbum  Kind = "album"
+	KindArtist Kind = "artist"
+)
+
+// Kinds виды объектов в порядке проверки
+var Kinds = []Kind{KindSong, KindAlbum, KindArtist}
+
+// Counters денормализованные счётчики песни, альбома или исполнителя
+type Counters struct {
+	Likes          int
+	Dislikes       int
+	Rating         int
+	ScoreCount     int
+	ScoreSum       int
+	ScoreHistogram []int
+}
+
+func (c Counters) Equal(o Counters) bool {
+	return c.Likes == o.Likes &&
+		c.Dislikes == o.Dislikes &&
+		c.Rating == o.Rating &&
+		c.ScoreCount == o.ScoreCount &&
+		c.ScoreSum == o.ScoreSum &&
+		slices.Equal(c.ScoreHistogram, o.ScoreHistogram)
+}
+
+// Summary сводка рецензий альбома или исполнителя для ответов API
+type Summary struct {
+	Likes    int
+	Dislikes int
+	Rating   int
+	// ScoreAverage == nil, пока нет ни одной оценки
+	ScoreAverage *float64
+	ScoreCount   int
+}
+
+// TargetCounters сохранённые в каталоге счётчики объекта и посчитанные по рецензиям
+type TargetCounters struct {
+	Kind   Kind
+	ID     int
+	Stored Counters
+	Actual Counters
+}
+
+func (s TargetCounters) Drifted() bool {
+	return !s.Stored.Equal(s.Actual)
+}
+
+// CheckResult итог проверки согласованности счётчиков
+type CheckResult struct {
+	Checked    int
+	Drifted    int
+	Fixed      int64
+	Drifts     []TargetCounters
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Add учитывает пачку проверенных объектов одного вида и возвращает идентификаторы разошедшихся
+func (r *CheckResult) Add(batch []TargetCounters) []int {
+	var ids []int
+
+	for _, s := range batch {
+		r.Checked++
+		if !s.Drifted() {
+			continue
+		}
+
+		r.Drifted++
+		ids = append(ids, s.ID)
+		if len(r.Drifts) < MaxReportedDrifts {
+			r.Drifts = append(r.Drifts, s)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/maYkiss56/tunes/internal/domain/release"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type ListRequest struct {
+	Limit  int
+	Offset int
+}
+
+func (r *ListRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+	if r.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+
+	return nil
+}
+
+type DigestRequest struct {
+	Frequency release.Digest `json:"frequency"`
+}
+
+func (r *DigestRequest) Validate() error {
+	if !r.Frequency.IsValid() {
+		return release.ErrInvalidDigest
+	}
+
+	return nil
+}
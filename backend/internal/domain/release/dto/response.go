@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/release"
+)
+
+type ArtistResponse struct {
+	ID       int    `json:"id"`
+	Nickname string `json:"nickname"`
+	Slug     string `json:"slug,omitempty"`
+}
+
+type Response struct {
+	Kind      release.Kind   `json:"kind"`
+	ID        int            `json:"id"`
+	Title     string         `json:"title"`
+	Slug      string         `json:"slug,omitempty"`
+	ImageURL  string         `json:"image_url"`
+	Artist    ArtistResponse `json:"artist"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func ToResponse(r release.Release) Response {
+	return Response{
+		Kind:     r.Kind,
+		ID:       r.TargetID,
+		Title:    r.Title,
+		Slug:     r.Slug,
+		ImageURL: r.ImageURL,
+		Artist: ArtistResponse{
+			ID:       r.Artist.ID,
+			Nickname: r.Artist.Nickname,
+			Slug:     r.Artist.Slug,
+		},
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func ToResponses(list []release.Release) []Response {
+	res := make([]Response, 0, len(list))
+	for _, r := range list {
+		res = append(res, ToResponse(r))
+	}
+	return res
+}
+
+// FollowResponse состояние подписки на исполнителя
+type FollowResponse struct {
+	ArtistID      int  `json:"artist_id"`
+	IsFollowing   bool `json:"is_following"`
+	FollowerCount int  `json:"follower_count"`
+}
+
+type DigestResponse struct {
+	Frequency release.Digest `json:"frequency"`
+}
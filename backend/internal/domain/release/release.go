@@ -0,0 +1,109 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrArtistNotFound = errors.New("artist not found")
+	ErrInvalidDigest  = errors.New("invalid digest frequency: expected off, daily or weekly")
+)
+
+// Kind вид релиза
+type Kind string
+
+const (
+	KindSong  Kind = "song"
+	KindAlbum Kind = "album"
+)
+
+// Artist исполнитель релиза в объёме, нужном спискам и уведомлениям
+type Artist struct {
+	ID       int
+	Nickname string
+	Slug     string
+}
+
+// Release новая песня или альбом исполнителя
+type Release struct {
+	ID int64
+	// Kind и TargetID указывают песню или альбом
+	Kind      Kind
+	TargetID  int
+	Title     string
+	Slug      string
+	ImageURL  string
+	Artist    Artist
+	CreatedAt time.Time
+	// Deleted релиз убран в корзину до рассылки уведомлений
+	Deleted bool
+}
+
+// Path адрес релиза на сайте
+func (r Release) Path() string {
+	return fmt.Sprintf("/%ss/%d", r.Kind, r.TargetID)
+}
+
+// Digest частота писем со сводкой релизов
+type Digest string
+
+const (
+	DigestOff    Digest = "off"
+	DigestDaily  Digest = "daily"
+	DigestWeekly Digest = "weekly"
+)
+
+func (d Digest) IsValid() bool {
+	return d == DigestOff || d == DigestDaily || d == DigestWeekly
+}
+
+// Period промежуток между письмами, 0 для DigestOff
+func (d Digest) Period() time.Duration {
+	switch d {
+	case DigestDaily:
+		return 24 * time.Hour
+	case DigestWeekly:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+// Subscriber получатель сводки
+type Subscriber struct {
+	UserID   int
+	Email    string
+	Username string
+	Digest   Digest
+	// SentAt время прошлой сводки, nil если писем ещё не было
+	SentAt *time.Time
+}
+
+// Since начало периода сводки: прошлое письмо, но не раньше одного периода назад
+func (s Subscriber) Since(now time.Time) time.Time {
+	since := now.Add(-s.Digest.Period())
+	if s.SentAt != nil && s.SentAt.After(since) {
+		return *s.SentAt
+	}
+	return since
+}
+
+// Payload содержимое уведомления о релизе
+type Payload struct {
+	Kind     Kind   `json:"kind"`
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	ArtistID int    `json:"artist_id"`
+	Artist   string `json:"artist"`
+}
+
+func (r Release) Payload() Payload {
+	return Payload{
+		Kind:     r.Kind,
+		ID:       r.TargetID,
+		Title:    r.Title,
+		ArtistID: r.Artist.ID,
+		Artist:   r.Artist.Nickname,
+	}
+}
@@ -0,0 +1,42 @@
+package review
+
+// RatingDelta изменение счётчиков песни от создания, правки или удаления рецензии
+type RatingDelta struct {
+	Likes      int
+	Dislikes   int
+	ScoreCount int
+	ScoreSum   int
+	// Histogram[i] изменение числа оценок MinScore+i
+	Histogram [MaxScore - MinScore + 1]int
+}
+
+// Delta считает изменение счётчиков при переходе рецензии из before в after,
+// nil означает отсутствие рецензии. Невалидные и неопубликованные рецензии в счётчиках не участвуют.
+func Delta(before, after *Review) RatingDelta {
+	var d RatingDelta
+	d.add(before, -1)
+	d.add(after, 1)
+	return d
+}
+
+func (d RatingDelta) IsZero() bool {
+	return d == RatingDelta{}
+}
+
+func (d *RatingDelta) add(r *Review, sign int) {
+	if r == nil || !r.IsValid || !r.IsPublished() {
+		return
+	}
+
+	if r.IsLike {
+		d.Likes += sign
+	} else {
+		d.Dislikes += sign
+	}
+
+	if r.Score != nil && IsValidScore(*r.Score) {
+		d.ScoreCount += sign
+		d.ScoreSum += sign * *r.Score
+		d.Histogram[*r.Score-MinScore] += sign
+	}
+}
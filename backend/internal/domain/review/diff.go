@@ -0,0 +1,137 @@
+package review
+
+import (
+	"strings"
+	"unicode"
+)
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// maxDiffCells ограничивает таблицу LCS; на более длинных правках
+// изменённая середина выводится целиком как удаление и вставка
+const maxDiffCells = 1 << 20
+
+type DiffChunk struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// Diff сравнивает тексты по словам, пробелы считаются отдельными токенами,
+// поэтому склейка Text всех чанков кроме DiffDelete даёт новый текст
+func Diff(from, to string) []DiffChunk {
+	a, b := tokenize(from), tokenize(to)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var chunks []DiffChunk
+	add := func(op DiffOp, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(chunks); n > 0 && chunks[n-1].Op == op {
+			chunks[n-1].Text += text
+			return
+		}
+		chunks = append(chunks, DiffChunk{Op: op, Text: text})
+	}
+
+	add(DiffEqual, strings.Join(a[:prefix], ""))
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		add(DiffDelete, strings.Join(midA, ""))
+		add(DiffInsert, strings.Join(midB, ""))
+	} else {
+		for _, c := range lcsDiff(midA, midB) {
+			add(c.Op, c.Text)
+		}
+	}
+
+	add(DiffEqual, strings.Join(a[len(a)-suffix:], ""))
+
+	return chunks
+}
+
+// lcsDiff строит поштучный дифф по наибольшей общей подпоследовательности
+func lcsDiff(a, b []string) []DiffChunk {
+	n, m := len(a), len(b)
+
+	// lcs[i][j] длина LCS для a[i:] и b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	chunks := make([]DiffChunk, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			chunks = append(chunks, DiffChunk{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			chunks = append(chunks, DiffChunk{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			chunks = append(chunks, DiffChunk{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		chunks = append(chunks, DiffChunk{Op: DiffDelete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		chunks = append(chunks, DiffChunk{Op: DiffInsert, Text: b[j]})
+	}
+
+	return chunks
+}
+
+// tokenize делит текст на слова и группы пробельных символов
+func tokenize(s string) []string {
+	var tokens []string
+
+	start := 0
+	for i, r := range s {
+		if i > start && unicode.IsSpace(r) != isSpaceAt(s, start) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+
+	return tokens
+}
+
+func isSpaceAt(s string, i int) bool {
+	for _, r := range s[i:] {
+		return unicode.IsSpace(r)
+	}
+	return false
+}
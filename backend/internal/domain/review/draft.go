@@ -0,0 +1,46 @@
+package review
+
+import (
+	"errors"
+	"time"
+)
+
+// Status стадия публикации рецензии
+type Status string
+
+const (
+	// StatusDraft черновик виден только автору
+	StatusDraft Status = "draft"
+	// StatusScheduled черновик, который планировщик опубликует в PublishAt
+	StatusScheduled Status = "scheduled"
+	StatusPublished Status = "published"
+)
+
+var (
+	ErrAlreadyPublished = errors.New("review is already published")
+	ErrNotAuthor        = errors.New("only the author can change the review")
+)
+
+func (r *Review) IsPublished() bool {
+	return r.Status == StatusPublished
+}
+
+// Stage задаёт стадию новой рецензии: publishAt в будущем откладывает
+// публикацию, draft оставляет черновик, иначе рецензия публикуется сразу
+func (r *Review) Stage(draft bool, publishAt *time.Time, now time.Time) {
+	switch {
+	case publishAt != nil && publishAt.After(now):
+		r.Status = StatusScheduled
+		r.PublishAt = publishAt
+	case draft:
+		r.Status = StatusDraft
+	default:
+		r.Status = StatusPublished
+		r.PublishedAt = &now
+	}
+}
+
+// IsDue сообщает, что запланированную рецензию пора публиковать
+func (r *Review) IsDue(now time.Time) bool {
+	return r.Status == StatusScheduled && r.PublishAt != nil && !r.PublishAt.After(now)
+}
@@ -1,30 +1,134 @@
 package dto
 
-import "errors"
+import (
+	"errors"
+	"time"
 
+	"github.com/maYkiss56/tunes/internal/domain/moderation"
+	"github.com/maYkiss56/tunes/internal/domain/review"
+)
+
+// CreateReviewRequest ссылается ровно на один объект: song_id, album_id или artist_id
 type CreateReviewRequest struct {
-	UserID  int    `json:"user_id"`
-	SongID  int    `json:"song_id"`
-	Body    string `json:"body"`
-	IsLike  bool   `json:"is_like"`
-	IsValid bool   `json:"is_valid"`
+	UserID   int    `json:"user_id"`
+	SongID   int    `json:"song_id,omitempty"`
+	AlbumID  int    `json:"album_id,omitempty"`
+	ArtistID int    `json:"artist_id,omitempty"`
+	Body     string `json:"body"`
+	IsLike   bool   `json:"is_like"`
+	IsValid  bool   `json:"is_valid"`
+	Score    *int   `json:"score,omitempty"`
+	// Draft сохраняет черновик, PublishAt в будущем откладывает публикацию
+	Draft     bool       `json:"draft,omitempty"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
 }
 
 func (r *CreateReviewRequest) Validate() error {
 	if r.UserID == 0 {
 		return errors.New("user_id is required")
 	}
-	if r.SongID == 0 {
-		return errors.New("song_id is required")
+	if _, err := r.Target(); err != nil {
+		return err
 	}
 	if r.Body == "" {
 		return errors.New("text review is required")
 	}
+	if r.Score != nil && !review.IsValidScore(*r.Score) {
+		return review.ErrInvalidScore
+	}
 	return nil
 }
 
+func (r *CreateReviewRequest) Target() (review.Target, error) {
+	var targets []review.Target
+
+	if r.SongID != 0 {
+		targets = append(targets, review.SongTarget(r.SongID))
+	}
+	if r.AlbumID != 0 {
+		targets = append(targets, review.AlbumTarget(r.AlbumID))
+	}
+	if r.ArtistID != 0 {
+		targets = append(targets, review.ArtistTarget(r.ArtistID))
+	}
+
+	if len(targets) != 1 || !targets[0].IsValid() {
+		return review.Target{}, review.ErrInvalidTarget
+	}
+
+	return targets[0], nil
+}
+
+// SetTarget заменяет объект рецензии на заданный адресом запроса
+func (r *CreateReviewRequest) SetTarget(t review.Target) {
+	r.SongID, r.AlbumID, r.ArtistID = 0, 0, 0
+
+	switch t.Type {
+	case review.TargetSong:
+		r.SongID = t.ID
+	case review.TargetAlbum:
+		r.AlbumID = t.ID
+	case review.TargetArtist:
+		r.ArtistID = t.ID
+	}
+}
+
 type UpdateReviewRequest struct {
 	Body   *string `json:"body,omitempty"`
 	IsLike *bool   `json:"is_like,omitempty"`
+	Score  *int    `json:"score,omitempty"`
 	SongID int     `json:"song_id"`
 }
+
+func (r *UpdateReviewRequest) Validate() error {
+	// 0 снимает оценку
+	if r.Score != nil && *r.Score != 0 && !review.IsValidScore(*r.Score) {
+		return review.ErrInvalidScore
+	}
+	return nil
+}
+
+type VoteRequest struct {
+	Helpful *bool `json:"helpful"`
+}
+
+func (r *VoteRequest) Validate() error {
+	if r.Helpful == nil {
+		return errors.New("helpful is required")
+	}
+	return nil
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+func (r *ReactionRequest) Validate() error {
+	if !review.IsValidReaction(r.Emoji) {
+		return review.ErrInvalidReaction
+	}
+	return nil
+}
+
+// PublishRequest без publish_at публикует рецензию сразу
+type PublishRequest struct {
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+// ModerateReviewRequest решение модератора: approved или rejected
+type ModerateReviewRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func (r *ModerateReviewRequest) Validate() error {
+	status := moderation.Status(r.Status)
+	if status != moderation.StatusApproved && status != moderation.StatusRejected {
+		return errors.New("status must be approved or rejected")
+	}
+	if status == moderation.StatusRejected && r.Reason == "" {
+		return errors.New("reason is required")
+	}
+
+	return nil
+}
@@ -3,25 +3,51 @@ package dto
 import (
 	"time"
 
+	"github.com/maYkiss56/tunes/internal/domain/mention"
+	mentionDTO "github.com/maYkiss56/tunes/internal/domain/mention/dto"
 	"github.com/maYkiss56/tunes/internal/domain/review"
 	"github.com/maYkiss56/tunes/internal/domain/song"
 	songDTO "github.com/maYkiss56/tunes/internal/domain/song/dto"
 	"github.com/maYkiss56/tunes/internal/domain/users"
 	userDTO "github.com/maYkiss56/tunes/internal/domain/users/dto"
+	"github.com/maYkiss56/tunes/internal/markdown"
 )
 
+// TargetResponse краткое описание объекта рецензии
+type TargetResponse struct {
+	Type     review.TargetType `json:"type"`
+	ID       int               `json:"id"`
+	Title    string            `json:"title"`
+	ImageURL string            `json:"image_url,omitempty"`
+}
+
 type Response struct {
-	ID        int              `json:"id"`
-	User      userDTO.Response `json:"user"`
-	Song      songDTO.Response `json:"song"`
-	Body      string           `json:"body"`
-	IsLike    bool             `json:"is_like"`
-	IsValid   bool             `json:"is_valid"`
-	UpdatedAt time.Time        `json:"updated_at"`
+	ID     int              `json:"id"`
+	User   userDTO.Response `json:"user"`
+	Target TargetResponse   `json:"target"`
+	// Song заполняется только у рецензий на песни
+	Song           *songDTO.Response     `json:"song,omitempty"`
+	Body           string                `json:"body"`
+	BodyHTML       string                `json:"body_html"`
+	Mentions       []mentionDTO.Response `json:"mentions"`
+	IsLike         bool                  `json:"is_like"`
+	IsValid        bool                  `json:"is_valid"`
+	Score          *int                  `json:"score,omitempty"`
+	Status         review.Status         `json:"status"`
+	PublishAt      *time.Time            `json:"publish_at,omitempty"`
+	PublishedAt    *time.Time            `json:"published_at,omitempty"`
+	HelpfulCount   int                   `json:"helpful_count"`
+	UnhelpfulCount int                   `json:"unhelpful_count"`
+	Reactions      map[string]int        `json:"reactions"`
+	CommentCount   int                   `json:"comment_count"`
+	IsEdited       bool                  `json:"is_edited"`
+	EditedAt       *time.Time            `json:"edited_at,omitempty"`
+	UpdatedAt      time.Time             `json:"updated_at"`
 }
 
-func ToResponse(r review.Review, u users.User, s song.Song) Response {
-	return Response{
+// ToResponse принимает s == nil для рецензий на альбомы и исполнителей
+func ToResponse(r review.Review, u users.User, t TargetResponse, s *song.Song) Response {
+	res := Response{
 		ID: r.ID,
 		User: userDTO.Response{
 			ID:        u.ID,
@@ -29,16 +55,87 @@ func ToResponse(r review.Review, u users.User, s song.Song) Response {
 			Username:  u.Username,
 			AvatarURL: u.AvatarURL,
 		},
-		Song: songDTO.Response{
+		Target:         t,
+		Body:           r.Body,
+		BodyHTML:       markdown.RenderLinks(r.Body, mention.Links(r.Mentions)),
+		Mentions:       mentionDTO.ToResponses(r.Mentions),
+		IsLike:         r.IsLike,
+		IsValid:        r.IsValid,
+		Score:          r.Score,
+		Status:         r.Status,
+		PublishAt:      r.PublishAt,
+		PublishedAt:    r.PublishedAt,
+		HelpfulCount:   r.HelpfulCount,
+		UnhelpfulCount: r.UnhelpfulCount,
+		Reactions:      r.Reactions,
+		CommentCount:   r.CommentCount,
+		IsEdited:       r.EditedAt != nil,
+		EditedAt:       r.EditedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+
+	if s != nil {
+		res.Song = &songDTO.Response{
 			ID:          s.ID,
 			Title:       s.Title,
 			FullTitle:   s.FullTitle,
 			ImageURL:    s.ImageURL,
 			ReleaseDate: &s.ReleaseDate,
-		},
-		Body:      r.Body,
-		IsLike:    r.IsLike,
-		IsValid:   r.IsValid,
-		UpdatedAt: r.UpdatedAt,
+		}
+	}
+
+	return res
+}
+
+type RevisionResponse struct {
+	Revision  int       `json:"revision"`
+	Current   bool      `json:"current"`
+	Body      string    `json:"body"`
+	BodyHTML  string    `json:"body_html"`
+	IsLike    bool      `json:"is_like"`
+	Score     *int      `json:"score,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// автор правки и изменения относительно предыдущей версии, у первой версии отсутствуют
+	EditedBy     *int               `json:"edited_by,omitempty"`
+	BodyDiff     []review.DiffChunk `json:"body_diff,omitempty"`
+	LikeChanged  bool               `json:"like_changed"`
+	ScoreChanged bool               `json:"score_changed"`
+}
+
+type HistoryResponse struct {
+	ReviewID int `json:"review_id"`
+	// Revisions от текущей версии к первой
+	Revisions []RevisionResponse `json:"revisions"`
+}
+
+// ToHistoryResponse принимает версии по возрастанию номера, последняя из них текущая
+func ToHistoryResponse(reviewID int, revisions []review.Revision) HistoryResponse {
+	res := HistoryResponse{
+		ReviewID:  reviewID,
+		Revisions: make([]RevisionResponse, len(revisions)),
 	}
+
+	for i, rev := range revisions {
+		item := RevisionResponse{
+			Revision:  rev.Revision,
+			Current:   i == len(revisions)-1,
+			Body:      rev.Body,
+			BodyHTML:  markdown.Render(rev.Body),
+			IsLike:    rev.IsLike,
+			Score:     rev.Score,
+			CreatedAt: rev.CreatedAt,
+		}
+
+		if i > 0 {
+			prev := revisions[i-1]
+			item.EditedBy = prev.EditedBy
+			item.BodyDiff = review.Diff(prev.Body, rev.Body)
+			item.LikeChanged = prev.IsLike != rev.IsLike
+			item.ScoreChanged = !review.SameScore(prev.Score, rev.Score)
+		}
+
+		res.Revisions[len(revisions)-1-i] = item
+	}
+
+	return res
 }
@@ -1,26 +1,73 @@
 package review
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/mention"
+)
+
+const (
+	MinScore = 1
+	MaxScore = 10
+)
+
+var ErrInvalidScore = errors.New("score must be between 1 and 10")
+
+// ExistsError возвращается при второй рецензии пользователя на тот же объект
+type ExistsError struct {
+	ID int
+}
+
+func (e *ExistsError) Error() string {
+	return fmt.Sprintf("review already exists: %d", e.ID)
+}
 
 type Review struct {
-	ID        int
-	UserID    int
-	SongID    int
-	Body      string
-	IsLike    bool
-	IsValid   bool
+	ID             int
+	UserID         int
+	Target         Target
+	Body           string
+	IsLike         bool
+	IsValid        bool
+	Score          *int
+	Status         Status
+	PublishAt      *time.Time
+	PublishedAt    *time.Time
+	HelpfulCount   int
+	UnhelpfulCount int
+	// CommentCount, Reactions и Mentions заполняются при чтении
+	CommentCount int
+	Reactions    map[string]int
+	Mentions     []mention.Mention
+	// EditedAt время последней правки содержимого, nil если рецензию не меняли
+	EditedAt  *time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
-func NewReview(userID int, songID int, body string, isLike bool, isValid bool) (*Review, error) {
+func NewReview(userID int, target Target, body string, isLike bool, isValid bool, score *int) (*Review, error) {
+	if !target.IsValid() {
+		return nil, ErrInvalidTarget
+	}
+
+	now := time.Now()
+
 	return &Review{
-		UserID:    userID,
-		SongID:    songID,
-		Body:      body,
-		IsLike:    isLike,
-		IsValid:   true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		UserID:      userID,
+		Target:      target,
+		Body:        body,
+		IsLike:      isLike,
+		IsValid:     true,
+		Score:       score,
+		Status:      StatusPublished,
+		PublishedAt: &now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}, nil
 }
+
+func IsValidScore(score int) bool {
+	return score >= MinScore && score <= MaxScore
+}
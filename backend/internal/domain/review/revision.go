@@ -0,0 +1,55 @@
+package review
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrRevisionNotFound = errors.New("review revision not found")
+
+// Revision прежняя версия рецензии. Номера идут с 1, текущая версия
+// рецензии имеет номер на единицу больше последней сохранённой.
+type Revision struct {
+	ReviewID int
+	Revision int
+	Body     string
+	IsLike   bool
+	Score    *int
+	// EditedBy пользователь, заменивший версию следующей
+	EditedBy *int
+	// CreatedAt момент появления версии, ReplacedAt момент её замены
+	CreatedAt  time.Time
+	ReplacedAt *time.Time
+}
+
+// SameContent сообщает, что у рецензий совпадает видимое читателям содержимое
+func (r *Review) SameContent(other *Review) bool {
+	return r.Body == other.Body &&
+		r.IsLike == other.IsLike &&
+		SameScore(r.Score, other.Score)
+}
+
+// CurrentRevision представляет текущее состояние рецензии как последнюю версию
+func (r *Review) CurrentRevision(number int) Revision {
+	createdAt := r.CreatedAt
+	if r.EditedAt != nil {
+		createdAt = *r.EditedAt
+	}
+
+	return Revision{
+		ReviewID:  r.ID,
+		Revision:  number,
+		Body:      r.Body,
+		IsLike:    r.IsLike,
+		Score:     r.Score,
+		CreatedAt: createdAt,
+	}
+}
+
+// SameScore сравнивает оценки, nil означает отсутствие оценки
+func SameScore(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,48 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TargetType вид объекта, к которому относится рецензия
+type TargetType string
+
+const (
+	TargetSong   TargetType = "song"
+	TargetAlbum  TargetType = "album"
+	TargetArtist TargetType = "artist"
+)
+
+var ErrInvalidTarget = errors.New("review must reference exactly one of song_id, album_id or artist_id")
+
+// Target объект рецензии
+type Target struct {
+	Type TargetType
+	ID   int
+}
+
+func SongTarget(id int) Target {
+	return Target{Type: TargetSong, ID: id}
+}
+
+func AlbumTarget(id int) Target {
+	return Target{Type: TargetAlbum, ID: id}
+}
+
+func ArtistTarget(id int) Target {
+	return Target{Type: TargetArtist, ID: id}
+}
+
+func (t Target) IsValid() bool {
+	switch t.Type {
+	case TargetSong, TargetAlbum, TargetArtist:
+		return t.ID > 0
+	default:
+		return false
+	}
+}
+
+func (t Target) String() string {
+	return fmt.Sprintf("%s %d", t.Type, t.ID)
+}
@@ -0,0 +1,51 @@
+package review
+
+import (
+	"errors"
+	"slices"
+)
+
+const (
+	// SortRecent новые рецензии первыми
+	SortRecent = "recent"
+	// SortHelpful по нижней границе Уилсона для доли голосов «полезно»
+	SortHelpful = "helpful"
+)
+
+// Reactions допустимые реакции на рецензию
+var Reactions = []string{"👍", "👎", "❤️", "🔥", "😂", "😮", "😢", "🎵"}
+
+var (
+	ErrNotFound        = errors.New("review not found")
+	ErrOwnReview       = errors.New("cannot vote on own review")
+	ErrInvalidReaction = errors.New("unsupported reaction")
+	ErrInvalidSort     = errors.New("sort must be recent or helpful")
+)
+
+func IsValidReaction(emoji string) bool {
+	return slices.Contains(Reactions, emoji)
+}
+
+func IsValidSort(sort string) bool {
+	return sort == "" || sort == SortRecent || sort == SortHelpful
+}
+
+// VoteDelta считает изменение счётчиков при смене голоса before на after,
+// nil означает отсутствие голоса
+func VoteDelta(before, after *bool) (helpful, unhelpful int) {
+	if before != nil {
+		if *before {
+			helpful--
+		} else {
+			unhelpful--
+		}
+	}
+	if after != nil {
+		if *after {
+			helpful++
+		} else {
+			unhelpful++
+		}
+	}
+	return helpful, unhelpful
+}
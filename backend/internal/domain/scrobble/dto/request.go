@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/maYkiss56/tunes/internal/domain/scrobble"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+
+	defaultTopLimit = 10
+	maxTopLimit     = 50
+)
+
+type ListRequest struct {
+	Limit  int
+	Offset int
+}
+
+func (r *ListRequest) Validate() error {
+	if r.Limit <= 0 {
+		r.Limit = defaultLimit
+	}
+	if r.Limit > maxLimit {
+		r.Limit = maxLimit
+	}
+	if r.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+
+	return nil
+}
+
+// StatsRequest статистика за период week, month, year или all (по умолчанию month)
+type StatsRequest struct {
+	Period scrobble.Period
+	Limit  int
+}
+
+func (r *StatsRequest) Validate() error {
+	if r.Period == "" {
+		r.Period = scrobble.PeriodMonth
+	}
+	if !r.Period.IsValid() {
+		return errors.New("period must be week, month, year or all")
+	}
+
+	if r.Limit <= 0 {
+		r.Limit = defaultTopLimit
+	}
+	if r.Limit > maxTopLimit {
+		r.Limit = maxTopLimit
+	}
+
+	return nil
+}
+
+// AuthorizeRequest подтверждает токен настольного скробблера
+type AuthorizeRequest struct {
+	Token string `json:"token"`
+}
+
+func (r *AuthorizeRequest) Validate() error {
+	r.Token = strings.TrimSpace(r.Token)
+	if r.Token == "" {
+		return errors.New("token is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/scrobble"
+)
+
+// Response прослушивание; song_id и artist_id есть, если строки клиента
+// сопоставлены с каталогом
+type Response struct {
+	ID       int64     `json:"id"`
+	Track    string    `json:"track"`
+	Artist   string    `json:"artist"`
+	Album    string    `json:"album,omitempty"`
+	SongID   *int      `json:"song_id,omitempty"`
+	ArtistID *int      `json:"artist_id,omitempty"`
+	Duration *int      `json:"duration,omitempty"`
+	PlayedAt time.Time `json:"played_at"`
+}
+
+func ToResponse(s scrobble.Scrobble) Response {
+	return Response{
+		ID:       s.ID,
+		Track:    s.Track.Track,
+		Artist:   s.Track.Artist,
+		Album:    s.Track.Album,
+		SongID:   s.Track.SongID,
+		ArtistID: s.Track.ArtistID,
+		Duration: s.Track.Duration,
+		PlayedAt: s.Track.PlayedAt,
+	}
+}
+
+func ToResponses(list []scrobble.Scrobble) []Response {
+	res := make([]Response, 0, len(list))
+	for _, s := range list {
+		res = append(res, ToResponse(s))
+	}
+	return res
+}
+
+type NowPlayingResponse struct {
+	Track     string    `json:"track"`
+	Artist    string    `json:"artist"`
+	Album     string    `json:"album,omitempty"`
+	SongID    *int      `json:"song_id,omitempty"`
+	ArtistID  *int      `json:"artist_id,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func ToNowPlayingResponse(n scrobble.NowPlaying) NowPlayingResponse {
+	return NowPlayingResponse{
+		Track:     n.Track.Track,
+		Artist:    n.Track.Artist,
+		Album:     n.Track.Album,
+		SongID:    n.Track.SongID,
+		ArtistID:  n.Track.ArtistID,
+		StartedAt: n.StartedAt,
+		ExpiresAt: n.ExpiresAt,
+	}
+}
+
+type SummaryResponse struct {
+	Period    scrobble.Period `json:"period"`
+	Scrobbles int             `json:"scrobbles"`
+	Artists   int             `json:"artists"`
+	Songs     int             `json:"songs"`
+	First     *time.Time      `json:"first_played_at,omitempty"`
+	Last      *time.Time      `json:"last_played_at,omitempty"`
+}
+
+type ArtistStatResponse struct {
+	ArtistID *int   `json:"artist_id,omitempty"`
+	Name     string `json:"name"`
+	Plays    int    `json:"plays"`
+}
+
+func ToArtistStatResponses(list []scrobble.ArtistStat) []ArtistStatResponse {
+	res := make([]ArtistStatResponse, 0, len(list))
+	for _, a := range list {
+		res = append(res, ArtistStatResponse{ArtistID: a.ArtistID, Name: a.Name, Plays: a.Plays})
+	}
+	return res
+}
+
+type SongStatResponse struct {
+	SongID   *int   `json:"song_id,omitempty"`
+	Title    string `json:"title"`
+	ArtistID *int   `json:"artist_id,omitempty"`
+	Artist   string `json:"artist"`
+	Plays    int    `json:"plays"`
+}
+
+func ToSongStatResponses(list []scrobble.SongStat) []SongStatResponse {
+	res := make([]SongStatResponse, 0, len(list))
+	for _, s := range list {
+		res = append(res, SongStatResponse{
+			SongID:   s.SongID,
+			Title:    s.Title,
+			ArtistID: s.ArtistID,
+			Artist:   s.Artist,
+			Plays:    s.Plays,
+		})
+	}
+	return res
+}
+
+// ScrobblerResponse подключённый скробблер; сам ключ сессии не выводится
+type ScrobblerResponse struct {
+	ID         int        `json:"id"`
+	Client     string     `json:"client"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+func ToScrobblerResponses(list []scrobble.Session) []ScrobblerResponse {
+	res := make([]ScrobblerResponse, 0, len(list))
+	for _, s := range list {
+		res = append(res, ScrobblerResponse{
+			ID:         s.ID,
+			Client:     s.Client,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+		})
+	}
+	return res
+}
@@ -0,0 +1,235 @@
+package scrobble
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
+)
+
+const (
+	// MaxBatch столько прослушиваний принимает один track.scrobble
+	MaxBatch = 50
+	// MaxAge более старые прослушивания игнорируются, как в Last.fm
+	MaxAge = 14 * 24 * time.Hour
+	// MaxAhead допустимое опережение часов клиента
+	MaxAhead = 24 * time.Hour
+	// TokenTTL время на подтверждение токена настольного клиента
+	TokenTTL = time.Hour
+	// nowPlayingTTL столько «сейчас играет» держится, если клиент не прислал длительность
+	nowPlayingTTL = 10 * time.Minute
+	maxNameLength = 255
+)
+
+var (
+	ErrAuthFailed       = errors.New("invalid username or password")
+	ErrInvalidSession   = errors.New("invalid session key")
+	ErrTokenNotApproved = errors.New("token has not been authorized")
+	ErrTokenExpired     = errors.New("token has expired")
+	ErrTokenNotFound    = errors.New("token not found")
+	ErrSessionNotFound  = errors.New("scrobbler not found")
+)
+
+// IgnoreCode причина, по которой прослушивание не сохранено; коды из протокола Last.fm
+type IgnoreCode int
+
+const (
+	IgnoredNone   IgnoreCode = 0
+	IgnoredArtist IgnoreCode = 1
+	IgnoredTrack  IgnoreCode = 2
+	IgnoredTooOld IgnoreCode = 3
+	IgnoredTooNew IgnoreCode = 4
+)
+
+func (c IgnoreCode) Message() string {
+	switch c {
+	case IgnoredArtist:
+		return "Artist was ignored"
+	case IgnoredTrack:
+		return "Track was ignored"
+	case IgnoredTooOld:
+		return "Timestamp was too old"
+	case IgnoredTooNew:
+		return "Timestamp was too new"
+	}
+	return ""
+}
+
+// Track прослушивание в том виде, в каком его прислал клиент
+type Track struct {
+	Artist   string
+	Track    string
+	Album    string
+	MBID     string
+	Duration *int
+	PlayedAt time.Time
+	// заполняются при сопоставлении с каталогом
+	SongID   *int
+	ArtistID *int
+}
+
+// Normalize убирает пробелы по краям строк клиента; неверный MBID отбрасывается
+func (t *Track) Normalize() {
+	t.Artist = strings.TrimSpace(t.Artist)
+	t.Track = strings.TrimSpace(t.Track)
+	t.Album = strings.TrimSpace(t.Album)
+
+	mbid, err := identifier.NormalizeMBID(t.MBID)
+	if err != nil {
+		mbid = ""
+	}
+	t.MBID = mbid
+}
+
+// Check проверяет прослушивание по правилам Last.fm; для «сейчас играет»
+// времени нет, и checkTime передаётся false
+func (t Track) Check(now time.Time, checkTime bool) IgnoreCode {
+	switch {
+	case t.Artist == "", len(t.Artist) > maxNameLength:
+		return IgnoredArtist
+	case t.Track == "", len(t.Track) > maxNameLength, len(t.Album) > maxNameLength:
+		return IgnoredTrack
+	case !checkTime:
+		return IgnoredNone
+	case t.PlayedAt.Before(now.Add(-MaxAge)):
+		return IgnoredTooOld
+	case t.PlayedAt.After(now.Add(MaxAhead)):
+		return IgnoredTooNew
+	}
+	return IgnoredNone
+}
+
+// Result ответ на одно прослушивание из пакета
+type Result struct {
+	Track   Track
+	Ignored IgnoreCode
+}
+
+type Scrobble struct {
+	ID        int64
+	UserID    int
+	Track     Track
+	CreatedAt time.Time
+}
+
+type NowPlaying struct {
+	UserID    int
+	Track     Track
+	StartedAt time.Time
+	ExpiresAt time.Time
+}
+
+func NewNowPlaying(userID int, t Track, now time.Time) *NowPlaying {
+	ttl := nowPlayingTTL
+	if t.Duration != nil && *t.Duration > 0 {
+		ttl = time.Duration(*t.Duration) * time.Second
+	}
+
+	return &NowPlaying{
+		UserID:    userID,
+		Track:     t,
+		StartedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// Session подключённый скробблер пользователя
+type Session struct {
+	ID         int
+	Key        string
+	UserID     int
+	Username   string
+	Client     string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// Token токен входа настольного клиента; UserID заполняется после подтверждения на сайте
+type Token struct {
+	Token     string
+	Client    string
+	UserID    *int
+	Username  string
+	CreatedAt time.Time
+}
+
+func (t Token) Expired(now time.Time) bool {
+	return now.After(t.CreatedAt.Add(TokenTTL))
+}
+
+// Sign считает api_sig запроса: параметры по алфавиту, имя и значение
+// подряд, в конце секрет, всё в md5. format и callback не подписываются.
+func Sign(params map[string]string, secret string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name == "format" || name == "callback" || name == "api_sig" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteString(params[name])
+	}
+	b.WriteString(secret)
+
+	sum := md5.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// Period промежуток, за который считается статистика
+type Period string
+
+const (
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	PeriodYear  Period = "year"
+	PeriodAll   Period = "all"
+)
+
+func (p Period) IsValid() bool {
+	return p == PeriodWeek || p == PeriodMonth || p == PeriodYear || p == PeriodAll
+}
+
+// Since начало промежутка; для PeriodAll нулевое время
+func (p Period) Since(now time.Time) time.Time {
+	switch p {
+	case PeriodWeek:
+		return now.AddDate(0, 0, -7)
+	case PeriodMonth:
+		return now.AddDate(0, -1, 0)
+	case PeriodYear:
+		return now.AddDate(-1, 0, 0)
+	}
+	return time.Time{}
+}
+
+type Summary struct {
+	Scrobbles int
+	Artists   int
+	Songs     int
+	First     *time.Time
+	Last      *time.Time
+}
+
+// ArtistStat исполнитель в топе; ArtistID пуст, если имя не сопоставлено с каталогом
+type ArtistStat struct {
+	ArtistID *int
+	Name     string
+	Plays    int
+}
+
+type SongStat struct {
+	SongID   *int
+	Title    string
+	ArtistID *int
+	Artist   string
+	Plays    int
+}
@@ -0,0 +1,125 @@
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxLength ограничивает длину основы слага без числового суффикса
+const MaxLength = 150
+
+// MovedError возвращается, когда слаг найден только в истории,
+// Slug — текущий слаг сущности
+type MovedError struct {
+	Slug string
+}
+
+func (e *MovedError) Error() string {
+	return "slug moved to " + e.Slug
+}
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ì': "i", 'í': "i",
+	'î': "i", 'ï': "i", 'ñ': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o",
+	'ö': "o", 'ø': "o", 'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y",
+	'ÿ': "y", 'ß': "ss", 'œ': "oe",
+}
+
+// Make строит основу слага: транслитерация кириллицы, нижний регистр,
+// латиница и цифры через дефис. Чисто числовой слаг неотличим от id,
+// поэтому к нему и к пустому результату добавляется fallback.
+func Make(s, fallback string) string {
+	var b strings.Builder
+	dash := false
+
+	write := func(part string) {
+		if part == "" {
+			return
+		}
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(part)
+		dash = false
+	}
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(r))
+		case translit[r] != "":
+			write(translit[r])
+		case r == '&':
+			dash = true
+			write("and")
+			dash = true
+		case r == '\'' || r == '’' || r == 'ъ' || r == 'ь':
+			// апостроф и твёрдый/мягкий знак не разрывают слово
+		default:
+			dash = true
+		}
+		if b.Len() >= MaxLength {
+			break
+		}
+	}
+
+	res := strings.Trim(truncate(b.String()), "-")
+	if res == "" {
+		return fallback
+	}
+	if _, err := strconv.Atoi(res); err == nil {
+		return fallback + "-" + res
+	}
+
+	return res
+}
+
+func truncate(s string) string {
+	if len(s) <= MaxLength {
+		return s
+	}
+	s = s[:MaxLength]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// WithSuffix возвращает n-й вариант слага для разрешения коллизий
+func WithSuffix(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+	return base + "-" + strconv.Itoa(n)
+}
+
+// HasBase сообщает, что slug построен из base, возможно с числовым суффиксом
+func HasBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	rest, ok := strings.CutPrefix(slug, base+"-")
+	if !ok || rest == "" {
+		return false
+	}
+	_, err := strconv.Atoi(rest)
+	return err == nil
+}
+
+// ParseID отличает числовой идентификатор от слага в параметре маршрута
+func ParseID(ref string) (int, bool) {
+	id, err := strconv.Atoi(ref)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,157 @@
+package snapshot
+
+import (
+	"errors"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/review"
+)
+
+// FormatVersion увеличивается при любом несовместимом изменении формата.
+// Версия 3 добавляет тексты песен, версия 2 выгружает рецензии на альбомы
+// и исполнителей, версия 1 — только на песни.
+const FormatVersion = 3
+
+// MinFormatVersion самая старая версия, которую ещё можно восстановить
+const MinFormatVersion = 1
+
+const (
+	ManifestFile = "manifest.json"
+	GenresFile   = "genres.jsonl"
+	ArtistsFile  = "artists.jsonl"
+	AlbumsFile   = "albums.jsonl"
+	SongsFile    = "songs.jsonl"
+	LyricsFile   = "lyrics.jsonl"
+	ReviewsFile  = "reviews.jsonl"
+	ImagesDir    = "images/"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported snapshot format version")
+	ErrDatabaseNotEmpty   = errors.New("target database is not empty")
+	ErrManifestMissing    = errors.New("snapshot manifest is missing")
+)
+
+type FileInfo struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type Manifest struct {
+	Version        int        `json:"version"`
+	CreatedAt      time.Time  `json:"created_at"`
+	IncludeReviews bool       `json:"include_reviews"`
+	Files          []FileInfo `json:"files"`
+	Images         int        `json:"images"`
+}
+
+type Genre struct {
+	ID        int        `json:"id"`
+	Title     string     `json:"title"`
+	ImageURL  string     `json:"image_url"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type Artist struct {
+	ID        int        `json:"id"`
+	Nickname  string     `json:"nickname"`
+	BIO       string     `json:"bio"`
+	Country   string     `json:"country"`
+	MBID      string     `json:"mbid,omitempty"`
+	Slug      string     `json:"slug,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type Album struct {
+	ID        int        `json:"id"`
+	Title     string     `json:"title"`
+	ImageURL  string     `json:"image_url"`
+	ArtistID  int        `json:"artist_id"`
+	UPC       string     `json:"upc,omitempty"`
+	MBID      string     `json:"mbid,omitempty"`
+	Slug      string     `json:"slug,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type Song struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	FullTitle   string     `json:"full_title"`
+	ImageURL    string     `json:"image_url"`
+	ReleaseDate time.Time  `json:"release_date"`
+	GenreID     int        `json:"genre_id"`
+	ArtistID    int        `json:"artist_id"`
+	AlbumID     int        `json:"album_id"`
+	ISRC        string     `json:"isrc,omitempty"`
+	MBID        string     `json:"mbid,omitempty"`
+	Slug        string     `json:"slug,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Lyrics текст песни; в снапшотах до версии 3 текстов нет
+type Lyrics struct {
+	SongID    int       `json:"song_id"`
+	Plain     string    `json:"plain"`
+	LRC       string    `json:"lrc,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Review ссылается на пользователя по email, так как пользователи
+// не входят в снапшот и при восстановлении ищутся в целевой базе
+type Review struct {
+	ID         int               `json:"id"`
+	UserEmail  string            `json:"user_email"`
+	TargetType review.TargetType `json:"target_type"`
+	TargetID   int               `json:"target_id"`
+	// SongID есть только в снапшотах версии 1
+	SongID    int       `json:"song_id,omitempty"`
+	Body      string    `json:"body"`
+	IsLike    bool      `json:"is_like"`
+	IsValid   bool      `json:"is_valid"`
+	Score     *int      `json:"score,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Target объект рецензии; рецензии из снапшотов версии 1 относятся к песням
+func (r Review) Target() review.Target {
+	if r.TargetType == "" {
+		return review.Target{Type: review.TargetSong, ID: r.SongID}
+	}
+	return review.Target{Type: r.TargetType, ID: r.TargetID}
+}
+
+// Data содержит все записи снапшота в порядке зависимостей.
+// Удалённые в корзину строки тоже выгружаются: на них могут ссылаться живые записи.
+type Data struct {
+	Manifest Manifest
+	Genres   []Genre
+	Artists  []Artist
+	Albums   []Album
+	Songs    []Song
+	Lyrics   []Lyrics
+	Reviews  []Review
+}
+
+// RestoreResult описывает результат восстановления и соответствие
+// старых идентификаторов новым
+type RestoreResult struct {
+	Genres         map[int]int
+	Artists        map[int]int
+	Albums         map[int]int
+	Songs          map[int]int
+	Lyrics         int
+	Reviews        int
+	SkippedReviews int
+}
+
+func NewRestoreResult() *RestoreResult {
+	return &RestoreResult{
+		Genres:  make(map[int]int),
+		Artists: make(map[int]int),
+		Albums:  make(map[int]int),
+		Songs:   make(map[int]int),
+	}
+}
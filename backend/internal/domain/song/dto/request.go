@@ -2,12 +2,19 @@ package dto
 
 import (
 	"errors"
+	"strings"
 	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
 )
 
 const (
 	maxTitleLength     = 50
 	maxFullTitleLength = 150
+
+	maxSearchQueryLength = 200
+	defaultSearchLimit   = 20
+	maxSearchLimit       = 100
 )
 
 type CreateSongRequest struct {
@@ -18,6 +25,8 @@ type CreateSongRequest struct {
 	GenreID     int       `json:"genre_id"`
 	ArtistID    int       `json:"artist_id"`
 	AlbumID     int       `json:"album_id,omitempty"`
+	ISRC        string    `json:"isrc,omitempty"`
+	MBID        string    `json:"mbid,omitempty"`
 }
 
 func (r *CreateSongRequest) Validate() error {
@@ -43,6 +52,21 @@ func (r *CreateSongRequest) Validate() error {
 		return errors.New("release_date is required")
 	}
 
+	if r.ISRC != "" {
+		isrc, err := identifier.NormalizeISRC(r.ISRC)
+		if err != nil {
+			return err
+		}
+		r.ISRC = isrc
+	}
+	if r.MBID != "" {
+		mbid, err := identifier.NormalizeMBID(r.MBID)
+		if err != nil {
+			return err
+		}
+		r.MBID = mbid
+	}
+
 	return nil
 }
 
@@ -54,6 +78,8 @@ type UpdateSongRequest struct {
 	GenreID     *int       `json:"genre_id,omitempty"`
 	ArtistID    *int       `json:"artist_id,omitempty"`
 	AlbumID     *int       `json:"album_id,omitempty"`
+	ISRC        *string    `json:"isrc,omitempty"`
+	MBID        *string    `json:"mbid,omitempty"`
 }
 
 func (r *UpdateSongRequest) Validate() error {
@@ -65,5 +91,44 @@ func (r *UpdateSongRequest) Validate() error {
 		return errors.New("full title is too long")
 	}
 
+	// пустая строка сбрасывает идентификатор
+	if r.ISRC != nil && *r.ISRC != "" {
+		isrc, err := identifier.NormalizeISRC(*r.ISRC)
+		if err != nil {
+			return err
+		}
+		r.ISRC = &isrc
+	}
+	if r.MBID != nil && *r.MBID != "" {
+		mbid, err := identifier.NormalizeMBID(*r.MBID)
+		if err != nil {
+			return err
+		}
+		r.MBID = &mbid
+	}
+
+	return nil
+}
+
+type SearchRequest struct {
+	Query string
+	Limit int
+}
+
+func (r *SearchRequest) Validate() error {
+	r.Query = strings.TrimSpace(r.Query)
+	if len([]rune(r.Query)) < 2 {
+		return errors.New("search query must be at least 2 characters")
+	}
+	if len(r.Query) > maxSearchQueryLength {
+		return errors.New("search query is too long")
+	}
+	if r.Limit <= 0 {
+		r.Limit = defaultSearchLimit
+	}
+	if r.Limit > maxSearchLimit {
+		r.Limit = maxSearchLimit
+	}
+
 	return nil
 }
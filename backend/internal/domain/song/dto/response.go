@@ -9,33 +9,51 @@ import (
 	artistDTO "github.com/maYkiss56/tunes/internal/domain/artist/dto"
 	"github.com/maYkiss56/tunes/internal/domain/genre"
 	genreDTO "github.com/maYkiss56/tunes/internal/domain/genre/dto"
+	libraryDTO "github.com/maYkiss56/tunes/internal/domain/library/dto"
 	"github.com/maYkiss56/tunes/internal/domain/song"
+	"github.com/maYkiss56/tunes/internal/markdown"
 )
 
 type Response struct {
-	ID           int                `json:"id"`
-	Title        string             `json:"title"`
-	FullTitle    string             `json:"full_title,omitempty"`
-	ImageURL     string             `json:"image_url,omitempty"`
-	ReleaseDate  *time.Time         `json:"release_date,omitempty"`
-	LikeCount    int                `json:"like_count"`
-	DislikeCount int                `json:"dislike_count"`
-	Rating       int                `json:"rating"`
-	Genre        genreDTO.Response  `json:"genre"`
-	Artist       artistDTO.Response `json:"artist"`
-	Album        albumDTO.Response  `json:"album"`
+	ID             int                `json:"id"`
+	Title          string             `json:"title"`
+	FullTitle      string             `json:"full_title,omitempty"`
+	ImageURL       string             `json:"image_url,omitempty"`
+	ReleaseDate    *time.Time         `json:"release_date,omitempty"`
+	LikeCount      int                `json:"like_count"`
+	DislikeCount   int                `json:"dislike_count"`
+	Rating         int                `json:"rating"`
+	ScoreAverage   *float64           `json:"score_average"`
+	ScoreCount     int                `json:"score_count"`
+	ScoreHistogram map[int]int        `json:"score_histogram,omitempty"`
+	ISRC           string             `json:"isrc,omitempty"`
+	MBID           string             `json:"mbid,omitempty"`
+	Slug           string             `json:"slug,omitempty"`
+	Genre          genreDTO.Response  `json:"genre"`
+	Artist         artistDTO.Response `json:"artist"`
+	Album          albumDTO.Response  `json:"album"`
+	// ListCount в скольких публичных списках есть песня; только в карточке песни
+	ListCount *int `json:"list_count,omitempty"`
+	// Library отметки вошедшего пользователя в его библиотеке
+	Library *libraryDTO.StatusResponse `json:"library,omitempty"`
 }
 
 func ToResponse(s song.Song, g genre.Genre, songArtist artist.Artist, a album.Album, albumArtist artist.Artist) Response {
 	return Response{
-		ID:           s.ID,
-		Title:        s.Title,
-		FullTitle:    s.FullTitle,
-		ImageURL:     s.ImageURL,
-		ReleaseDate:  &s.ReleaseDate,
-		LikeCount:    s.LikeCount,
-		DislikeCount: s.DislikeCount,
-		Rating:       s.Rating,
+		ID:             s.ID,
+		Title:          s.Title,
+		FullTitle:      s.FullTitle,
+		ImageURL:       s.ImageURL,
+		ReleaseDate:    &s.ReleaseDate,
+		LikeCount:      s.LikeCount,
+		DislikeCount:   s.DislikeCount,
+		Rating:         s.Rating,
+		ScoreAverage:   s.ScoreAverage,
+		ScoreCount:     s.ScoreCount,
+		ScoreHistogram: histogram(s.ScoreHistogram),
+		ISRC:           s.ISRC,
+		MBID:           s.MBID,
+		Slug:           s.Slug,
 		Genre: genreDTO.Response{
 			ID:       g.ID,
 			Title:    g.Title,
@@ -45,18 +63,42 @@ func ToResponse(s song.Song, g genre.Genre, songArtist artist.Artist, a album.Al
 			ID:       songArtist.ID,
 			Nickname: songArtist.Nickname,
 			BIO:      songArtist.BIO,
+			BIOHTML:  markdown.Render(songArtist.BIO),
 			Country:  songArtist.Country,
+			Slug:     songArtist.Slug,
 		},
 		Album: albumDTO.Response{
 			ID:       a.ID,
 			Title:    a.Title,
 			ImageURL: a.ImageURL,
+			Slug:     a.Slug,
 			Artist: artistDTO.Response{
 				ID:       albumArtist.ID,
 				Nickname: albumArtist.Nickname,
 				BIO:      albumArtist.BIO,
+				BIOHTML:  markdown.Render(albumArtist.BIO),
 				Country:  albumArtist.Country,
+				Slug:     albumArtist.Slug,
 			},
 		},
 	}
 }
+
+// SearchResult — найденная песня и фрагмент текста с совпадением
+type SearchResult struct {
+	Song    Response `json:"song"`
+	Snippet string   `json:"snippet,omitempty"`
+}
+
+// histogram превращает массив счётчиков в карту "оценка -> количество"
+func histogram(counts []int) map[int]int {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	res := make(map[int]int, len(counts))
+	for i, n := range counts {
+		res[i+1] = n
+	}
+	return res
+}
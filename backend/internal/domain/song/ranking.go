@@ -0,0 +1,34 @@
+package song
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Rank стратегия упорядочивания песен по голосам
+type Rank string
+
+const (
+	// RankNet разность лайков и дизлайков, прежнее поведение
+	RankNet Rank = "net"
+	// RankWilson нижняя граница доверительного интервала Уилсона для доли лайков
+	RankWilson Rank = "wilson"
+	// RankBayesian доля лайков, сглаженная к среднему по каталогу
+	RankBayesian Rank = "bayesian"
+	// RankHot баланс голосов с поправкой на свежесть песни
+	RankHot Rank = "hot"
+)
+
+var ErrUnknownRank = errors.New("unknown rank")
+
+// ParseRank разбирает параметр rank, пустое значение означает RankNet
+func ParseRank(s string) (Rank, error) {
+	switch r := Rank(s); r {
+	case "":
+		return RankNet, nil
+	case RankNet, RankWilson, RankBayesian, RankHot:
+		return r, nil
+	default:
+		return "", fmt.Errorf("%w %q: expected net, wilson, bayesian or hot", ErrUnknownRank, s)
+	}
+}
@@ -1,9 +1,12 @@
 package song
 
 import (
+	"errors"
 	"time"
 )
 
+var ErrNotFound = errors.New("song not found")
+
 type Song struct {
 	ID           int
 	Title        string
@@ -13,11 +16,18 @@ type Song struct {
 	LikeCount    int
 	DislikeCount int
 	Rating       int
-	GenreID      int
-	ArtistID     int
-	AlbumID      int
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	// ScoreAverage == nil, пока у песни нет ни одной оценки
+	ScoreAverage   *float64
+	ScoreCount     int
+	ScoreHistogram []int
+	GenreID        int
+	ArtistID       int
+	AlbumID        int
+	ISRC           string
+	MBID           string
+	Slug           string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 func NewSong(
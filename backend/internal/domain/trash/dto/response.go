@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/trash"
+)
+
+type Response struct {
+	Entity    string    `json:"entity"`
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+func ToResponse(i trash.Item, retention time.Duration) Response {
+	return Response{
+		Entity:    i.Entity,
+		ID:        i.ID,
+		Title:     i.Title,
+		DeletedAt: i.DeletedAt,
+		PurgeAt:   i.DeletedAt.Add(retention),
+	}
+}
@@ -0,0 +1,39 @@
+package trash
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	EntitySong   = "song"
+	EntityAlbum  = "album"
+	EntityArtist = "artist"
+	EntityGenre  = "genre"
+)
+
+var ErrNotInTrash = errors.New("entity not found in trash")
+
+func IsValidEntity(entity string) bool {
+	switch entity {
+	case EntitySong, EntityAlbum, EntityArtist, EntityGenre:
+		return true
+	}
+	return false
+}
+
+type Item struct {
+	Entity    string
+	ID        int
+	Title     string
+	DeletedAt time.Time
+}
+
+// PurgeResult содержит количество окончательно удалённых строк по таблицам
+type PurgeResult struct {
+	Reviews int64
+	Songs   int64
+	Albums  int64
+	Artists int64
+	Genres  int64
+}
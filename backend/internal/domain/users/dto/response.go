@@ -22,11 +22,27 @@ func ToResponse(u users.User) Response {
 	}
 }
 
+// ProfileResponse профиль вошедшего пользователя со счётчиками подписок
+type ProfileResponse struct {
+	Response
+	FollowerCount  int `json:"follower_count"`
+	FollowingCount int `json:"following_count"`
+}
+
+func ToProfileResponse(u users.User) ProfileResponse {
+	return ProfileResponse{
+		Response:       ToResponse(u),
+		FollowerCount:  u.FollowerCount,
+		FollowingCount: u.FollowingCount,
+	}
+}
+
 type TopResponse struct {
-	ID          int    `json:"id"`
-	Username    string `json:"username"`
-	AvatarURL   string `json:"avatar_url,omitempty"`
-	ReviewCount int    `json:"review_count"`
+	ID           int    `json:"id"`
+	Username     string `json:"username"`
+	AvatarURL    string `json:"avatar_url,omitempty"`
+	ReviewCount  int    `json:"review_count"`
+	HelpfulCount int    `json:"helpful_count"`
 }
 
 func ToTopResponse(u users.User, reviewCount int) TopResponse {
@@ -0,0 +1,18 @@
+package users
+
+import "errors"
+
+// Критерии рейтинга рецензентов
+const (
+	// TopByReviews число одобренных рецензий
+	TopByReviews = "reviews"
+	// TopByHelpful число полученных голосов «полезно»
+	TopByHelpful = "helpful"
+)
+
+var ErrInvalidTopBy = errors.New("invalid top reviewers criterion: expected reviews or helpful")
+
+// IsValidTopBy пустое значение означает TopByReviews
+func IsValidTopBy(by string) bool {
+	return by == "" || by == TopByReviews || by == TopByHelpful
+}
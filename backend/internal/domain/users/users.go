@@ -4,7 +4,10 @@ import (
 	"time"
 )
 
-const RoleID = 2 // user
+const (
+	RoleID      = 2 // user
+	AdminRoleID = 4 // admin
+)
 
 type User struct {
 	ID           int
@@ -14,9 +17,12 @@ type User struct {
 	AvatarURL    string
 	IsBanned     bool
 	RoleID       int
-	LastLogin    time.Time
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	// FollowerCount и FollowingCount ведутся при подписке и отписке
+	FollowerCount  int
+	FollowingCount int
+	LastLogin      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 func NewUser(email, username, password string) (*User, error) {
@@ -0,0 +1,288 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+)
+
+// renderBlocks разбирает строки на блоки. В плотных пунктах списка (tight)
+// абзацы выводятся без обёртки <p>.
+func renderBlocks(b *output, lines []string, depth int, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case isBlank(line):
+			i++
+
+		case isFence(line):
+			i = renderCode(b, lines, i)
+
+		case headingLevel(line) > 0:
+			level := headingLevel(line)
+			tag := "h" + strconv.Itoa(level)
+			b.WriteString("<" + tag + ">")
+			renderInline(b, headingText(line[level:]), 0)
+			b.WriteString("</" + tag + ">\n")
+			i++
+
+		case isRule(line):
+			b.WriteString("<hr>\n")
+			i++
+
+		case depth < MaxBlockDepth && isSpoiler(line):
+			inner, next := collectPrefixed(lines, i, isSpoiler, ">!")
+			b.WriteString("<details class=\"spoiler\"><summary>Spoiler</summary>\n")
+			renderBlocks(b, inner, depth+1, false)
+			b.WriteString("</details>\n")
+			i = next
+
+		case depth < MaxBlockDepth && isQuote(line):
+			inner, next := collectPrefixed(lines, i, isQuote, ">")
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, inner, depth+1, false)
+			b.WriteString("</blockquote>\n")
+			i = next
+
+		case depth < MaxBlockDepth && listMarker(line) != nil:
+			i = renderList(b, lines, i, depth)
+
+		default:
+			i = renderParagraph(b, lines, i, depth, tight)
+		}
+	}
+}
+
+func renderParagraph(b *output, lines []string, i, depth int, tight bool) int {
+	start := i
+	for i++; i < len(lines) && !startsBlock(lines[i], depth); i++ {
+	}
+
+	if !tight {
+		b.WriteString("<p>")
+	}
+	for j, line := range lines[start:i] {
+		if j > 0 {
+			b.WriteString("<br>\n")
+		}
+		renderInline(b, strings.TrimSpace(line), 0)
+	}
+	if !tight {
+		b.WriteString("</p>")
+	}
+	b.WriteString("\n")
+
+	return i
+}
+
+// renderCode выводит блок кода между ``` как есть; незакрытый блок идёт до конца текста
+func renderCode(b *output, lines []string, i int) int {
+	b.WriteString("<pre><code>")
+	for i++; i < len(lines) && !isFence(lines[i]); i++ {
+		escape(b, lines[i])
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+
+	return i + 1
+}
+
+type marker struct {
+	ordered bool
+	start   int
+	// width длина маркера с пробелом после него
+	width int
+	// delim символ маркера: -, *, + или . и ) для нумерованных
+	delim byte
+}
+
+func listMarker(line string) *marker {
+	if len(line) < 2 {
+		return nil
+	}
+
+	switch line[0] {
+	case '-', '*', '+':
+		if line[1] == ' ' && !isRule(line) {
+			return &marker{width: 2, delim: line[0]}
+		}
+		return nil
+	}
+
+	n := 0
+	for n < len(line) && n < 9 && line[n] >= '0' && line[n] <= '9' {
+		n++
+	}
+	if n == 0 || n+1 >= len(line) || (line[n] != '.' && line[n] != ')') || line[n+1] != ' ' {
+		return nil
+	}
+	start, _ := strconv.Atoi(line[:n])
+
+	return &marker{ordered: true, start: start, width: n + 2, delim: line[n]}
+}
+
+func (m *marker) sameList(o *marker) bool {
+	return o != nil && m.ordered == o.ordered && m.delim == o.delim
+}
+
+// renderList собирает пункты списка: продолжения пункта идут с отступом,
+// пустая строка внутри списка делает его неплотным
+func renderList(b *output, lines []string, i, depth int) int {
+	first := listMarker(lines[i])
+
+	var (
+		items [][]string
+		loose bool
+	)
+
+	for i < len(lines) {
+		line := lines[i]
+
+		if m := listMarker(line); first.sameList(m) {
+			items = append(items, []string{line[m.width:]})
+			i++
+			continue
+		}
+
+		last := len(items) - 1
+
+		if isBlank(line) {
+			// список продолжается, если за пустыми строками идёт пункт или отступ
+			j := i
+			for j < len(lines) && isBlank(lines[j]) {
+				j++
+			}
+			if j == len(lines) || !(first.sameList(listMarker(lines[j])) || isIndented(lines[j])) {
+				break
+			}
+			loose = true
+			for ; i < j; i++ {
+				items[last] = append(items[last], "")
+			}
+			continue
+		}
+
+		if isIndented(line) {
+			items[last] = append(items[last], unindent(line))
+			i++
+			continue
+		}
+
+		if startsBlock(line, depth) {
+			break
+		}
+
+		// ленивое продолжение абзаца пункта
+		items[last] = append(items[last], line)
+		i++
+	}
+
+	tag := "ul"
+	if first.ordered {
+		tag = "ol"
+	}
+
+	b.WriteString("<" + tag)
+	if first.ordered && first.start != 1 {
+		b.WriteString(" start=\"" + strconv.Itoa(first.start) + "\"")
+	}
+	b.WriteString(">\n")
+
+	for _, item := range items {
+		b.WriteString("<li>")
+		renderBlocks(b, item, depth+1, !loose)
+		b.WriteString("</li>\n")
+	}
+
+	b.WriteString("</" + tag + ">\n")
+
+	return i
+}
+
+// collectPrefixed собирает подряд идущие строки блока и снимает с них префикс
+func collectPrefixed(lines []string, i int, match func(string) bool, prefix string) ([]string, int) {
+	var inner []string
+
+	for ; i < len(lines) && match(lines[i]); i++ {
+		line := strings.TrimPrefix(strings.TrimLeft(lines[i], " "), prefix)
+		inner = append(inner, strings.TrimPrefix(line, " "))
+	}
+
+	return inner, i
+}
+
+// startsBlock сообщает, что строка прерывает абзац
+func startsBlock(line string, depth int) bool {
+	if isBlank(line) || isFence(line) || headingLevel(line) > 0 || isRule(line) {
+		return true
+	}
+	if depth >= MaxBlockDepth {
+		return false
+	}
+	return isQuote(line) || listMarker(line) != nil
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func isFence(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), "```")
+}
+
+func isQuote(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), ">") && !isSpoiler(line)
+}
+
+func isSpoiler(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), ">!")
+}
+
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+}
+
+func unindent(line string) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	n := 0
+	for n < len(line) && n < 4 && line[n] == ' ' {
+		n++
+	}
+	return line[n:]
+}
+
+// headingLevel возвращает уровень заголовка # .. ######, 0 если строка не заголовок
+func headingLevel(line string) int {
+	n := 0
+	for n < len(line) && n < 7 && line[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || n == len(line) || line[n] != ' ' {
+		return 0
+	}
+	return n
+}
+
+// headingText снимает с заголовка пробелы и необязательные закрывающие #
+func headingText(s string) string {
+	s = strings.TrimSpace(s)
+	if trimmed := strings.TrimRight(s, "#"); trimmed == "" || strings.HasSuffix(trimmed, " ") {
+		s = strings.TrimSpace(trimmed)
+	}
+	return s
+}
+
+// isRule распознаёт горизонтальную черту из трёх и более -, * или _
+func isRule(line string) bool {
+	s := strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	if len(s) < 3 {
+		return false
+	}
+	c := s[0]
+	if c != '-' && c != '*' && c != '_' {
+		return false
+	}
+	return strings.Count(s, string(c)) == len(s)
+}
@@ -0,0 +1,341 @@
+package markdown
+
+import (
+	"net/url"
+	"strings"
+)
+
+// allowedSchemes схемы ссылок, которые выводятся как <a>
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// spans парные разделители строки и теги, в которые они превращаются
+var spans = []struct {
+	delim string
+	open  string
+	close string
+}{
+	{"**", "<strong>", "</strong>"},
+	{"__", "<strong>", "</strong>"},
+	{"~~", "<del>", "</del>"},
+	{"||", `<span class="spoiler">`, "</span>"},
+	{"*", "<em>", "</em>"},
+	{"_", "<em>", "</em>"},
+}
+
+// inline разбирает одну строку. next запоминает найденные позиции
+// разделителей (-1 если правее их нет): разбор идёт слева направо, поэтому
+// строка из одних незакрытых * или [ разбирается за линейное время.
+type inline struct {
+	b      *output
+	depth  int
+	inLink bool
+	next   map[string]int
+}
+
+func renderInline(b *output, s string, depth int) {
+	(&inline{b: b, depth: depth}).render(s)
+}
+
+func (p *inline) render(s string) {
+	if p.depth >= MaxInlineDepth {
+		escape(p.b, s)
+		return
+	}
+
+	for i := 0; i < len(s); {
+		if n := p.token(s, i); n > 0 {
+			i += n
+			continue
+		}
+		escapeByte(p.b, s[i])
+		i++
+	}
+}
+
+// token пытается разобрать конструкцию с позиции i и возвращает её длину, 0 если не вышло
+func (p *inline) token(s string, i int) int {
+	switch s[i] {
+	case '\\':
+		if i+1 < len(s) && isPunct(s[i+1]) {
+			escapeByte(p.b, s[i+1])
+			return 2
+		}
+	case '`':
+		return p.code(s, i)
+	case '[':
+		if !p.inLink {
+			if n := p.reference(s, i); n > 0 {
+				return n
+			}
+			return p.link(s, i)
+		}
+	case '@':
+		// @ внутри слова (адрес почты) упоминанием не считается
+		if !p.inLink && (i == 0 || !isWordByte(s[i-1])) {
+			return p.userMention(s, i)
+		}
+	case '<':
+		if !p.inLink {
+			return p.autolink(s, i)
+		}
+	case '*', '_', '~', '|':
+		return p.span(s, i)
+	}
+	return 0
+}
+
+func (p *inline) code(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	fence := s[i : i+n]
+
+	end := p.find(s, i+n, fence)
+	if end < 0 {
+		escape(p.b, fence)
+		return n
+	}
+
+	p.b.WriteString("<code>")
+	escape(p.b, strings.TrimSpace(s[i+n:end]))
+	p.b.WriteString("</code>")
+
+	return end + n - i
+}
+
+func (p *inline) span(s string, i int) int {
+	for _, sp := range spans {
+		if !strings.HasPrefix(s[i:], sp.delim) {
+			continue
+		}
+
+		// _ внутри слова (snake_case) разметкой не считается
+		if sp.delim[0] == '_' && i > 0 && isWordByte(s[i-1]) {
+			return 0
+		}
+
+		start := i + len(sp.delim)
+		end := p.find(s, start, sp.delim)
+		if end <= start {
+			continue
+		}
+
+		inner := s[start:end]
+		if isSpace(inner[0]) || isSpace(inner[len(inner)-1]) {
+			continue
+		}
+
+		p.b.WriteString(sp.open)
+		p.nested(inner, p.inLink)
+		p.b.WriteString(sp.close)
+
+		return end + len(sp.delim) - i
+	}
+
+	return 0
+}
+
+// link разбирает [текст](адрес); ссылка с недопустимым адресом выводится одним текстом
+func (p *inline) link(s string, i int) int {
+	mid := p.find(s, i+1, "](")
+	if mid < 0 {
+		return 0
+	}
+	end := p.find(s, mid+2, ")")
+	if end < 0 {
+		return 0
+	}
+
+	text, href := s[i+1:mid], strings.TrimSpace(s[mid+2:end])
+
+	if safe, ok := safeURL(href); ok {
+		p.b.WriteString(`<a href="`)
+		escape(p.b, safe)
+		p.b.WriteString(`" rel="nofollow noopener ugc">`)
+		p.nested(text, true)
+		p.b.WriteString("</a>")
+	} else {
+		p.nested(text, true)
+	}
+
+	return end + 1 - i
+}
+
+// autolink разбирает <https://...>
+func (p *inline) autolink(s string, i int) int {
+	// адрес заканчивается на первом пробеле или угловой скобке
+	end := strings.IndexAny(s[i+1:], " \t<>")
+	if end < 0 || s[i+1+end] != '>' {
+		return 0
+	}
+	href := s[i+1 : i+1+end]
+
+	safe, ok := safeURL(href)
+	if !ok || !strings.Contains(href, ":") {
+		return 0
+	}
+
+	p.b.WriteString(`<a href="`)
+	escape(p.b, safe)
+	p.b.WriteString(`" rel="nofollow noopener ugc">`)
+	escape(p.b, href)
+	p.b.WriteString("</a>")
+
+	return end + 2
+}
+
+// reference разбирает [[вид:ключ]]
+func (p *inline) reference(s string, i int) int {
+	if !strings.HasPrefix(s[i:], "[[") {
+		return 0
+	}
+	end := p.find(s, i+2, "]]")
+	if end < 0 {
+		return 0
+	}
+
+	kind, key, ok := strings.Cut(s[i+2:end], ":")
+	if !ok || !isMentionKind(kind) || !isMentionKey(key) {
+		return 0
+	}
+
+	p.mention(strings.ToLower(kind+":"+key), s[i:end+2])
+
+	return end + 2 - i
+}
+
+// userMention разбирает @имя; точка и дефис в конце к имени не относятся
+func (p *inline) userMention(s string, i int) int {
+	j := i + 1
+	for j < len(s) && j-i <= MaxMentionKey && isMentionByte(s[j]) {
+		j++
+	}
+	for j > i+1 && (s[j-1] == '.' || s[j-1] == '-') {
+		j--
+	}
+	if j == i+1 {
+		return 0
+	}
+
+	p.mention("user:"+strings.ToLower(s[i+1:j]), s[i:j])
+
+	return j - i
+}
+
+// mention выводит упоминание ссылкой, если для key она передана, иначе исходным текстом
+func (p *inline) mention(key, raw string) {
+	if p.b.collect && !p.b.seen[key] {
+		if p.b.seen == nil {
+			p.b.seen = make(map[string]bool)
+		}
+		p.b.seen[key] = true
+		p.b.mentions = append(p.b.mentions, key)
+	}
+
+	link, ok := p.b.links[key]
+	if !ok {
+		escape(p.b, raw)
+		return
+	}
+
+	p.b.WriteString(`<a href="`)
+	escape(p.b, link.Href)
+	p.b.WriteString(`" class="mention">`)
+	escape(p.b, link.Text)
+	p.b.WriteString("</a>")
+}
+
+func (p *inline) nested(s string, inLink bool) {
+	(&inline{b: p.b, depth: p.depth + 1, inLink: inLink}).render(s)
+}
+
+// find ищет разделитель не левее from с учётом запомненных позиций
+func (p *inline) find(s string, from int, delim string) int {
+	if pos, ok := p.next[delim]; ok && (pos < 0 || pos >= from) {
+		return pos
+	}
+
+	pos := strings.Index(s[from:], delim)
+	if pos >= 0 {
+		pos += from
+	}
+
+	if p.next == nil {
+		p.next = make(map[string]int)
+	}
+	p.next[delim] = pos
+
+	return pos
+}
+
+// safeURL пропускает абсолютные ссылки с разрешённой схемой и пути от корня сайта
+func safeURL(raw string) (string, bool) {
+	if raw == "" || strings.ContainsAny(raw, " \t\n") {
+		return "", false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+
+	if u.Scheme == "" {
+		// относительные ссылки только от корня, //host означает чужую схему
+		if !strings.HasPrefix(raw, "/") || strings.HasPrefix(raw, "//") {
+			return "", false
+		}
+		return u.String(), true
+	}
+
+	if !allowedSchemes[strings.ToLower(u.Scheme)] {
+		return "", false
+	}
+
+	return u.String(), true
+}
+
+func isMentionKind(s string) bool {
+	if s == "" || len(s) > 16 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isMentionKey(s string) bool {
+	if s == "" || len(s) > MaxMentionKey {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isMentionByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isMentionByte символы имён пользователей и слагов в упоминаниях
+func isMentionByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
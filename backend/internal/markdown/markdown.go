@@ -0,0 +1,123 @@
+// Package markdown переводит пользовательский Markdown рецензий и биографий в HTML.
+//
+// Разметка строится только из фиксированного набора тегов, весь текст
+// экранируется, поэтому сырой HTML из исходника в вывод не попадает,
+// а ссылки допускаются лишь со схемами http, https и mailto.
+// Упоминания @имя и [[вид:ключ]] становятся ссылками, если вызывающий
+// передал для них адрес.
+package markdown
+
+import (
+	"strings"
+)
+
+// Ограничения рендеринга против патологических входных данных
+const (
+	// MaxInputBytes длиннее этого текст выводится без разметки
+	MaxInputBytes = 32 << 10
+	// MaxBlockDepth вложенность цитат, спойлеров и списков
+	MaxBlockDepth = 8
+	// MaxInlineDepth вложенность выделений и ссылок внутри строки
+	MaxInlineDepth = 8
+	// MaxMentionKey длина имени или ключа в упоминании
+	MaxMentionKey = 160
+)
+
+// Link ссылка, в которую выводится найденное упоминание
+type Link struct {
+	Href string
+	Text string
+}
+
+// Links ссылки упоминаний по ключу в нижнем регистре: "user:имя" для @имя
+// и "вид:ключ" для [[вид:ключ]]. Упоминание без ссылки выводится как текст.
+type Links map[string]Link
+
+// output собирает HTML одного рендеринга
+type output struct {
+	strings.Builder
+	links Links
+	// mentions заполняется, только если collect
+	collect  bool
+	mentions []string
+	seen     map[string]bool
+}
+
+// Render возвращает безопасный HTML для текста в Markdown
+func Render(src string) string {
+	return RenderLinks(src, nil)
+}
+
+// RenderLinks как Render, но выводит упоминания из links ссылками
+func RenderLinks(src string, links Links) string {
+	b := output{links: links}
+	render(&b, src)
+	return b.String()
+}
+
+// Mentions возвращает ключи упоминаний из текста без повторов в порядке появления.
+// Упоминания в коде и внутри ссылок не учитываются, как и при рендеринге.
+func Mentions(src string) []string {
+	b := output{collect: true}
+	render(&b, src)
+	return b.mentions
+}
+
+func render(b *output, src string) {
+	if strings.TrimSpace(src) == "" {
+		return
+	}
+
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+
+	b.Grow(len(src) + len(src)/4)
+
+	if len(src) > MaxInputBytes {
+		renderPlain(b, src)
+		return
+	}
+
+	renderBlocks(b, strings.Split(src, "\n"), 0, false)
+}
+
+// renderPlain выводит текст абзацами без разбора разметки
+func renderPlain(b *output, src string) {
+	for _, para := range strings.Split(src, "\n\n") {
+		if strings.TrimSpace(para) == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		for i, line := range strings.Split(strings.Trim(para, "\n"), "\n") {
+			if i > 0 {
+				b.WriteString("<br>\n")
+			}
+			escape(b, line)
+		}
+		b.WriteString("</p>\n")
+	}
+}
+
+// escape пишет текст с экранированием символов, значимых в HTML
+func escape(b *output, s string) {
+	for i := 0; i < len(s); i++ {
+		escapeByte(b, s[i])
+	}
+}
+
+func escapeByte(b *output, c byte) {
+	switch c {
+	case '<':
+		b.WriteString("&lt;")
+	case '>':
+		b.WriteString("&gt;")
+	case '&':
+		b.WriteString("&amp;")
+	case '"':
+		b.WriteString("&#34;")
+	case '\'':
+		b.WriteString("&#39;")
+	default:
+		b.WriteByte(c)
+	}
+}
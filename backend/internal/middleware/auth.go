@@ -4,12 +4,11 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/maYkiss56/tunes/internal/domain/users"
 	"github.com/maYkiss56/tunes/internal/session"
 	"github.com/maYkiss56/tunes/internal/utilites"
 )
 
-const AdminRoleID = 4
-
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		cookie, err := r.Cookie("session_id")
@@ -42,6 +41,27 @@ func AuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// OptionalAuthMiddleware сохраняет в контексте сессию, если она действительна,
+// и пропускает запрос без неё, если нет. Для публичных маршрутов, ответ которых
+// зависит от пользователя.
+func OptionalAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session_id")
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sess, ok := session.GetSession(cookie.Value)
+		if !ok || sess.ExpiresAt.Before(time.Now()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(session.WithSession(r.Context(), sess)))
+	})
+}
+
 func AdminOnlyMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		s := session.FromContext(r.Context())
@@ -50,7 +70,7 @@ func AdminOnlyMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		if s.UserRoleID != AdminRoleID {
+		if s.UserRoleID != users.AdminRoleID {
 			utilites.RenderError(w, r, http.StatusForbidden, "forbidden: insufficient permissions")
 			return
 		}
@@ -12,6 +12,9 @@ import (
 	domain "github.com/maYkiss56/tunes/internal/domain/album"
 	"github.com/maYkiss56/tunes/internal/domain/album/dto"
 	"github.com/maYkiss56/tunes/internal/domain/artist"
+	"github.com/maYkiss56/tunes/internal/domain/rating"
+	"github.com/maYkiss56/tunes/internal/domain/review"
+	"github.com/maYkiss56/tunes/internal/domain/trash"
 	"github.com/maYkiss56/tunes/internal/logger"
 )
 
@@ -27,35 +30,90 @@ func NewAlbumRepository(db *pgxpool.Pool, logger *logger.Logger) *AlbumRepositor
 	}
 }
 
+var albumSelect = `
+	select a.id, a.title,
+	a.image_url, a.artist_id,
+	coalesce(a.upc, ''), coalesce(a.mbid::text, ''), coalesce(a.slug, ''),
+	` + ratingSummaryColumns("a") + `,
+	ar.id, ar.nickname, ar.bio, ar.country, coalesce(ar.slug, '')
+	from album a
+	join artist ar on a.artist_id = ar.id`
+
+func scanAlbum(row pgx.Row) (dto.Response, error) {
+	var (
+		album   domain.Album
+		artist  artist.Artist
+		summary rating.Summary
+	)
+
+	dest := []any{
+		&album.ID,
+		&album.Title,
+		&album.ImageURL,
+		&album.ArtistID,
+		&album.UPC,
+		&album.MBID,
+		&album.Slug,
+	}
+	dest = append(dest, summaryDest(&summary)...)
+	dest = append(dest,
+		&artist.ID,
+		&artist.Nickname,
+		&artist.BIO,
+		&artist.Country,
+		&artist.Slug,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return dto.Response{}, err
+	}
+
+	album.Ratings = &summary
+
+	return dto.ToResponse(album, artist), nil
+}
+
 func (r *AlbumRepository) CreateAlbum(ctx context.Context, album *domain.Album) error {
 	query := `insert into album
-		(title, image_url, artist_id)
-		values ($1, $2, $3) returning id`
+		(title, image_url, artist_id, upc, mbid)
+		values ($1, $2, $3, nullif($4, ''), nullif($5, '')::uuid) returning id`
 
-	err := r.db.QueryRow(
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
 		ctx,
 		query,
 		album.Title,
 		album.ImageURL,
 		album.ArtistID,
+		album.UPC,
+		album.MBID,
 	).Scan(&album.ID)
 	if err != nil {
-		r.logger.Error("failed to create song", "error", err)
+		r.logger.Error("failed to create album", "error", err)
+		return wrapUniqueViolation(err)
+	}
+
+	if album.Slug, err = assignSlug(ctx, tx, slugAlbum, album.ID, album.Title); err != nil {
+		r.logger.Error("failed to assign album slug", "error", err)
 		return err
 	}
 
-	return nil
+	if err = recordAlbumRelease(ctx, tx, album.ArtistID, album.ID); err != nil {
+		r.logger.Error("failed to record album release", "error", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *AlbumRepository) GetAllAlbums(ctx context.Context) ([]dto.Response, error) {
-	query := `
-		select a.id, a.title,
-		a.image_url, a.artist_id,
-		ar.id, ar.nickname, ar.bio, ar.country
-		from album a
-		join artist ar on a.artist_id = ar.id`
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, albumSelect+" where a.deleted_at is null")
 	if err != nil {
 		r.logger.Error("failed to get all albums", "error", err)
 		return nil, err
@@ -65,26 +123,13 @@ func (r *AlbumRepository) GetAllAlbums(ctx context.Context) ([]dto.Response, err
 	albums := make([]dto.Response, 0)
 
 	for rows.Next() {
-		var (
-			album  domain.Album
-			artist artist.Artist
-		)
-		err = rows.Scan(
-			&album.ID,
-			&album.Title,
-			&album.ImageURL,
-			&album.ArtistID,
-			&artist.ID,
-			&artist.Nickname,
-			&artist.BIO,
-			&artist.Country,
-		)
+		album, err := scanAlbum(rows)
 		if err != nil {
 			r.logger.Error("failed to scan rows", "error", err)
 			return nil, err
 		}
 
-		albums = append(albums, dto.ToResponse(album, artist))
+		albums = append(albums, album)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
@@ -94,36 +139,39 @@ func (r *AlbumRepository) GetAllAlbums(ctx context.Context) ([]dto.Response, err
 }
 
 func (r *AlbumRepository) GetAlbumByID(ctx context.Context, id int) (*dto.Response, error) {
-	query := `
-		select a.id, a.title,
-		a.image_url, a.artist_id,
-		ar.id, ar.nickname,
-		ar.bio, ar.country
-		from album a
-		join artist ar on a.artist_id = ar.id
-		where a.id=$1`
+	return r.getAlbum(ctx, "a.id = $1", id)
+}
 
-	var (
-		album  domain.Album
-		artist artist.Artist
-	)
+func (r *AlbumRepository) GetAlbumByUPC(ctx context.Context, upc string) (*dto.Response, error) {
+	return r.getAlbum(ctx, "a.upc = $1", upc)
+}
 
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&album.ID, &album.Title,
-			&album.ImageURL, &album.ArtistID,
-			&artist.ID, &artist.Nickname, &artist.BIO,
-			&artist.Country)
+func (r *AlbumRepository) GetAlbumByMBID(ctx context.Context, mbid string) (*dto.Response, error) {
+	return r.getAlbum(ctx, "a.mbid = $1::uuid", mbid)
+}
+
+func (r *AlbumRepository) GetAlbumBySlug(ctx context.Context, slug string) (*dto.Response, error) {
+	return r.getAlbum(ctx, "a.slug = $1", slug)
+}
+
+// GetAlbumSlugRedirect возвращает текущий слаг по слагу из истории
+func (r *AlbumRepository) GetAlbumSlugRedirect(ctx context.Context, slug string) (string, error) {
+	return findSlugRedirect(ctx, r.db, slugAlbum, slug)
+}
+
+func (r *AlbumRepository) getAlbum(ctx context.Context, where string, arg any) (*dto.Response, error) {
+	query := albumSelect + " where a.deleted_at is null and " + where
+
+	res, err := scanAlbum(conn(ctx, r.db).QueryRow(ctx, query, arg))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.Error("album not found", "id", id)
+			r.logger.Error("album not found", "where", where, "value", arg)
 			return nil, err
 		}
 		r.logger.Error("failed to search album", "error", err)
 		return nil, err
 	}
 
-	res := dto.ToResponse(album, artist)
-
 	return &res, nil
 }
 
@@ -154,6 +202,18 @@ func (r *AlbumRepository) UpdateAlbum(
 		argPos++
 	}
 
+	if update.UPC != nil {
+		fields = append(fields, fmt.Sprintf("upc=nullif($%d, '')", argPos))
+		args = append(args, *update.UPC)
+		argPos++
+	}
+
+	if update.MBID != nil {
+		fields = append(fields, fmt.Sprintf("mbid=nullif($%d, '')::uuid", argPos))
+		args = append(args, *update.MBID)
+		argPos++
+	}
+
 	if len(fields) == 0 {
 		return nil
 	}
@@ -163,13 +223,30 @@ func (r *AlbumRepository) UpdateAlbum(
 
 	query := fmt.Sprintf("update album set %s %s", strings.Join(fields, ", "), whereClause)
 
-	res, err := r.db.Exec(
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(
 		ctx,
 		query,
 		args...,
 	)
 	if err != nil {
 		r.logger.Error("failed to update album", "id", id, "error", err)
+		return wrapUniqueViolation(err)
+	}
+
+	if update.Title != nil && res.RowsAffected() > 0 {
+		if _, err = assignSlug(ctx, tx, slugAlbum, id, *update.Title); err != nil {
+			r.logger.Error("failed to assign album slug", "id", id, "error", err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
 		return err
 	}
 
@@ -182,10 +259,45 @@ func (r *AlbumRepository) UpdateAlbum(
 	return nil
 }
 
+// DeleteAlbum переносит альбом в корзину, окончательно его удаляет TrashRepository.Purge
 func (r *AlbumRepository) DeleteAlbum(ctx context.Context, id int) error {
-	query := `delete from album where id=$1`
+	query := `update album set deleted_at = now() where id=$1 and deleted_at is null`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to delete album", "id", id, "error", err)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// RestoreAlbum возвращает album из корзины
+func (r *AlbumRepository) RestoreAlbum(ctx context.Context, id int) error {
+	query := `update album set deleted_at = null where id=$1 and deleted_at is not null`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to restore album", "id", id, "error", err)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return trash.ErrNotInTrash
+	}
+
+	return nil
+}
 
-	if _, err := r.db.Exec(ctx, query, id); err != nil {
+// ApplyRatingDelta прибавляет к счётчикам альбома вклад изменения рецензии;
+// вызывается в транзакции UnitOfWork вместе с записью рецензии
+func (r *AlbumRepository) ApplyRatingDelta(ctx context.Context, albumID int, d review.RatingDelta) error {
+	if err := applyTargetRatingDelta(ctx, conn(ctx, r.db), "album", albumID, d); err != nil {
+		r.logger.Error("failed to apply rating delta", "album_id", albumID, "error", err)
 		return err
 	}
 
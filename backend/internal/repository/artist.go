@@ -11,6 +11,9 @@ import (
 
 	domain "github.com/maYkiss56/tunes/internal/domain/artist"
 	"github.com/maYkiss56/tunes/internal/domain/artist/dto"
+	"github.com/maYkiss56/tunes/internal/domain/rating"
+	"github.com/maYkiss56/tunes/internal/domain/review"
+	"github.com/maYkiss56/tunes/internal/domain/trash"
 	"github.com/maYkiss56/tunes/internal/logger"
 )
 
@@ -26,30 +29,61 @@ func NewArtistRepository(db *pgxpool.Pool, logger *logger.Logger) *ArtistReposit
 	}
 }
 
+var artistSelect = `select id, nickname, bio, country, coalesce(mbid::text, ''), coalesce(slug, ''), follower_count, ` +
+	mentionsColumn("m.artist_id = artist.id") + `, ` +
+	ratingSummaryColumns("artist") + ` from artist`
+
+func scanArtist(row pgx.Row) (*domain.Artist, error) {
+	var (
+		artist  domain.Artist
+		summary rating.Summary
+	)
+
+	dest := []any{&artist.ID, &artist.Nickname, &artist.BIO, &artist.Country, &artist.MBID, &artist.Slug,
+		&artist.FollowerCount, &artist.Mentions}
+	if err := row.Scan(append(dest, summaryDest(&summary)...)...); err != nil {
+		return nil, err
+	}
+	artist.Ratings = &summary
+
+	return &artist, nil
+}
+
 func (r *ArtistRepository) CreateArtist(ctx context.Context, artist *domain.Artist) error {
-	query := `insert into artist 
-		(nickname, bio, country) 
-		values ($1, $2, $3) returning id`
+	query := `insert into artist
+		(nickname, bio, country, mbid)
+		values ($1, $2, $3, nullif($4, '')::uuid) returning id`
 
-	err := r.db.QueryRow(
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
 		ctx,
 		query,
 		artist.Nickname,
 		artist.BIO,
 		artist.Country,
+		artist.MBID,
 	).Scan(&artist.ID)
 
 	if err != nil {
 		r.logger.Error("failed to create artist", "error", err)
+		return wrapUniqueViolation(err)
+	}
+
+	if artist.Slug, err = assignSlug(ctx, tx, slugArtist, artist.ID, artist.Nickname); err != nil {
+		r.logger.Error("failed to assign artist slug", "error", err)
 		return err
 	}
-	return nil
+
+	return tx.Commit(ctx)
 }
 
 func (r *ArtistRepository) GetAllArtists(ctx context.Context) ([]*domain.Artist, error) {
-	query := `select id, nickname, bio, country from artist`
-
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, artistSelect+" where deleted_at is null")
 	if err != nil {
 		r.logger.Error("failed to get all artist", "error", err)
 		return nil, err
@@ -59,28 +93,13 @@ func (r *ArtistRepository) GetAllArtists(ctx context.Context) ([]*domain.Artist,
 	artists := make([]*domain.Artist, 0)
 
 	for rows.Next() {
-		var (
-			artistID       int
-			artistNickname string
-			artistBIO      string
-			artistCountry  string
-		)
-		err = rows.Scan(
-			&artistID, &artistNickname, &artistBIO, &artistCountry,
-		)
+		artistRow, err := scanArtist(rows)
 		if err != nil {
 			r.logger.Error("failed to scan rows", "error", err)
 			return nil, err
 		}
 
-		artistRow := domain.Artist{
-			ID:       artistID,
-			Nickname: artistNickname,
-			BIO:      artistBIO,
-			Country:  artistCountry,
-		}
-
-		artists = append(artists, &artistRow)
+		artists = append(artists, artistRow)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
@@ -90,32 +109,36 @@ func (r *ArtistRepository) GetAllArtists(ctx context.Context) ([]*domain.Artist,
 }
 
 func (r *ArtistRepository) GetArtistByID(ctx context.Context, id int) (*domain.Artist, error) {
-	query := `select id, nickname, bio, country from artist where id=$1`
+	return r.getArtist(ctx, "id = $1", id)
+}
 
-	var (
-		artistID       int
-		artistNickname string
-		artistBIO      string
-		artistCountry  string
-	)
+func (r *ArtistRepository) GetArtistByMBID(ctx context.Context, mbid string) (*domain.Artist, error) {
+	return r.getArtist(ctx, "mbid = $1::uuid", mbid)
+}
+
+func (r *ArtistRepository) GetArtistBySlug(ctx context.Context, slug string) (*domain.Artist, error) {
+	return r.getArtist(ctx, "slug = $1", slug)
+}
+
+// GetArtistSlugRedirect возвращает текущий слаг по слагу из истории
+func (r *ArtistRepository) GetArtistSlugRedirect(ctx context.Context, slug string) (string, error) {
+	return findSlugRedirect(ctx, r.db, slugArtist, slug)
+}
+
+func (r *ArtistRepository) getArtist(ctx context.Context, where string, arg any) (*domain.Artist, error) {
+	query := artistSelect + " where deleted_at is null and " + where
 
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&artistID, &artistNickname, &artistBIO, &artistCountry)
+	artist, err := scanArtist(conn(ctx, r.db).QueryRow(ctx, query, arg))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.Error("artist not found", "id", id)
+			r.logger.Error("artist not found", "where", where, "value", arg)
 			return nil, err
 		}
 		r.logger.Error("failed to search artist", "error", err)
 		return nil, err
 	}
 
-	return &domain.Artist{
-		ID:       artistID,
-		Nickname: artistNickname,
-		BIO:      artistBIO,
-		Country:  artistCountry,
-	}, nil
+	return artist, nil
 }
 
 func (r *ArtistRepository) UpdateArtist(
@@ -142,6 +165,11 @@ func (r *ArtistRepository) UpdateArtist(
 		args = append(args, *update.Country)
 		argPos++
 	}
+	if update.MBID != nil {
+		fields = append(fields, fmt.Sprintf("mbid=nullif($%d, '')::uuid", argPos))
+		args = append(args, *update.MBID)
+		argPos++
+	}
 
 	if len(fields) == 0 {
 		return nil
@@ -153,13 +181,30 @@ func (r *ArtistRepository) UpdateArtist(
 
 	query := fmt.Sprintf("update artist set %s %s", strings.Join(fields, ", "), whereClause)
 
-	res, err := r.db.Exec(
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(
 		ctx,
 		query,
 		args...,
 	)
 	if err != nil {
 		r.logger.Error("failed to update artist", "id", id, "error", err)
+		return wrapUniqueViolation(err)
+	}
+
+	if update.Nickname != nil && res.RowsAffected() > 0 {
+		if _, err = assignSlug(ctx, tx, slugArtist, id, *update.Nickname); err != nil {
+			r.logger.Error("failed to assign artist slug", "id", id, "error", err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
 		return err
 	}
 
@@ -172,10 +217,286 @@ func (r *ArtistRepository) UpdateArtist(
 	return nil
 }
 
+// DeleteArtist переносит исполнителя в корзину, окончательно его удаляет TrashRepository.Purge
 func (r *ArtistRepository) DeleteArtist(ctx context.Context, id int) error {
-	query := `delete from artist where id=$1`
+	query := `update artist set deleted_at = now() where id=$1 and deleted_at is null`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to delete artist", "id", id, "error", err)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// RestoreArtist возвращает artist из корзины
+func (r *ArtistRepository) RestoreArtist(ctx context.Context, id int) error {
+	query := `update artist set deleted_at = null where id=$1 and deleted_at is not null`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to restore artist", "id", id, "error", err)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return trash.ErrNotInTrash
+	}
+
+	return nil
+}
+
+// GetArtistRedirect возвращает идентификатор артиста, в которого был влит id
+func (r *ArtistRepository) GetArtistRedirect(ctx context.Context, id int) (int, error) {
+	var toID int
+
+	err := r.db.QueryRow(ctx, `select to_id from artist_redirect where from_id=$1`, id).Scan(&toID)
+	if err != nil {
+		return 0, err
+	}
+
+	return toID, nil
+}
+
+// GetDuplicateCandidates отбирает пары артистов, имена которых похожи по триграммам
+// (оператор % из pg_trgm); точную близость по нормализованным именам считает сервис
+func (r *ArtistRepository) GetDuplicateCandidates(ctx context.Context) ([]domain.Candidate, error) {
+	rows, err := r.db.Query(ctx, `
+		select a.id, b.id
+		from artist a
+		join artist b on b.id > a.id and lower(b.nickname) % lower(a.nickname) and b.deleted_at is null
+		where a.deleted_at is null
+		order by a.id, b.id`)
+	if err != nil {
+		r.logger.Error("failed to get duplicate candidates", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		pairs [][2]int
+		ids   []int
+	)
+	for rows.Next() {
+		var p [2]int
+		if err = rows.Scan(&p[0], &p[1]); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		pairs = append(pairs, p)
+		ids = append(ids, p[0], p[1])
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	candidates := make([]domain.Candidate, 0, len(pairs))
+	if len(pairs) == 0 {
+		return candidates, nil
+	}
+
+	rows, err = r.db.Query(ctx, artistSelect+" where id = any($1)", ids)
+	if err != nil {
+		r.logger.Error("failed to get duplicate candidates", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	artists := make(map[int]*domain.Artist)
+	for rows.Next() {
+		a, err := scanArtist(rows)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		artists[a.ID] = a
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, p := range pairs {
+		a, b := artists[p[0]], artists[p[1]]
+		if a == nil || b == nil {
+			continue
+		}
+		candidates = append(candidates, domain.Candidate{Artist: *a, Other: *b})
+	}
+
+	return candidates, nil
+}
+
+// MergeArtists переносит песни и альбомы source на target, оставляет
+// перенаправление со старого идентификатора и удаляет source.
+// Пустые поля target дополняются значениями source. Вызывать нужно
+// в UnitOfWork.Do, чтобы слияние и запись журнала аудита шли одной транзакцией.
+func (r *ArtistRepository) MergeArtists(ctx context.Context, sourceID, targetID int) (*domain.MergeResult, error) {
+	q := conn(ctx, r.db)
+
+	// блокируем обе строки в порядке id, чтобы встречные слияния не взаимоблокировались
+	rows, err := q.Query(ctx, `
+		select id from artist
+		where id in ($1, $2) and deleted_at is null
+		order by id
+		for update`, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if locked != 2 {
+		return nil, pgx.ErrNoRows
+	}
+
+	source, err := scanArtist(q.QueryRow(ctx, artistSelect+" where id=$1", sourceID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.MergeResult{SourceID: sourceID, TargetID: targetID}
+
+	tag, err := q.Exec(ctx, `update song set artist_id=$1 where artist_id=$2`, targetID, sourceID)
+	if err != nil {
+		r.logger.Error("failed to move songs", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+	res.Songs = tag.RowsAffected()
+
+	tag, err = q.Exec(ctx, `update album set artist_id=$1 where artist_id=$2`, targetID, sourceID)
+	if err != nil {
+		r.logger.Error("failed to move albums", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+	res.Albums = tag.RowsAffected()
+
+	// у пользователя остаётся одна рецензия на исполнителя: при совпадении сохраняется рецензия на target
+	_, err = q.Exec(ctx, `
+		delete from review r where r.artist_id=$2
+		and exists (select 1 from review t where t.artist_id=$1 and t.user_id=r.user_id)`,
+		targetID, sourceID)
+	if err != nil {
+		r.logger.Error("failed to drop duplicate reviews", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+	tag, err = q.Exec(ctx, `update review set artist_id=$1 where artist_id=$2`, targetID, sourceID)
+	if err != nil {
+		r.logger.Error("failed to move reviews", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+	res.Reviews = tag.RowsAffected()
+
+	if _, err = q.Exec(ctx, targetCountersRecompute("artist", "artist_id", "x.id = $1"), targetID); err != nil {
+		r.logger.Error("failed to update merged artist rating", "id", targetID, "error", err)
+		return nil, err
+	}
+
+	// упоминания source ведут на target; биография source переходит к target
+	// только вместо пустой, и её упоминания вместе с ней
+	if _, err = q.Exec(ctx, `update mention set target_id=$1 where target_type='artist' and target_id=$2`,
+		targetID, sourceID); err != nil {
+		r.logger.Error("failed to move mentions", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+	_, err = q.Exec(ctx, `
+		update mention set artist_id=$1 where artist_id=$2
+		and exists (select 1 from artist where id=$1 and bio = '')`,
+		targetID, sourceID)
+	if err != nil {
+		r.logger.Error("failed to move bio mentions", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+
+	// подписчики source переходят к target, релизы source остаются в его ленте
+	_, err = q.Exec(ctx, `
+		insert into artist_follow (user_id, artist_id, created_at)
+		select user_id, $1, created_at from artist_follow where artist_id=$2
+		on conflict do nothing`,
+		targetID, sourceID)
+	if err != nil {
+		r.logger.Error("failed to move artist follows", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+	_, err = q.Exec(ctx, `
+		update artist set follower_count = (select count(*) from artist_follow where artist_id=$1)
+		where id=$1`, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = q.Exec(ctx, `update artist_release set artist_id=$1 where artist_id=$2`, targetID, sourceID); err != nil {
+		r.logger.Error("failed to move releases", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+	for _, table := range []string{"scrobble", "scrobble_now_playing"} {
+		if _, err = q.Exec(ctx, `update `+table+` set artist_id=$1 where artist_id=$2`, targetID, sourceID); err != nil {
+			r.logger.Error("failed to move scrobbles", "source", sourceID, "target", targetID, "error", err)
+			return nil, err
+		}
+	}
+
+	// mbid уникален, поэтому сначала снимаем его с source
+	if _, err = q.Exec(ctx, `update artist set mbid=null where id=$1`, sourceID); err != nil {
+		return nil, err
+	}
+	_, err = q.Exec(ctx, `
+		update artist set
+			bio = case when bio = '' then $2 else bio end,
+			country = case when country = '' then $3 else country end,
+			mbid = coalesce(mbid, nullif($4, '')::uuid)
+		where id=$1`,
+		targetID, source.BIO, source.Country, source.MBID,
+	)
+	if err != nil {
+		r.logger.Error("failed to update merged artist", "id", targetID, "error", err)
+		return nil, err
+	}
+
+	if _, err = q.Exec(ctx, `update artist_redirect set to_id=$1 where to_id=$2`, targetID, sourceID); err != nil {
+		return nil, err
+	}
+	if _, err = q.Exec(ctx, `insert into artist_redirect (from_id, to_id) values ($1, $2)`, sourceID, targetID); err != nil {
+		return nil, err
+	}
+
+	// слаги source продолжают открывать оставшегося артиста
+	_, err = q.Exec(ctx, `
+		insert into slug_history (entity, slug, entity_id)
+		select $3, slug, $2 from artist where id=$1 and slug is not null
+		on conflict (entity, slug) do update set entity_id = excluded.entity_id`,
+		sourceID, targetID, slugArtist,
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, err = q.Exec(ctx, `update slug_history set entity_id=$2 where entity=$3 and entity_id=$1`,
+		sourceID, targetID, slugArtist)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = q.Exec(ctx, `delete from artist where id=$1`, sourceID); err != nil {
+		r.logger.Error("failed to delete merged artist", "id", sourceID, "error", err)
+		return nil, err
+	}
+
+	return res, nil
+}
 
-	if _, err := r.db.Exec(ctx, query, id); err != nil {
+// ApplyRatingDelta прибавляет к счётчикам исполнителя вклад изменения рецензии;
+// вызывается в транзакции UnitOfWork вместе с записью рецензии
+func (r *ArtistRepository) ApplyRatingDelta(ctx context.Context, artistID int, d review.RatingDelta) error {
+	if err := applyTargetRatingDelta(ctx, conn(ctx, r.db), "artist", artistID, d); err != nil {
+		r.logger.Error("failed to apply rating delta", "artist_id", artistID, "error", err)
 		return err
 	}
 
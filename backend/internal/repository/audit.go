@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/audit"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type AuditRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewAuditRepository(db *pgxpool.Pool, logger *logger.Logger) *AuditRepository {
+	return &AuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *AuditRepository) CreateEntry(ctx context.Context, entry *domain.Entry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		insert into audit_log
+		(actor_id, actor_email, entity, entity_id, action, before, after, changes, created_at)
+		values (nullif($1, 0), $2, $3, $4, $5, $6, $7, $8, $9)
+		returning id`
+
+	err = conn(ctx, r.db).QueryRow(ctx, query,
+		entry.ActorID,
+		entry.ActorEmail,
+		entry.Entity,
+		entry.EntityID,
+		entry.Action,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+		changes,
+		entry.CreatedAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		r.logger.Error("failed to create audit entry", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) ListEntries(ctx context.Context, filter domain.Filter) ([]domain.Entry, error) {
+	var conditions []string
+	var args []interface{}
+	argPos := 1
+
+	if filter.ActorID != 0 {
+		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", argPos))
+		args = append(args, filter.ActorID)
+		argPos++
+	}
+	if filter.Entity != "" {
+		conditions = append(conditions, fmt.Sprintf("entity = $%d", argPos))
+		args = append(args, filter.Entity)
+		argPos++
+	}
+	if filter.EntityID != 0 {
+		conditions = append(conditions, fmt.Sprintf("entity_id = $%d", argPos))
+		args = append(args, filter.EntityID)
+		argPos++
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argPos))
+		args = append(args, filter.From)
+		argPos++
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", argPos))
+		args = append(args, filter.To)
+		argPos++
+	}
+
+	query := `
+		select id, coalesce(actor_id, 0), actor_email, entity, entity_id,
+		action, before, after, changes, created_at
+		from audit_log`
+	if len(conditions) > 0 {
+		query += " where " + strings.Join(conditions, " and ")
+	}
+	query += fmt.Sprintf(" order by created_at desc, id desc limit $%d offset $%d", argPos, argPos+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list audit entries", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]domain.Entry, 0)
+
+	for rows.Next() {
+		var e domain.Entry
+		var before, after, changes []byte
+		if err = rows.Scan(
+			&e.ID, &e.ActorID, &e.ActorEmail, &e.Entity, &e.EntityID,
+			&e.Action, &before, &after, &changes, &e.CreatedAt,
+		); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		e.Before = before
+		e.After = after
+		if err = json.Unmarshal(changes, &e.Changes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// nullJSON превращает пустое состояние в NULL, а не в пустую строку jsonb
+func nullJSON(b json.RawMessage) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
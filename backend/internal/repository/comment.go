@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/comment"
+	"github.com/maYkiss56/tunes/internal/domain/moderation"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type CommentRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewCommentRepository(db *pgxpool.Pool, logger *logger.Logger) *CommentRepository {
+	return &CommentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// commentColumns порядок колонок должен совпадать со scanComment
+const commentColumns = `
+	c.id, c.review_id, c.parent_id, c.user_id, c.body, c.depth,
+	c.status, c.reason, c.edited_at, c.deleted_at, c.created_at, c.updated_at,
+	u.id, u.email, u.username, u.avatar_url`
+
+const commentSelect = "select" + commentColumns + `
+	from review_comment c
+	join users u on c.user_id = u.id`
+
+func scanComment(row pgx.Row) (domain.Comment, error) {
+	var c domain.Comment
+
+	err := row.Scan(
+		&c.ID,
+		&c.ReviewID,
+		&c.ParentID,
+		&c.UserID,
+		&c.Body,
+		&c.Depth,
+		&c.Status,
+		&c.Reason,
+		&c.EditedAt,
+		&c.DeletedAt,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&c.User.ID,
+		&c.User.Email,
+		&c.User.Username,
+		&c.User.AvatarURL,
+	)
+
+	return c, err
+}
+
+func (r *CommentRepository) CreateComment(ctx context.Context, c *domain.Comment) error {
+	query := `
+		insert into review_comment
+		(review_id, parent_id, user_id, body, depth, status, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		returning id`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		c.ReviewID,
+		c.ParentID,
+		c.UserID,
+		c.Body,
+		c.Depth,
+		c.Status,
+		c.CreatedAt,
+		c.UpdatedAt,
+	).Scan(&c.ID)
+	if err != nil {
+		r.logger.Error("failed to create comment", "review_id", c.ReviewID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *CommentRepository) GetCommentByID(ctx context.Context, id int) (*domain.Comment, error) {
+	c, err := scanComment(conn(ctx, r.db).QueryRow(ctx, commentSelect+" where c.id = $1", id))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Error("failed to get comment", "id", id, "error", err)
+		}
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// GetThread возвращает страницу одобренных корневых комментариев рецензии
+// со всеми одобренными ответами и общее число корневых комментариев.
+// Ветки под скрытыми модератором комментариями не выводятся.
+func (r *CommentRepository) GetThread(
+	ctx context.Context,
+	reviewID, limit, offset int,
+) ([]domain.Comment, int, error) {
+	var total int
+
+	err := r.db.QueryRow(ctx, `
+		select count(*) from review_comment
+		where review_id = $1 and parent_id is null and status = $2`,
+		reviewID, moderation.StatusApproved,
+	).Scan(&total)
+	if err != nil {
+		r.logger.Error("failed to count comments", "review_id", reviewID, "error", err)
+		return nil, 0, err
+	}
+
+	query := `
+		with recursive thread as (
+			select id from (
+				select id from review_comment
+				where review_id = $1 and parent_id is null and status = $2
+				order by created_at, id
+				limit $3 offset $4
+			) roots
+			union all
+			select c.id from review_comment c
+			join thread t on c.parent_id = t.id
+			where c.status = $2
+		)
+		` + commentSelect + `
+		where c.id in (select id from thread)
+		order by c.created_at, c.id`
+
+	comments, err := r.listComments(ctx, query, reviewID, moderation.StatusApproved, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// GetCommentsByStatus очередь модерации: комментарии с заданным статусом, старые первыми
+func (r *CommentRepository) GetCommentsByStatus(
+	ctx context.Context,
+	status moderation.Status,
+	limit, offset int,
+) ([]domain.Comment, error) {
+	query := commentSelect + `
+		where c.status = $1 and c.deleted_at is null
+		order by c.created_at, c.id
+		limit $2 offset $3`
+
+	return r.listComments(ctx, query, status, limit, offset)
+}
+
+func (r *CommentRepository) listComments(ctx context.Context, query string, args ...any) ([]domain.Comment, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to get comments", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]domain.Comment, 0)
+
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		comments = append(comments, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *CommentRepository) UpdateCommentBody(ctx context.Context, id int, body string) error {
+	query := `
+		update review_comment set body = $2, edited_at = now(), updated_at = now()
+		where id = $1 and deleted_at is null`
+
+	res, err := r.db.Exec(ctx, query, id, body)
+	if err != nil {
+		r.logger.Error("failed to update comment", "id", id, "error", err)
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteComment стирает текст, но оставляет комментарий в ветке ради ответов на него
+func (r *CommentRepository) DeleteComment(ctx context.Context, id int) error {
+	query := `
+		update review_comment set body = '', deleted_at = now(), updated_at = now()
+		where id = $1 and deleted_at is null`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to delete comment", "id", id, "error", err)
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *CommentRepository) ModerateComment(
+	ctx context.Context,
+	id int,
+	status moderation.Status,
+	moderatorID int,
+	reason string,
+) error {
+	query := `
+		update review_comment set status = $2, moderated_by = nullif($3, 0), reason = $4, updated_at = now()
+		where id = $1`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id, status, moderatorID, reason)
+	if err != nil {
+		r.logger.Error("failed to moderate comment", "id", id, "error", err)
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
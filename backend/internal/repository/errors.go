@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/maYkiss56/tunes/internal/domain/identifier"
+)
+
+const uniqueViolationCode = "23505"
+
+// wrapUniqueViolation превращает нарушение уникальных индексов
+// внешних идентификаторов в identifier.ErrDuplicate
+func wrapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolationCode {
+		return err
+	}
+
+	switch pgErr.ConstraintName {
+	case "song_isrc_key", "song_mbid_key", "album_upc_key", "album_mbid_key", "artist_mbid_key":
+		return fmt.Errorf("%w: %s", identifier.ErrDuplicate, pgErr.ConstraintName)
+	}
+
+	return err
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maYkiss56/tunes/internal/domain/feed"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type FeedRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewFeedRepository(db *pgxpool.Pool, logger *logger.Logger) *FeedRepository {
+	return &FeedRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// feedStart курсор первой страницы: все события раньше него
+var feedStart = feed.Cursor{At: time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+// feedTargetJoins и feedTargetVisible отбрасывают рецензии на объекты в корзине
+// до limit, иначе страница вышла бы короче после загрузки рецензий
+const (
+	feedTargetJoins = `
+			left join song s on s.id = r.song_id
+			left join album al on al.id = r.album_id
+			left join artist ar on ar.id = r.artist_id`
+	feedTargetVisible = `coalesce(s.deleted_at, al.deleted_at, ar.deleted_at) is null`
+)
+
+// GetFeed собирает ленту при чтении: каждый источник отдаёт не больше limit
+// свежих событий подписок раньше курсора, затем они сливаются по времени.
+// Оценки рецензий заблокированных авторов в ленту не попадают. Публичный список
+// даёт одно событие на момент последнего изменения.
+func (r *FeedRepository) GetFeed(ctx context.Context, userID int, cursor *feed.Cursor, limit int) ([]feed.Item, error) {
+	if cursor == nil {
+		cursor = &feedStart
+	}
+
+	query := `
+		with followees as (
+			select followee_id as id from user_follow where follower_id = $1
+		)
+		select x.kind, x.at, x.object_id, u.id, u.username, u.avatar_url
+		from (
+			(select 'review'::text as kind, r.published_at as at, r.user_id as actor_id, r.id as object_id
+			from review r
+			` + feedTargetJoins + `
+			where r.user_id in (select id from followees) and r.status = 'published'
+			and ` + feedTargetVisible + `
+			and (r.published_at, 'review'::text, r.user_id, r.id) < ($2::timestamptz, $3::text, $4::int, $5::int)
+			order by r.published_at desc
+			limit $6)
+			union all
+			(select 'like'::text, v.updated_at, v.user_id, v.review_id
+			from review_vote v
+			join review r on r.id = v.review_id
+			` + feedTargetJoins + `
+			where v.user_id in (select id from followees) and v.helpful and r.status = 'published'
+			and ` + feedTargetVisible + `
+			and not exists (select 1 from user_block b where b.blocker_id = $1 and b.blocked_id = r.user_id)
+			and (v.updated_at, 'like'::text, v.user_id, v.review_id) < ($2::timestamptz, $3::text, $4::int, $5::int)
+			order by v.updated_at desc
+			limit $6)
+			union all
+			(select 'list'::text, p.updated_at, p.owner_id, p.id
+			from playlist p
+			where p.owner_id in (select id from followees) and p.visibility = 'public'
+			and (p.updated_at, 'list'::text, p.owner_id, p.id) < ($2::timestamptz, $3::text, $4::int, $5::int)
+			order by p.updated_at desc
+			limit $6)
+		) x
+		join users u on u.id = x.actor_id
+		order by x.at desc, x.kind desc, x.actor_id desc, x.object_id desc
+		limit $6`
+
+	rows, err := r.db.Query(ctx, query,
+		userID, cursor.At, cursor.Kind, cursor.ActorID, cursor.ObjectID, limit)
+	if err != nil {
+		r.logger.Error("failed to get feed", "user_id", userID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]feed.Item, 0, limit)
+
+	for rows.Next() {
+		var item feed.Item
+		err = rows.Scan(
+			&item.Kind,
+			&item.At,
+			&item.ObjectID,
+			&item.Actor.ID,
+			&item.Actor.Username,
+			&item.Actor.AvatarURL,
+		)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
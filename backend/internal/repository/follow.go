@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maYkiss56/tunes/internal/domain/follow"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type FollowRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewFollowRepository(db *pgxpool.Pool, logger *logger.Logger) *FollowRepository {
+	return &FollowRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetProfile возвращает пользователя со счётчиками подписок
+func (r *FollowRepository) GetProfile(ctx context.Context, userID int) (*users.User, error) {
+	var u users.User
+
+	err := r.db.QueryRow(ctx, `
+		select id, username, avatar_url, follower_count, following_count
+		from users where id = $1`, userID,
+	).Scan(&u.ID, &u.Username, &u.AvatarURL, &u.FollowerCount, &u.FollowingCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// GetRelation возвращает отношение viewerID к userID
+func (r *FollowRepository) GetRelation(ctx context.Context, viewerID, userID int) (follow.Relation, error) {
+	var rel follow.Relation
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select
+			exists (select 1 from user_follow where follower_id = $1 and followee_id = $2),
+			exists (select 1 from user_follow where follower_id = $2 and followee_id = $1),
+			exists (select 1 from user_block where blocker_id = $1 and blocked_id = $2)`,
+		viewerID, userID,
+	).Scan(&rel.Following, &rel.FollowsYou, &rel.Blocked)
+	if err != nil {
+		r.logger.Error("failed to get relation", "viewer_id", viewerID, "user_id", userID, "error", err)
+		return rel, err
+	}
+
+	return rel, nil
+}
+
+// LockUsers блокирует строки двух пользователей в порядке id до конца транзакции
+// UnitOfWork, чтобы подписка не разошлась со встречной блокировкой.
+// Возвращает false, если кого-то из них нет.
+func (r *FollowRepository) LockUsers(ctx context.Context, a, b int) (bool, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		select id from users where id in ($1, $2)
+		order by id
+		for update`, a, b)
+	if err != nil {
+		r.logger.Error("failed to lock users", "error", err)
+		return false, err
+	}
+	defer rows.Close()
+
+	locked := 0
+	for rows.Next() {
+		locked++
+	}
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	return locked == 2, nil
+}
+
+// IsBlockedEither сообщает, заблокировал ли кто-то из двух пользователей другого
+func (r *FollowRepository) IsBlockedEither(ctx context.Context, a, b int) (bool, error) {
+	var blocked bool
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select exists (select 1 from user_block
+			where (blocker_id = $1 and blocked_id = $2) or (blocker_id = $2 and blocked_id = $1))`,
+		a, b,
+	).Scan(&blocked)
+
+	return blocked, err
+}
+
+// Follow подписывает followerID на followeeID и возвращает false, если подписка уже была;
+// вызывается в транзакции UnitOfWork вместе с изменением счётчиков
+func (r *FollowRepository) Follow(ctx context.Context, followerID, followeeID int) (bool, error) {
+	tag, err := conn(ctx, r.db).Exec(ctx, `
+		insert into user_follow (follower_id, followee_id) values ($1, $2)
+		on conflict do nothing`,
+		followerID, followeeID)
+	if err != nil {
+		r.logger.Error("failed to follow", "follower_id", followerID, "followee_id", followeeID, "error", err)
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	return true, r.applyCounts(ctx, followerID, followeeID, 1)
+}
+
+// Unfollow снимает подписку и возвращает false, если её не было
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followeeID int) (bool, error) {
+	tag, err := conn(ctx, r.db).Exec(ctx,
+		`delete from user_follow where follower_id = $1 and followee_id = $2`,
+		followerID, followeeID)
+	if err != nil {
+		r.logger.Error("failed to unfollow", "follower_id", followerID, "followee_id", followeeID, "error", err)
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	return true, r.applyCounts(ctx, followerID, followeeID, -1)
+}
+
+func (r *FollowRepository) applyCounts(ctx context.Context, followerID, followeeID, delta int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		update users set
+			following_count = following_count + case when id = $1 then $3 else 0 end,
+			follower_count = follower_count + case when id = $2 then $3 else 0 end
+		where id in ($1, $2)`,
+		followerID, followeeID, delta)
+	if err != nil {
+		r.logger.Error("failed to update follow counts", "follower_id", followerID, "followee_id", followeeID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// Block блокирует blockedID; подписки между пользователями снимает вызывающий
+func (r *FollowRepository) Block(ctx context.Context, blockerID, blockedID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into user_block (blocker_id, blocked_id) values ($1, $2)
+		on conflict do nothing`,
+		blockerID, blockedID)
+	if err != nil {
+		r.logger.Error("failed to block user", "blocker_id", blockerID, "blocked_id", blockedID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *FollowRepository) Unblock(ctx context.Context, blockerID, blockedID int) error {
+	_, err := r.db.Exec(ctx,
+		`delete from user_block where blocker_id = $1 and blocked_id = $2`,
+		blockerID, blockedID)
+	if err != nil {
+		r.logger.Error("failed to unblock user", "blocker_id", blockerID, "blocked_id", blockedID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetFollowers возвращает подписчиков пользователя, новые первыми
+func (r *FollowRepository) GetFollowers(ctx context.Context, userID, limit, offset int) ([]users.User, error) {
+	return r.listUsers(ctx, `
+		select u.id, u.username, u.avatar_url
+		from user_follow f
+		join users u on u.id = f.follower_id
+		where f.followee_id = $1
+		order by f.created_at desc, u.id desc
+		limit $2 offset $3`,
+		userID, limit, offset)
+}
+
+// GetFollowing возвращает подписки пользователя, новые первыми
+func (r *FollowRepository) GetFollowing(ctx context.Context, userID, limit, offset int) ([]users.User, error) {
+	return r.listUsers(ctx, `
+		select u.id, u.username, u.avatar_url
+		from user_follow f
+		join users u on u.id = f.followee_id
+		where f.follower_id = $1
+		order by f.created_at desc, u.id desc
+		limit $2 offset $3`,
+		userID, limit, offset)
+}
+
+// GetBlocked возвращает пользователей, заблокированных userID
+func (r *FollowRepository) GetBlocked(ctx context.Context, userID, limit, offset int) ([]users.User, error) {
+	return r.listUsers(ctx, `
+		select u.id, u.username, u.avatar_url
+		from user_block b
+		join users u on u.id = b.blocked_id
+		where b.blocker_id = $1
+		order by b.created_at desc, u.id desc
+		limit $2 offset $3`,
+		userID, limit, offset)
+}
+
+func (r *FollowRepository) listUsers(ctx context.Context, query string, args ...any) ([]users.User, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to get users", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := make([]users.User, 0)
+
+	for rows.Next() {
+		var u users.User
+		if err = rows.Scan(&u.ID, &u.Username, &u.AvatarURL); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		list = append(list, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
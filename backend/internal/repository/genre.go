@@ -11,6 +11,7 @@ import (
 
 	domain "github.com/maYkiss56/tunes/internal/domain/genre"
 	"github.com/maYkiss56/tunes/internal/domain/genre/dto"
+	"github.com/maYkiss56/tunes/internal/domain/trash"
 	"github.com/maYkiss56/tunes/internal/logger"
 )
 
@@ -29,7 +30,7 @@ func NewGenreRepository(db *pgxpool.Pool, logger *logger.Logger) *GenreRepositor
 func (r *GenreRepository) CreateGenre(ctx context.Context, genre *domain.Genre) error {
 	query := `insert into genre (title, image_url) values ($1, $2) returning id`
 
-	err := r.db.QueryRow(ctx, query, genre.Title, genre.ImageURL).Scan(&genre.ID)
+	err := conn(ctx, r.db).QueryRow(ctx, query, genre.Title, genre.ImageURL).Scan(&genre.ID)
 	if err != nil {
 		r.logger.Error("failed to create genre", "error", err)
 		return err
@@ -39,7 +40,7 @@ func (r *GenreRepository) CreateGenre(ctx context.Context, genre *domain.Genre)
 }
 
 func (r GenreRepository) GetAllGenre(ctx context.Context) ([]*domain.Genre, error) {
-	query := `select id, title, image_url from genre`
+	query := `select id, title, image_url from genre where deleted_at is null`
 
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
@@ -78,7 +79,7 @@ func (r GenreRepository) GetAllGenre(ctx context.Context) ([]*domain.Genre, erro
 }
 
 func (r *GenreRepository) GetGenreByID(ctx context.Context, id int) (*domain.Genre, error) {
-	query := `select id, title, image_url from genre where id=$1`
+	query := `select id, title, image_url from genre where id=$1 and deleted_at is null`
 
 	var (
 		genreID       int
@@ -86,7 +87,7 @@ func (r *GenreRepository) GetGenreByID(ctx context.Context, id int) (*domain.Gen
 		genreImageURL string
 	)
 
-	err := r.db.QueryRow(ctx, query, id).Scan(&genreID, &genreTitle, &genreImageURL)
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&genreID, &genreTitle, &genreImageURL)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			r.logger.Error("genre not found", "id", id)
@@ -133,7 +134,7 @@ func (r *GenreRepository) UpdateGenre(
 
 	query := fmt.Sprintf("update genre set %s %s", strings.Join(fields, ", "), whereClause)
 
-	res, err := r.db.Exec(
+	res, err := conn(ctx, r.db).Exec(
 		ctx,
 		query,
 		args...,
@@ -153,12 +154,36 @@ func (r *GenreRepository) UpdateGenre(
 	return nil
 }
 
+// DeleteGenre переносит жанр в корзину, окончательно его удаляет TrashRepository.Purge
 func (r *GenreRepository) DeleteGenre(ctx context.Context, id int) error {
-	query := `delete from genre where id=$1`
+	query := `update genre set deleted_at = now() where id=$1 and deleted_at is null`
 
-	if _, err := r.db.Exec(ctx, query, id); err != nil {
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to delete genre", "id", id, "error", err)
 		return err
 	}
 
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// RestoreGenre возвращает genre из корзины
+func (r *GenreRepository) RestoreGenre(ctx context.Context, id int) error {
+	query := `update genre set deleted_at = null where id=$1 and deleted_at is not null`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to restore genre", "id", id, "error", err)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return trash.ErrNotInTrash
+	}
+
 	return nil
 }
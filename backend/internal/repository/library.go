@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/library"
+	"github.com/maYkiss56/tunes/internal/domain/library/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type LibraryRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewLibraryRepository(db *pgxpool.Pool, logger *logger.Logger) *LibraryRepository {
+	return &LibraryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// libraryColumn колонка library_item и таблица каталога для вида объекта
+func libraryColumn(kind domain.Kind) (column, table string) {
+	if kind == domain.KindAlbum {
+		return "album_id", "album"
+	}
+	return "song_id", "song"
+}
+
+// TargetExists проверяет, что песня или альбом есть и не в корзине
+func (r *LibraryRepository) TargetExists(ctx context.Context, kind domain.Kind, id int) (bool, error) {
+	_, table := libraryColumn(kind)
+
+	var exists bool
+
+	err := r.db.QueryRow(ctx,
+		`select exists (select 1 from `+table+` where id = $1 and deleted_at is null)`, id,
+	).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check library target", "kind", kind, "id", id, "error", err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *LibraryRepository) GetStatus(ctx context.Context, userID int, kind domain.Kind, id int) (domain.Status, error) {
+	column, _ := libraryColumn(kind)
+
+	var s domain.Status
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select favorite, favorited_at, coalesce(shelf, ''), shelved_at
+		from library_item
+		where user_id = $1 and `+column+` = $2`, userID, id,
+	).Scan(&s.Favorite, &s.FavoritedAt, &s.Shelf, &s.ShelvedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Status{}, nil
+		}
+		r.logger.Error("failed to get library status", "user_id", userID, "kind", kind, "id", id, "error", err)
+		return domain.Status{}, err
+	}
+
+	return s, nil
+}
+
+// GetStatuses возвращает отметки пользователя для страницы песен или альбомов
+// одним запросом; объектов без отметок в результате нет
+func (r *LibraryRepository) GetStatuses(
+	ctx context.Context,
+	userID int,
+	kind domain.Kind,
+	ids []int,
+) (map[int]domain.Status, error) {
+	column, _ := libraryColumn(kind)
+
+	rows, err := r.db.Query(ctx, `
+		select `+column+`, favorite, favorited_at, coalesce(shelf, ''), shelved_at
+		from library_item
+		where user_id = $1 and `+column+` = any($2)`, userID, ids)
+	if err != nil {
+		r.logger.Error("failed to get library statuses", "user_id", userID, "kind", kind, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := make(map[int]domain.Status, len(ids))
+
+	for rows.Next() {
+		var (
+			id int
+			s  domain.Status
+		)
+		if err = rows.Scan(&id, &s.Favorite, &s.FavoritedAt, &s.Shelf, &s.ShelvedAt); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		statuses[id] = s
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// AddFavorite отмечает объект избранным; время первой отметки сохраняется
+func (r *LibraryRepository) AddFavorite(ctx context.Context, userID int, kind domain.Kind, id int) error {
+	column, _ := libraryColumn(kind)
+
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into library_item (user_id, `+column+`, favorite, favorited_at)
+		values ($1, $2, true, now())
+		on conflict (user_id, `+column+`) where `+column+` is not null do update set
+			favorite = true,
+			favorited_at = coalesce(library_item.favorited_at, now()),
+			updated_at = now()`,
+		userID, id)
+	if err != nil {
+		r.logger.Error("failed to add favorite", "user_id", userID, "kind", kind, "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetShelf ставит объект на полку want или listened, снимая с другой;
+// при повторе время не меняется
+func (r *LibraryRepository) SetShelf(
+	ctx context.Context,
+	userID int,
+	kind domain.Kind,
+	id int,
+	shelf domain.Shelf,
+) error {
+	column, _ := libraryColumn(kind)
+
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into library_item (user_id, `+column+`, shelf, shelved_at)
+		values ($1, $2, $3, now())
+		on conflict (user_id, `+column+`) where `+column+` is not null do update set
+			shelved_at = case when library_item.shelf = excluded.shelf
+				then library_item.shelved_at else now() end,
+			shelf = excluded.shelf,
+			updated_at = now()`,
+		userID, id, shelf)
+	if err != nil {
+		r.logger.Error("failed to set library shelf", "user_id", userID, "kind", kind, "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveFromShelf снимает объект с полки; запись без отметок удаляется
+func (r *LibraryRepository) RemoveFromShelf(
+	ctx context.Context,
+	userID int,
+	kind domain.Kind,
+	id int,
+	shelf domain.Shelf,
+) error {
+	column, _ := libraryColumn(kind)
+	q := conn(ctx, r.db)
+
+	var err error
+	if shelf == domain.ShelfFavorite {
+		_, err = q.Exec(ctx, `
+			update library_item set favorite = false, favorited_at = null, updated_at = now()
+			where user_id = $1 and `+column+` = $2 and favorite`,
+			userID, id)
+	} else {
+		_, err = q.Exec(ctx, `
+			update library_item set shelf = null, shelved_at = null, updated_at = now()
+			where user_id = $1 and `+column+` = $2 and shelf = $3`,
+			userID, id, shelf)
+	}
+	if err != nil {
+		r.logger.Error("failed to remove from library shelf", "user_id", userID, "kind", kind, "id", id, "error", err)
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+		delete from library_item
+		where user_id = $1 and `+column+` = $2 and not favorite and shelf is null`,
+		userID, id)
+	if err != nil {
+		r.logger.Error("failed to delete empty library item", "user_id", userID, "kind", kind, "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetItems выводит библиотеку пользователя; объекты из корзины скрываются.
+// С полкой записи идут по времени отметки на ней, без полки по последнему изменению.
+func (r *LibraryRepository) GetItems(ctx context.Context, userID int, req dto.ListRequest) ([]domain.Item, error) {
+	order := "l.updated_at"
+	switch req.Shelf {
+	case domain.ShelfFavorite:
+		order = "l.favorited_at"
+	case domain.ShelfWant, domain.ShelfListened:
+		order = "l.shelved_at"
+	}
+
+	query := `
+		select case when l.album_id is null then 'song' else 'album' end,
+		coalesce(l.song_id, l.album_id),
+		coalesce(s.title, a.title), coalesce(s.slug, a.slug, ''), coalesce(s.image_url, a.image_url, ''),
+		ar.id, ar.nickname,
+		l.favorite, l.favorited_at, coalesce(l.shelf, ''), l.shelved_at,
+		l.created_at, l.updated_at
+		from library_item l
+		left join song s on s.id = l.song_id
+		left join album a on a.id = l.album_id
+		join artist ar on ar.id = coalesce(s.artist_id, a.artist_id)
+		where l.user_id = $1
+		and s.deleted_at is null and a.deleted_at is null
+		and ($4 = '' or ($4 = 'favorite' and l.favorite) or l.shelf = $4)
+		and ($5 = '' or ($5 = 'album') = (l.album_id is not null))
+		order by ` + order + ` desc, l.id desc
+		limit $2 offset $3`
+
+	rows, err := r.db.Query(ctx, query, userID, req.Limit, req.Offset, string(req.Shelf), string(req.Kind))
+	if err != nil {
+		r.logger.Error("failed to get library", "user_id", userID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.Item, 0)
+
+	for rows.Next() {
+		var i domain.Item
+		err = rows.Scan(
+			&i.Kind,
+			&i.TargetID,
+			&i.Title,
+			&i.Slug,
+			&i.ImageURL,
+			&i.ArtistID,
+			&i.ArtistName,
+			&i.Status.Favorite,
+			&i.Status.FavoritedAt,
+			&i.Status.Shelf,
+			&i.Status.ShelvedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		items = append(items, i)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/lyrics"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type LyricsRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewLyricsRepository(db *pgxpool.Pool, logger *logger.Logger) *LyricsRepository {
+	return &LyricsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *LyricsRepository) GetLyrics(ctx context.Context, songID int) (*domain.Lyrics, error) {
+	query := `
+		select l.song_id, l.plain, l.lrc, l.updated_at
+		from song_lyrics l
+		join song s on s.id = l.song_id
+		where l.song_id = $1 and s.deleted_at is null`
+
+	var l domain.Lyrics
+	err := conn(ctx, r.db).QueryRow(ctx, query, songID).Scan(&l.SongID, &l.Plain, &l.LRC, &l.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		r.logger.Error("failed to get lyrics", "song_id", songID, "error", err)
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+func (r *LyricsRepository) UpsertLyrics(ctx context.Context, l *domain.Lyrics) error {
+	query := `
+		insert into song_lyrics (song_id, plain, lrc, updated_at)
+		values ($1, $2, $3, $4)
+		on conflict (song_id) do update set
+			plain = excluded.plain,
+			lrc = excluded.lrc,
+			updated_at = excluded.updated_at`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, l.SongID, l.Plain, l.LRC, l.UpdatedAt)
+	if err != nil {
+		r.logger.Error("failed to save lyrics", "song_id", l.SongID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *LyricsRepository) DeleteLyrics(ctx context.Context, songID int) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `delete from song_lyrics where song_id=$1`, songID)
+	if err != nil {
+		r.logger.Error("failed to delete lyrics", "song_id", songID, "error", err)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/mention"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type MentionRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewMentionRepository(db *pgxpool.Pool, logger *logger.Logger) *MentionRepository {
+	return &MentionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// mentionsColumn выбирает упоминания текста одним jsonb-массивом, который
+// сканируется в []mention.Mention. Подпись берётся из текущего имени сущности,
+// упоминания удалённых сущностей пропускаются. where связывает mention m с источником.
+func mentionsColumn(where string) string {
+	return `
+	(select coalesce(jsonb_agg(jsonb_build_object(
+		'kind', m.target_type, 'id', m.target_id, 'token', m.token,
+		'label', coalesce(mu.username, ms.title, mal.title, mar.nickname)) order by m.id), '[]')
+		from mention m
+		left join users mu on m.target_type = 'user' and mu.id = m.target_id
+		left join song ms on m.target_type = 'song' and ms.id = m.target_id and ms.deleted_at is null
+		left join album mal on m.target_type = 'album' and mal.id = m.target_id and mal.deleted_at is null
+		left join artist mar on m.target_type = 'artist' and mar.id = m.target_id and mar.deleted_at is null
+		where ` + where + `
+		and coalesce(mu.username, ms.title, mal.title, mar.nickname) is not null)`
+}
+
+// catalogTables таблица и колонка подписи для упоминаний песен, альбомов и исполнителей
+var catalogTables = map[domain.Kind][2]string{
+	domain.KindSong:   {"song", "title"},
+	domain.KindAlbum:  {"album", "title"},
+	domain.KindArtist: {"artist", "nickname"},
+}
+
+// Resolve сопоставляет упоминания с существующими сущностями: пользователей
+// по имени без учёта регистра, остальных по id или слагу. Ненайденные пропускаются.
+func (r *MentionRepository) Resolve(ctx context.Context, refs []domain.Ref) ([]domain.Mention, error) {
+	byKind := make(map[domain.Kind][]domain.Ref)
+	for _, ref := range refs {
+		byKind[ref.Kind] = append(byKind[ref.Kind], ref)
+	}
+
+	found := make(map[string]domain.Mention, len(refs))
+
+	for kind, kindRefs := range byKind {
+		var err error
+		if kind == domain.KindUser {
+			err = r.resolveUsers(ctx, kindRefs, found)
+		} else {
+			err = r.resolveCatalog(ctx, kind, kindRefs, found)
+		}
+		if err != nil {
+			r.logger.Error("failed to resolve mentions", "kind", kind, "error", err)
+			return nil, err
+		}
+	}
+
+	// порядок упоминаний в тексте сохраняется
+	mentions := make([]domain.Mention, 0, len(found))
+	for _, ref := range refs {
+		if m, ok := found[ref.Token()]; ok {
+			mentions = append(mentions, m)
+		}
+	}
+
+	return mentions, nil
+}
+
+func (r *MentionRepository) resolveUsers(ctx context.Context, refs []domain.Ref, found map[string]domain.Mention) error {
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.Key
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, `select id, username from users where lower(username) = any($1)`, names)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := domain.Mention{Kind: domain.KindUser}
+		if err = rows.Scan(&m.ID, &m.Label); err != nil {
+			return err
+		}
+		m.Token = domain.Ref{Kind: domain.KindUser, Key: strings.ToLower(m.Label)}.Token()
+		found[m.Token] = m
+	}
+
+	return rows.Err()
+}
+
+func (r *MentionRepository) resolveCatalog(
+	ctx context.Context,
+	kind domain.Kind,
+	refs []domain.Ref,
+	found map[string]domain.Mention,
+) error {
+	table, ok := catalogTables[kind]
+	if !ok {
+		return nil
+	}
+
+	var (
+		ids   []int
+		slugs []string
+	)
+	for _, ref := range refs {
+		if id, err := strconv.Atoi(ref.Key); err == nil {
+			ids = append(ids, id)
+		} else {
+			slugs = append(slugs, ref.Key)
+		}
+	}
+
+	query := `select id, coalesce(slug, ''), ` + table[1] + ` from ` + table[0] + `
+		where deleted_at is null and (id = any($1) or slug = any($2))`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, ids, slugs)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			m    = domain.Mention{Kind: kind}
+			slug string
+		)
+		if err = rows.Scan(&m.ID, &slug, &m.Label); err != nil {
+			return err
+		}
+
+		// на сущность можно сослаться и по id, и по слагу в одном тексте
+		for _, key := range []string{strconv.Itoa(m.ID), slug} {
+			if key == "" {
+				continue
+			}
+			m.Token = domain.Ref{Kind: kind, Key: key}.Token()
+			found[m.Token] = m
+		}
+	}
+
+	return rows.Err()
+}
+
+// ReplaceReviewMentions заменяет упоминания рецензии и возвращает добавленные
+func (r *MentionRepository) ReplaceReviewMentions(
+	ctx context.Context,
+	reviewID int,
+	mentions []domain.Mention,
+) ([]domain.Mention, error) {
+	return r.replace(ctx, "review_id", reviewID, mentions)
+}
+
+// ReplaceArtistMentions заменяет упоминания в биографии исполнителя и возвращает добавленные
+func (r *MentionRepository) ReplaceArtistMentions(
+	ctx context.Context,
+	artistID int,
+	mentions []domain.Mention,
+) ([]domain.Mention, error) {
+	return r.replace(ctx, "artist_id", artistID, mentions)
+}
+
+// replace удаляет упоминания, которых больше нет в тексте, и добавляет новые;
+// column колонка mention, ссылающаяся на источник
+func (r *MentionRepository) replace(
+	ctx context.Context,
+	column string,
+	sourceID int,
+	mentions []domain.Mention,
+) ([]domain.Mention, error) {
+	var (
+		kinds  = make([]string, len(mentions))
+		ids    = make([]int, len(mentions))
+		tokens = make([]string, len(mentions))
+	)
+	for i, m := range mentions {
+		kinds[i], ids[i], tokens[i] = string(m.Kind), m.ID, m.Token
+	}
+
+	q := conn(ctx, r.db)
+
+	_, err := q.Exec(ctx, `delete from mention where `+column+` = $1 and token <> all($2)`, sourceID, tokens)
+	if err != nil {
+		r.logger.Error("failed to delete mentions", column, sourceID, "error", err)
+		return nil, err
+	}
+
+	query := `
+		insert into mention (` + column + `, target_type, target_id, token)
+		select $1, t.kind, t.id, t.token
+		from unnest($2::text[], $3::int[], $4::text[]) as t(kind, id, token)
+		on conflict do nothing
+		returning target_type, target_id, token`
+
+	rows, err := q.Query(ctx, query, sourceID, kinds, ids, tokens)
+	if err != nil {
+		r.logger.Error("failed to add mentions", column, sourceID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	added := make([]domain.Mention, 0)
+
+	for rows.Next() {
+		var m domain.Mention
+		if err = rows.Scan(&m.Kind, &m.ID, &m.Token); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		added = append(added, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/notification"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type NotificationRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewNotificationRepository(db *pgxpool.Pool, logger *logger.Logger) *NotificationRepository {
+	return &NotificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateNotification сохраняет уведомление в транзакции UnitOfWork, если она открыта,
+// чтобы оно не пережило откат вызвавшего его изменения
+func (r *NotificationRepository) CreateNotification(ctx context.Context, n *domain.Notification) error {
+	query := `
+		insert into notification (user_id, type, actor_id, payload, created_at)
+		values ($1, $2, $3, $4, $5)
+		returning id`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, n.UserID, n.Type, n.ActorID, n.Payload, n.CreatedAt).Scan(&n.ID)
+	if err != nil {
+		r.logger.Error("failed to create notification", "user_id", n.UserID, "type", n.Type, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// CreateArtistFollowerNotifications рассылает одно уведомление всем подписчикам исполнителя
+// и возвращает их число; пишет в транзакции UnitOfWork, если она открыта
+func (r *NotificationRepository) CreateArtistFollowerNotifications(
+	ctx context.Context,
+	artistID int,
+	t domain.Type,
+	payload []byte,
+) (int64, error) {
+	query := `
+		insert into notification (user_id, type, payload, created_at)
+		select user_id, $2, $3, now() from artist_follow
+		where artist_id = $1`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, artistID, t, payload)
+	if err != nil {
+		r.logger.Error("failed to notify artist followers", "artist_id", artistID, "type", t, "error", err)
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// notificationSelect порядок колонок должен совпадать со scanNotification
+const notificationSelect = `
+	select n.id, n.user_id, n.type, n.actor_id, n.payload, n.read_at, n.created_at,
+	u.id, u.username, u.avatar_url
+	from notification n
+	left join users u on n.actor_id = u.id`
+
+func scanNotification(row pgx.Row) (domain.Notification, error) {
+	var (
+		n                   domain.Notification
+		actorID             *int
+		username, avatarURL *string
+	)
+
+	err := row.Scan(
+		&n.ID,
+		&n.UserID,
+		&n.Type,
+		&n.ActorID,
+		&n.Payload,
+		&n.ReadAt,
+		&n.CreatedAt,
+		&actorID,
+		&username,
+		&avatarURL,
+	)
+	if err != nil {
+		return n, err
+	}
+
+	if actorID != nil {
+		n.Actor = &users.User{ID: *actorID, Username: *username}
+		if avatarURL != nil {
+			n.Actor.AvatarURL = *avatarURL
+		}
+	}
+
+	return n, nil
+}
+
+func (r *NotificationRepository) GetNotificationByID(ctx context.Context, id int) (*domain.Notification, error) {
+	n, err := scanNotification(r.db.QueryRow(ctx, notificationSelect+" where n.id = $1", id))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Error("failed to get notification", "id", id, "error", err)
+		}
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// GetNotifications возвращает уведомления пользователя, новые первыми;
+// unread оставляет непрочитанные, непустой t только уведомления этого вида
+func (r *NotificationRepository) GetNotifications(
+	ctx context.Context,
+	userID int,
+	unread bool,
+	t domain.Type,
+	limit, offset int,
+) ([]domain.Notification, error) {
+	query := notificationSelect + `
+		where n.user_id = $1
+		and (not $2 or n.read_at is null)
+		and ($3 = '' or n.type = $3)
+		order by n.created_at desc, n.id desc
+		limit $4 offset $5`
+
+	return r.listNotifications(ctx, query, userID, unread, t, limit, offset)
+}
+
+// GetNotificationsAfter возвращает уведомления пользователя с id больше afterID
+// в порядке создания, чтобы дослать пропущенные переподключившемуся потоку
+func (r *NotificationRepository) GetNotificationsAfter(
+	ctx context.Context,
+	userID, afterID, limit int,
+) ([]domain.Notification, error) {
+	query := notificationSelect + `
+		where n.user_id = $1 and n.id > $2
+		order by n.id
+		limit $3`
+
+	return r.listNotifications(ctx, query, userID, afterID, limit)
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	var count int
+
+	err := r.db.QueryRow(ctx, `
+		select count(*) from notification where user_id = $1 and read_at is null`, userID,
+	).Scan(&count)
+	if err != nil {
+		r.logger.Error("failed to count unread notifications", "user_id", userID, "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkRead отмечает прочитанными уведомления пользователя из ids, все при пустом ids,
+// и возвращает число изменённых; уже прочитанные не меняются
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID int, ids []int) (int64, error) {
+	if ids == nil {
+		ids = []int{}
+	}
+
+	res, err := r.db.Exec(ctx, `
+		update notification set read_at = now()
+		where user_id = $1 and read_at is null
+		and (cardinality($2::int[]) = 0 or id = any($2))`,
+		userID, ids)
+	if err != nil {
+		r.logger.Error("failed to mark notifications read", "user_id", userID, "error", err)
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// Listen слушает канал notification на отдельном соединении и передаёт события
+// в handle до отмены ctx или обрыва соединения
+func (r *NotificationRepository) Listen(ctx context.Context, handle func(domain.Event)) error {
+	pooled, err := r.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// соединение с LISTEN в пул не возвращается
+	c := pooled.Hijack()
+	defer c.Close(context.Background())
+
+	if _, err = c.Exec(ctx, "listen notification"); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := c.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var e domain.Event
+		if err = json.Unmarshal([]byte(msg.Payload), &e); err != nil {
+			r.logger.Error("invalid notification event", "payload", msg.Payload, "error", err)
+			continue
+		}
+
+		handle(e)
+	}
+}
+
+func (r *NotificationRepository) listNotifications(
+	ctx context.Context,
+	query string,
+	args ...any,
+) ([]domain.Notification, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to get notifications", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]domain.Notification, 0)
+
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		notifications = append(notifications, n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
@@ -0,0 +1,573 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/playlist"
+	"github.com/maYkiss56/tunes/internal/domain/playlist/dto"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type PlaylistRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewPlaylistRepository(db *pgxpool.Pool, logger *logger.Logger) *PlaylistRepository {
+	return &PlaylistRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// playlistSelect порядок колонок должен совпадать со scanPlaylist
+const playlistSelect = `
+	select p.id, p.owner_id, p.title, p.description, p.visibility, p.ranked,
+	p.item_count, p.like_count, p.created_at, p.updated_at,
+	u.id, u.username, u.avatar_url
+	from playlist p
+	join users u on u.id = p.owner_id`
+
+func scanPlaylist(row pgx.Row) (domain.List, error) {
+	var l domain.List
+
+	err := row.Scan(
+		&l.ID,
+		&l.OwnerID,
+		&l.Title,
+		&l.Description,
+		&l.Visibility,
+		&l.Ranked,
+		&l.ItemCount,
+		&l.LikeCount,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+		&l.Owner.ID,
+		&l.Owner.Username,
+		&l.Owner.AvatarURL,
+	)
+
+	return l, err
+}
+
+// playlistItemSelect порядок колонок должен совпадать со scanPlaylistItem.
+// Позиции с песнями и альбомами из корзины не выводятся.
+const playlistItemSelect = `
+	select i.id, i.playlist_id, i.position, i.note, i.added_by, i.created_at,
+	case when i.album_id is null then 'song' else 'album' end,
+	coalesce(i.song_id, i.album_id),
+	coalesce(s.title, a.title), coalesce(s.slug, a.slug, ''), coalesce(s.image_url, a.image_url, ''),
+	ar.id, ar.nickname
+	from playlist_item i
+	left join song s on s.id = i.song_id
+	left join album a on a.id = i.album_id
+	join artist ar on ar.id = coalesce(s.artist_id, a.artist_id)`
+
+const playlistItemLive = "s.deleted_at is null and a.deleted_at is null"
+
+func scanPlaylistItem(row pgx.Row) (domain.Item, error) {
+	var i domain.Item
+
+	err := row.Scan(
+		&i.ID,
+		&i.ListID,
+		&i.Position,
+		&i.Note,
+		&i.AddedBy,
+		&i.CreatedAt,
+		&i.Target.Kind,
+		&i.Target.ID,
+		&i.Title,
+		&i.Slug,
+		&i.ImageURL,
+		&i.ArtistID,
+		&i.ArtistName,
+	)
+
+	return i, err
+}
+
+func (r *PlaylistRepository) CreateList(ctx context.Context, l *domain.List) error {
+	query := `
+		insert into playlist (owner_id, title, description, visibility, ranked, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7)
+		returning id`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		l.OwnerID,
+		l.Title,
+		l.Description,
+		l.Visibility,
+		l.Ranked,
+		l.CreatedAt,
+		l.UpdatedAt,
+	).Scan(&l.ID)
+	if err != nil {
+		r.logger.Error("failed to create list", "owner_id", l.OwnerID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) GetList(ctx context.Context, id int) (*domain.List, error) {
+	l, err := scanPlaylist(conn(ctx, r.db).QueryRow(ctx, playlistSelect+" where p.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// LockList блокирует список до конца транзакции UnitOfWork: изменения
+// позиций одного списка выполняются по очереди
+func (r *PlaylistRepository) LockList(ctx context.Context, id int) (*domain.List, error) {
+	l, err := scanPlaylist(conn(ctx, r.db).QueryRow(ctx, playlistSelect+" where p.id = $1 for update of p", id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+func (r *PlaylistRepository) IsEditor(ctx context.Context, listID, userID int) (bool, error) {
+	var editor bool
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select exists (select 1 from playlist_editor where playlist_id = $1 and user_id = $2)`,
+		listID, userID,
+	).Scan(&editor)
+	if err != nil {
+		r.logger.Error("failed to check list editor", "list_id", listID, "user_id", userID, "error", err)
+		return false, err
+	}
+
+	return editor, nil
+}
+
+func (r *PlaylistRepository) IsLiked(ctx context.Context, listID, userID int) (bool, error) {
+	var liked bool
+
+	err := r.db.QueryRow(ctx, `
+		select exists (select 1 from playlist_like where playlist_id = $1 and user_id = $2)`,
+		listID, userID,
+	).Scan(&liked)
+	if err != nil {
+		r.logger.Error("failed to check list like", "list_id", listID, "user_id", userID, "error", err)
+		return false, err
+	}
+
+	return liked, nil
+}
+
+func (r *PlaylistRepository) UpdateList(ctx context.Context, id int, req dto.UpdateRequest) error {
+	query := `
+		update playlist set
+			title = coalesce($2, title),
+			description = coalesce($3, description),
+			visibility = coalesce($4, visibility),
+			ranked = coalesce($5, ranked),
+			updated_at = now()
+		where id = $1`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id, req.Title, req.Description, req.Visibility, req.Ranked)
+	if err != nil {
+		r.logger.Error("failed to update list", "id", id, "error", err)
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) DeleteList(ctx context.Context, id int) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `delete from playlist where id = $1`, id)
+	if err != nil {
+		r.logger.Error("failed to delete list", "id", id, "error", err)
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetPublicLists возвращает публичные списки с фильтрами из запроса
+func (r *PlaylistRepository) GetPublicLists(ctx context.Context, req dto.ListRequest) ([]domain.List, error) {
+	order := "p.updated_at desc, p.id desc"
+	if req.Sort == dto.SortPopular {
+		order = "p.like_count desc, p.updated_at desc, p.id desc"
+	}
+
+	query := playlistSelect + `
+		where p.visibility = 'public'
+		and ($1 = 0 or p.owner_id = $1)
+		and ($2 = 0 or exists (select 1 from playlist_item i where i.playlist_id = p.id and i.song_id = $2))
+		and ($3 = 0 or exists (select 1 from playlist_item i where i.playlist_id = p.id and i.album_id = $3))
+		order by ` + order + `
+		limit $4 offset $5`
+
+	return r.listPlaylists(ctx, query, req.UserID, req.SongID, req.AlbumID, req.Limit, req.Offset)
+}
+
+// GetPublicListsByIDs возвращает публичные списки из ids для ленты
+func (r *PlaylistRepository) GetPublicListsByIDs(ctx context.Context, ids []int) ([]domain.List, error) {
+	return r.listPlaylists(ctx, playlistSelect+" where p.id = any($1) and p.visibility = 'public'", ids)
+}
+
+// GetUserLists возвращает все списки, которые пользователь ведёт или редактирует
+func (r *PlaylistRepository) GetUserLists(ctx context.Context, userID, limit, offset int) ([]domain.List, error) {
+	query := playlistSelect + `
+		where p.owner_id = $1
+		or exists (select 1 from playlist_editor e where e.playlist_id = p.id and e.user_id = $1)
+		order by p.updated_at desc, p.id desc
+		limit $2 offset $3`
+
+	return r.listPlaylists(ctx, query, userID, limit, offset)
+}
+
+func (r *PlaylistRepository) GetItems(ctx context.Context, listID int) ([]domain.Item, error) {
+	query := playlistItemSelect + `
+		where i.playlist_id = $1 and ` + playlistItemLive + `
+		order by i.position`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, listID)
+	if err != nil {
+		r.logger.Error("failed to get list items", "list_id", listID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.Item, 0)
+
+	for rows.Next() {
+		item, err := scanPlaylistItem(rows)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetItem возвращает позицию списка, в том числе с объектом из корзины
+func (r *PlaylistRepository) GetItem(ctx context.Context, listID, itemID int) (*domain.Item, error) {
+	item, err := scanPlaylistItem(conn(ctx, r.db).QueryRow(ctx,
+		playlistItemSelect+" where i.playlist_id = $1 and i.id = $2", listID, itemID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// TargetExists проверяет, что песня или альбом есть и не в корзине
+func (r *PlaylistRepository) TargetExists(ctx context.Context, t domain.Target) (bool, error) {
+	table := "song"
+	if t.Kind == domain.KindAlbum {
+		table = "album"
+	}
+
+	var exists bool
+
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`select exists (select 1 from `+table+` where id = $1 and deleted_at is null)`, t.ID,
+	).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check list target", "kind", t.Kind, "id", t.ID, "error", err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *PlaylistRepository) HasItem(ctx context.Context, listID int, t domain.Target) (bool, error) {
+	var exists bool
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select exists (
+			select 1 from playlist_item
+			where playlist_id = $1 and (song_id = $2 or album_id = $3)
+		)`,
+		listID, targetID(t, domain.KindSong), targetID(t, domain.KindAlbum),
+	).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check list item", "list_id", listID, "error", err)
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// AddItem вставляет позицию на item.Position, сдвигая следующие вниз;
+// вызывается под LockList
+func (r *PlaylistRepository) AddItem(ctx context.Context, item *domain.Item) error {
+	q := conn(ctx, r.db)
+
+	_, err := q.Exec(ctx, `
+		update playlist_item set position = position + 1
+		where playlist_id = $1 and position >= $2`,
+		item.ListID, item.Position)
+	if err != nil {
+		r.logger.Error("failed to shift list items", "list_id", item.ListID, "error", err)
+		return err
+	}
+
+	err = q.QueryRow(ctx, `
+		insert into playlist_item (playlist_id, song_id, album_id, position, note, added_by, created_at)
+		values ($1, nullif($2, 0), nullif($3, 0), $4, $5, $6, $7)
+		returning id`,
+		item.ListID,
+		targetID(item.Target, domain.KindSong),
+		targetID(item.Target, domain.KindAlbum),
+		item.Position,
+		item.Note,
+		item.AddedBy,
+		item.CreatedAt,
+	).Scan(&item.ID)
+	if err != nil {
+		r.logger.Error("failed to add list item", "list_id", item.ListID, "error", err)
+		return err
+	}
+
+	return r.applyItemCount(ctx, item.ListID, 1)
+}
+
+// MoveItem переносит позицию с места from на место to, сдвигая позиции между ними
+func (r *PlaylistRepository) MoveItem(ctx context.Context, listID, itemID, from, to int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		update playlist_item set position = case
+			when id = $2 then $4
+			when $4 < $3 then position + 1
+			else position - 1
+		end
+		where playlist_id = $1 and position between least($3::int, $4::int) and greatest($3::int, $4::int)`,
+		listID, itemID, from, to)
+	if err != nil {
+		r.logger.Error("failed to move list item", "list_id", listID, "item_id", itemID, "error", err)
+		return err
+	}
+
+	return r.touch(ctx, listID)
+}
+
+func (r *PlaylistRepository) UpdateItemNote(ctx context.Context, listID, itemID int, note string) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`update playlist_item set note = $3 where playlist_id = $1 and id = $2`, listID, itemID, note)
+	if err != nil {
+		r.logger.Error("failed to update list item note", "item_id", itemID, "error", err)
+		return err
+	}
+
+	return r.touch(ctx, listID)
+}
+
+// DeleteItem удаляет позицию и сдвигает следующие вверх
+func (r *PlaylistRepository) DeleteItem(ctx context.Context, listID, itemID, position int) error {
+	q := conn(ctx, r.db)
+
+	if _, err := q.Exec(ctx, `delete from playlist_item where id = $1`, itemID); err != nil {
+		r.logger.Error("failed to delete list item", "item_id", itemID, "error", err)
+		return err
+	}
+
+	_, err := q.Exec(ctx, `
+		update playlist_item set position = position - 1
+		where playlist_id = $1 and position > $2`,
+		listID, position)
+	if err != nil {
+		r.logger.Error("failed to shift list items", "list_id", listID, "error", err)
+		return err
+	}
+
+	return r.applyItemCount(ctx, listID, -1)
+}
+
+// Reorder расставляет позиции в порядке ids; позиции с объектами из корзины,
+// которых клиент не видит, уходят в конец в прежнем порядке
+func (r *PlaylistRepository) Reorder(ctx context.Context, listID int, ids []int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		with requested as (
+			select id, ord from unnest($2::int[]) with ordinality as o (id, ord)
+		), rest as (
+			select id, cardinality($2::int[]) + row_number() over (order by position) as ord
+			from playlist_item
+			where playlist_id = $1 and id <> all ($2::int[])
+		)
+		update playlist_item i set position = x.ord
+		from (select * from requested union all select * from rest) x
+		where i.id = x.id and i.playlist_id = $1`,
+		listID, ids)
+	if err != nil {
+		r.logger.Error("failed to reorder list", "list_id", listID, "error", err)
+		return err
+	}
+
+	return r.touch(ctx, listID)
+}
+
+// LikeList ставит отметку и возвращает новое число отметок; повтор ничего не меняет
+func (r *PlaylistRepository) LikeList(ctx context.Context, listID, userID int) (int, error) {
+	var count int
+
+	err := r.db.QueryRow(ctx, `
+		with added as (
+			insert into playlist_like (playlist_id, user_id) values ($1, $2)
+			on conflict do nothing
+			returning playlist_id
+		)
+		update playlist set like_count = like_count + (select count(*) from added)
+		where id = $1
+		returning like_count`,
+		listID, userID,
+	).Scan(&count)
+	if err != nil {
+		r.logger.Error("failed to like list", "list_id", listID, "user_id", userID, "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *PlaylistRepository) UnlikeList(ctx context.Context, listID, userID int) (int, error) {
+	var count int
+
+	err := r.db.QueryRow(ctx, `
+		with removed as (
+			delete from playlist_like where playlist_id = $1 and user_id = $2
+			returning playlist_id
+		)
+		update playlist set like_count = like_count - (select count(*) from removed)
+		where id = $1
+		returning like_count`,
+		listID, userID,
+	).Scan(&count)
+	if err != nil {
+		r.logger.Error("failed to unlike list", "list_id", listID, "user_id", userID, "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *PlaylistRepository) GetEditors(ctx context.Context, listID int) ([]users.User, error) {
+	rows, err := r.db.Query(ctx, `
+		select u.id, u.username, u.avatar_url
+		from playlist_editor e
+		join users u on u.id = e.user_id
+		where e.playlist_id = $1
+		order by e.added_at, u.id`, listID)
+	if err != nil {
+		r.logger.Error("failed to get list editors", "list_id", listID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	editors := make([]users.User, 0)
+
+	for rows.Next() {
+		var u users.User
+		if err = rows.Scan(&u.ID, &u.Username, &u.AvatarURL); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		editors = append(editors, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return editors, nil
+}
+
+// AddEditor добавляет соавтора; повторное добавление ничего не меняет
+func (r *PlaylistRepository) AddEditor(ctx context.Context, listID, userID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into playlist_editor (playlist_id, user_id) values ($1, $2)
+		on conflict do nothing`,
+		listID, userID)
+	if err != nil {
+		r.logger.Error("failed to add list editor", "list_id", listID, "user_id", userID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) RemoveEditor(ctx context.Context, listID, userID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`delete from playlist_editor where playlist_id = $1 and user_id = $2`, listID, userID)
+	if err != nil {
+		r.logger.Error("failed to remove list editor", "list_id", listID, "user_id", userID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) applyItemCount(ctx context.Context, listID, delta int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		update playlist set item_count = item_count + $2, updated_at = now()
+		where id = $1`, listID, delta)
+	if err != nil {
+		r.logger.Error("failed to update list item count", "list_id", listID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PlaylistRepository) touch(ctx context.Context, listID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `update playlist set updated_at = now() where id = $1`, listID)
+	return err
+}
+
+func (r *PlaylistRepository) listPlaylists(ctx context.Context, query string, args ...any) ([]domain.List, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to get lists", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := make([]domain.List, 0)
+
+	for rows.Next() {
+		l, err := scanPlaylist(rows)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		lists = append(lists, l)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+// targetID возвращает id объекта, если он вида kind, иначе 0
+func targetID(t domain.Target, kind domain.Kind) int {
+	if t.Kind == kind {
+		return t.ID
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/rating"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+// RatingRepository сверяет денормализованные счётчики песен, альбомов и исполнителей с рецензиями
+type RatingRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewRatingRepository(db *pgxpool.Pool, logger *logger.Logger) *RatingRepository {
+	return &RatingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// countersQueries таблица вида объекта и запросы его фактических счётчиков и пересчёта;
+// where получает условие на id строки каталога
+func countersQueries(kind domain.Kind) (table string, actual, recompute func(where string) string) {
+	switch kind {
+	case domain.KindAlbum:
+		return "album",
+			func(where string) string { return targetCountersActual("album", "album_id", "x."+where) },
+			func(where string) string { return targetCountersRecompute("album", "album_id", "x."+where) }
+	case domain.KindArtist:
+		return "artist",
+			func(where string) string { return targetCountersActual("artist", "artist_id", "x."+where) },
+			func(where string) string { return targetCountersRecompute("artist", "artist_id", "x."+where) }
+	default:
+		return "song",
+			func(where string) string { return songCountersActual("s." + where) },
+			func(where string) string { return songCountersRecompute("s." + where) }
+	}
+}
+
+// GetCountersBatch возвращает сохранённые и фактические счётчики
+// не более limit объектов вида kind с id больше afterID в порядке id
+func (r *RatingRepository) GetCountersBatch(
+	ctx context.Context,
+	kind domain.Kind,
+	afterID, limit int,
+) ([]domain.TargetCounters, error) {
+	table, actual, _ := countersQueries(kind)
+
+	query := `
+		select t.id,
+		t.like_count, t.dislike_count, t.rating, t.score_count, t.score_sum, t.score_histogram,
+		c.like_count, c.dislike_count, c.like_count - c.dislike_count, c.score_count, c.score_sum, c.score_histogram
+		from ` + table + ` t
+		join (` + actual("id in (select id from "+table+" where id > $1 order by id limit $2)") + `) c
+		on c.id = t.id
+		order by t.id`
+
+	rows, err := r.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		r.logger.Error("failed to get counters", "kind", kind, "after_id", afterID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	batch := make([]domain.TargetCounters, 0, limit)
+
+	for rows.Next() {
+		c := domain.TargetCounters{Kind: kind}
+		if err = rows.Scan(
+			&c.ID,
+			&c.Stored.Likes, &c.Stored.Dislikes, &c.Stored.Rating,
+			&c.Stored.ScoreCount, &c.Stored.ScoreSum, &c.Stored.ScoreHistogram,
+			&c.Actual.Likes, &c.Actual.Dislikes, &c.Actual.Rating,
+			&c.Actual.ScoreCount, &c.Actual.ScoreSum, &c.Actual.ScoreHistogram,
+		); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		batch = append(batch, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// RepairCounters пересчитывает счётчики объектов ids вида kind по рецензиям в одной транзакции,
+// у песен заодно и ранги. Строки сначала блокируются: пересчёт отдельным запросом
+// получает снимок после фиксации рецензий, которые держали эти строки
+func (r *RatingRepository) RepairCounters(ctx context.Context, kind domain.Kind, ids []int) (int64, error) {
+	table, _, recompute := countersQueries(kind)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `select id from `+table+` where id = any($1) order by id for update`, ids); err != nil {
+		r.logger.Error("failed to lock drifted counters", "kind", kind, "error", err)
+		return 0, err
+	}
+
+	res, err := tx.Exec(ctx, recompute("id = any($1)"), ids)
+	if err != nil {
+		r.logger.Error("failed to repair counters", "kind", kind, "error", err)
+		return 0, err
+	}
+
+	if kind == domain.KindSong {
+		if _, err = tx.Exec(ctx, songRankRefresh+" where s.id = any($1)", ids); err != nil {
+			r.logger.Error("failed to refresh repaired song ranks", "error", err)
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/release"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type ReleaseRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewReleaseRepository(db *pgxpool.Pool, logger *logger.Logger) *ReleaseRepository {
+	return &ReleaseRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// releaseSelect порядок колонок должен совпадать со scanRelease
+const releaseSelect = `
+	select e.id, e.created_at,
+	case when e.album_id is null then 'song' else 'album' end,
+	coalesce(e.song_id, e.album_id),
+	coalesce(s.title, a.title), coalesce(s.slug, a.slug, ''), coalesce(s.image_url, a.image_url, ''),
+	coalesce(s.deleted_at, a.deleted_at, ar.deleted_at) is not null,
+	ar.id, ar.nickname, coalesce(ar.slug, '')
+	from artist_release e
+	join artist ar on ar.id = e.artist_id
+	left join song s on s.id = e.song_id
+	left join album a on a.id = e.album_id`
+
+// releaseLive отсекает релизы, убранные в корзину вместе с исполнителем или без него
+const releaseLive = "s.deleted_at is null and a.deleted_at is null and ar.deleted_at is null"
+
+func scanRelease(row pgx.Row) (domain.Release, error) {
+	var rel domain.Release
+
+	err := row.Scan(
+		&rel.ID,
+		&rel.CreatedAt,
+		&rel.Kind,
+		&rel.TargetID,
+		&rel.Title,
+		&rel.Slug,
+		&rel.ImageURL,
+		&rel.Deleted,
+		&rel.Artist.ID,
+		&rel.Artist.Nickname,
+		&rel.Artist.Slug,
+	)
+
+	return rel, err
+}
+
+// recordAlbumRelease ставит новый альбом в очередь уведомлений в транзакции его создания
+func recordAlbumRelease(ctx context.Context, tx pgx.Tx, artistID, albumID int) error {
+	_, err := tx.Exec(ctx, `insert into artist_release (artist_id, album_id) values ($1, $2)`, artistID, albumID)
+	return err
+}
+
+// recordSongRelease ставит новую песню в очередь уведомлений, если она не входит
+// в уже вышедший альбом: о нём подписчики узнали при создании альбома
+func recordSongRelease(ctx context.Context, tx pgx.Tx, artistID, songID, albumID int) error {
+	_, err := tx.Exec(ctx, `
+		insert into artist_release (artist_id, song_id)
+		select $1, $2
+		where not exists (select 1 from artist_release where album_id = $3)`,
+		artistID, songID, albumID)
+	return err
+}
+
+// FollowArtist подписывает пользователя на исполнителя и возвращает false, если исполнителя нет.
+// Повторная подписка счётчик не меняет.
+func (r *ReleaseRepository) FollowArtist(ctx context.Context, userID, artistID int) (bool, error) {
+	var found bool
+
+	err := r.db.QueryRow(ctx, `
+		with target as (
+			select id from artist where id = $2 and deleted_at is null
+		), added as (
+			insert into artist_follow (user_id, artist_id)
+			select $1, id from target
+			on conflict do nothing
+			returning artist_id
+		), counted as (
+			update artist set follower_count = follower_count + 1
+			where id in (select artist_id from added)
+		)
+		select exists (select 1 from target)`,
+		userID, artistID,
+	).Scan(&found)
+	if err != nil {
+		r.logger.Error("failed to follow artist", "user_id", userID, "artist_id", artistID, "error", err)
+		return false, err
+	}
+
+	return found, nil
+}
+
+// UnfollowArtist снимает подписку; отписка от исполнителя без подписки ничего не меняет
+func (r *ReleaseRepository) UnfollowArtist(ctx context.Context, userID, artistID int) error {
+	_, err := r.db.Exec(ctx, `
+		with removed as (
+			delete from artist_follow where user_id = $1 and artist_id = $2
+			returning artist_id
+		)
+		update artist set follower_count = follower_count - 1
+		where id in (select artist_id from removed)`,
+		userID, artistID)
+	if err != nil {
+		r.logger.Error("failed to unfollow artist", "user_id", userID, "artist_id", artistID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetArtistFollow возвращает, подписан ли пользователь на исполнителя, и число подписчиков
+func (r *ReleaseRepository) GetArtistFollow(ctx context.Context, userID, artistID int) (bool, int, error) {
+	var (
+		following bool
+		count     int
+	)
+
+	err := r.db.QueryRow(ctx, `
+		select exists (select 1 from artist_follow where user_id = $1 and artist_id = $2), follower_count
+		from artist where id = $2 and deleted_at is null`,
+		userID, artistID,
+	).Scan(&following, &count)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return following, count, nil
+}
+
+// GetFollowedReleases возвращает релизы исполнителей из подписок пользователя, новые первыми
+func (r *ReleaseRepository) GetFollowedReleases(
+	ctx context.Context,
+	userID, limit, offset int,
+) ([]domain.Release, error) {
+	query := releaseSelect + `
+		join artist_follow f on f.artist_id = e.artist_id and f.user_id = $1
+		where ` + releaseLive + `
+		order by e.created_at desc, e.id desc
+		limit $2 offset $3`
+
+	return r.listReleases(ctx, query, userID, limit, offset)
+}
+
+// GetFollowedReleasesBetween релизы из подписок, вышедшие в (since, until], для сводки
+func (r *ReleaseRepository) GetFollowedReleasesBetween(
+	ctx context.Context,
+	userID int,
+	since, until time.Time,
+	limit int,
+) ([]domain.Release, error) {
+	query := releaseSelect + `
+		join artist_follow f on f.artist_id = e.artist_id and f.user_id = $1
+		where ` + releaseLive + ` and e.created_at > $2 and e.created_at <= $3
+		order by e.created_at, e.id
+		limit $4`
+
+	return r.listReleases(ctx, query, userID, since, until, limit)
+}
+
+// ClaimPendingReleases блокирует до конца транзакции UnitOfWork релизы, по которым
+// не разосланы уведомления; занятые другим экземпляром пропускаются
+func (r *ReleaseRepository) ClaimPendingReleases(ctx context.Context, limit int) ([]domain.Release, error) {
+	query := releaseSelect + `
+		where e.notified_at is null
+		order by e.id
+		limit $1
+		for update of e skip locked`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("failed to claim releases", "error", err)
+		return nil, err
+	}
+
+	return r.collect(rows)
+}
+
+// MarkNotified закрывает релизы в очереди уведомлений
+func (r *ReleaseRepository) MarkNotified(ctx context.Context, ids []int64) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `update artist_release set notified_at = now() where id = any($1)`, ids)
+	if err != nil {
+		r.logger.Error("failed to mark releases notified", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReleaseRepository) GetDigest(ctx context.Context, userID int) (domain.Digest, error) {
+	var d domain.Digest
+
+	err := r.db.QueryRow(ctx, `select release_digest from users where id = $1`, userID).Scan(&d)
+	if err != nil {
+		r.logger.Error("failed to get digest", "user_id", userID, "error", err)
+		return "", err
+	}
+
+	return d, nil
+}
+
+func (r *ReleaseRepository) SetDigest(ctx context.Context, userID int, d domain.Digest) error {
+	res, err := r.db.Exec(ctx, `update users set release_digest = $2 where id = $1`, userID, d)
+	if err != nil {
+		r.logger.Error("failed to set digest", "user_id", userID, "error", err)
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetDueSubscribers возвращает подписчиков сводки d, прошлое письмо которых
+// отправлено не позже dueBefore, по возрастанию id после afterID
+func (r *ReleaseRepository) GetDueSubscribers(
+	ctx context.Context,
+	d domain.Digest,
+	dueBefore time.Time,
+	afterID, limit int,
+) ([]domain.Subscriber, error) {
+	rows, err := r.db.Query(ctx, `
+		select id, email, username, release_digest, digest_sent_at
+		from users
+		where release_digest = $1 and (digest_sent_at is null or digest_sent_at <= $2) and id > $3
+		order by id
+		limit $4`,
+		d, dueBefore, afterID, limit)
+	if err != nil {
+		r.logger.Error("failed to get digest subscribers", "digest", d, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscribers := make([]domain.Subscriber, 0)
+
+	for rows.Next() {
+		var s domain.Subscriber
+		if err = rows.Scan(&s.UserID, &s.Email, &s.Username, &s.Digest, &s.SentAt); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		subscribers = append(subscribers, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscribers, nil
+}
+
+func (r *ReleaseRepository) SetDigestSent(ctx context.Context, userID int, at time.Time) error {
+	_, err := r.db.Exec(ctx, `update users set digest_sent_at = $2 where id = $1`, userID, at)
+	if err != nil {
+		r.logger.Error("failed to set digest sent", "user_id", userID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReleaseRepository) listReleases(ctx context.Context, query string, args ...any) ([]domain.Release, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to get releases", "error", err)
+		return nil, err
+	}
+
+	return r.collect(rows)
+}
+
+func (r *ReleaseRepository) collect(rows pgx.Rows) ([]domain.Release, error) {
+	defer rows.Close()
+
+	releases := make([]domain.Release, 0)
+
+	for rows.Next() {
+		rel, err := scanRelease(rows)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		releases = append(releases, rel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
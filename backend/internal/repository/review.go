@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/maYkiss56/tunes/internal/domain/mention"
 	domain "github.com/maYkiss56/tunes/internal/domain/review"
 	"github.com/maYkiss56/tunes/internal/domain/review/dto"
 	"github.com/maYkiss56/tunes/internal/domain/song"
@@ -33,24 +34,156 @@ func NewReviewRepository(db *pgxpool.Pool, logger *logger.Logger, userRepo *User
 	}
 }
 
+// reviewSelect выбирает рецензию вместе с автором и объектом рецензии;
+// порядок колонок должен совпадать со scanReview
+var reviewSelect = `
+	select r.id, r.user_id, r.song_id, r.album_id, r.artist_id,
+	r.body, r.is_like, r.is_valid, r.score,
+	r.status, r.publish_at, r.published_at,
+	r.created_at, r.updated_at, r.edited_at,
+	(select count(*) from review_comment c
+		where c.review_id = r.id and c.status = 'approved' and c.deleted_at is null),
+	r.helpful_count, r.unhelpful_count,
+	(select coalesce(jsonb_object_agg(x.emoji, x.n), '{}') from (
+		select emoji, count(*) as n from review_reaction
+		where review_id = r.id group by emoji) x),` +
+	mentionsColumn("m.review_id = r.id") + `,
+	u.id, u.email, u.username, u.avatar_url,
+	coalesce(s.title, al.title, ar.nickname), coalesce(s.image_url, al.image_url, ''),
+	coalesce(s.full_title, ''), s.release_date
+	from review r
+	join users u on r.user_id = u.id
+	left join song s on r.song_id = s.id
+	left join album al on r.album_id = al.id
+	left join artist ar on r.artist_id = ar.id`
+
+// reviewVisible оставляет опубликованные рецензии на объекты вне корзины
+const reviewVisible = `
+	where r.status = 'published'
+	and coalesce(s.deleted_at, al.deleted_at, ar.deleted_at) is null`
+
+// targetColumn колонка review, ссылающаяся на объект рецензии
+func targetColumn(t domain.TargetType) string {
+	switch t {
+	case domain.TargetAlbum:
+		return "album_id"
+	case domain.TargetArtist:
+		return "artist_id"
+	default:
+		return "song_id"
+	}
+}
+
+// scanTarget собирает объект рецензии из взаимоисключающих колонок
+func scanTarget(songID, albumID, artistID *int) domain.Target {
+	switch {
+	case songID != nil:
+		return domain.SongTarget(*songID)
+	case albumID != nil:
+		return domain.AlbumTarget(*albumID)
+	case artistID != nil:
+		return domain.ArtistTarget(*artistID)
+	default:
+		return domain.Target{}
+	}
+}
+
+func scanReview(row pgx.Row) (dto.Response, error) {
+	var (
+		review                     domain.Review
+		user                       users.User
+		songID, albumID, artistID  *int
+		title, imageURL, fullTitle string
+		releaseDate                *time.Time
+	)
+
+	err := row.Scan(
+		&review.ID,
+		&review.UserID,
+		&songID,
+		&albumID,
+		&artistID,
+		&review.Body,
+		&review.IsLike,
+		&review.IsValid,
+		&review.Score,
+		&review.Status,
+		&review.PublishAt,
+		&review.PublishedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&review.EditedAt,
+		&review.CommentCount,
+		&review.HelpfulCount,
+		&review.UnhelpfulCount,
+		&review.Reactions,
+		&review.Mentions,
+		&user.ID,
+		&user.Email,
+		&user.Username,
+		&user.AvatarURL,
+		&title,
+		&imageURL,
+		&fullTitle,
+		&releaseDate,
+	)
+	if err != nil {
+		return dto.Response{}, err
+	}
+
+	review.Target = scanTarget(songID, albumID, artistID)
+	target := dto.TargetResponse{
+		Type:     review.Target.Type,
+		ID:       review.Target.ID,
+		Title:    title,
+		ImageURL: imageURL,
+	}
+
+	var s *song.Song
+	if review.Target.Type == domain.TargetSong {
+		s = &song.Song{
+			ID:        review.Target.ID,
+			Title:     title,
+			FullTitle: fullTitle,
+			ImageURL:  imageURL,
+		}
+		if releaseDate != nil {
+			s.ReleaseDate = *releaseDate
+		}
+	}
+
+	return dto.ToResponse(review, user, target, s), nil
+}
+
 func (r *ReviewRepository) CreateReview(ctx context.Context, review *domain.Review) error {
 	query := `
 	insert into review
-	(user_id, song_id, body, is_like, is_valid, created_at, updated_at)
-	values ($1, $2, $3, $4, $5, $6, $7) returning id`
+	(user_id, ` + targetColumn(review.Target.Type) + `, body, is_like, is_valid, score,
+	status, publish_at, published_at, created_at, updated_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	on conflict do nothing
+	returning id`
 
-	err := r.db.QueryRow(
+	err := conn(ctx, r.db).QueryRow(
 		ctx,
 		query,
 		review.UserID,
-		review.SongID,
+		review.Target.ID,
 		review.Body,
 		review.IsLike,
 		review.IsValid,
+		review.Score,
+		review.Status,
+		review.PublishAt,
+		review.PublishedAt,
 		time.Now(),
 		time.Now(),
 	).Scan(&review.ID)
 	if err != nil {
+		// конфликт не прерывает транзакцию, поэтому существующую рецензию можно прочитать в ней же
+		if errors.Is(err, pgx.ErrNoRows) {
+			return r.existingReview(ctx, review.UserID, review.Target)
+		}
 		r.logger.Error("failed to create review", "error", err)
 		return err
 	}
@@ -58,78 +191,183 @@ func (r *ReviewRepository) CreateReview(ctx context.Context, review *domain.Revi
 	return nil
 }
 
-func (r *ReviewRepository) GetAllReviews(ctx context.Context) ([]dto.Response, error) {
+// existingReview возвращает *domain.ExistsError с рецензией пользователя на объект
+func (r *ReviewRepository) existingReview(ctx context.Context, userID int, target domain.Target) error {
+	var id int
+
+	query := `select id from review where user_id=$1 and ` + targetColumn(target.Type) + `=$2`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID, target.ID).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return &domain.ExistsError{ID: id}
+}
+
+// GetReviewForUpdate блокирует рецензию до конца транзакции UnitOfWork
+func (r *ReviewRepository) GetReviewForUpdate(ctx context.Context, id int) (*domain.Review, error) {
 	query := `
-		select r.id, r.user_id, r.song_id,
-		r.body, r.is_like, r.is_valid,
-		r.created_at, r.updated_at,
-		u.id, u.email, u.username, u.avatar_url,
-		s.id, s.title, s.full_title, s.image_url, s.release_date
-		from review r
-		join users u on r.user_id = u.id
-		join song s on r.song_id = s.id`
-
-	rows, err := r.db.Query(ctx, query)
+		select id, user_id, song_id, album_id, artist_id,
+		body, is_like, is_valid, score, status, publish_at, published_at,
+		created_at, updated_at, edited_at
+		from review where id = $1
+		for update`
+
+	var (
+		review                    domain.Review
+		songID, albumID, artistID *int
+	)
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&review.ID,
+		&review.UserID,
+		&songID,
+		&albumID,
+		&artistID,
+		&review.Body,
+		&review.IsLike,
+		&review.IsValid,
+		&review.Score,
+		&review.Status,
+		&review.PublishAt,
+		&review.PublishedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&review.EditedAt,
+	)
 	if err != nil {
-		r.logger.Error("failed to get all reviews", "error", err)
 		return nil, err
 	}
-	defer rows.Close()
+	review.Target = scanTarget(songID, albumID, artistID)
 
-	reviews := make([]dto.Response, 0)
+	return &review, nil
+}
 
-	for rows.Next() {
-		var (
-			review domain.Review
-			user   users.User
-			song   song.Song
-		)
-		err = rows.Scan(
-			&review.ID,
-			&review.UserID,
-			&review.SongID,
-			&review.Body,
-			&review.IsLike,
-			&review.IsValid,
-			&review.CreatedAt,
-			&review.UpdatedAt,
-			&user.ID,
-			&user.Email,
-			&user.Username,
-			&user.AvatarURL,
-			&song.ID,
-			&song.Title,
-			&song.FullTitle,
-			&song.ImageURL,
-			&song.ReleaseDate,
-		)
-		if err != nil {
-			r.logger.Error("failed to scan rows", "error", err)
-			return nil, err
-		}
+// reviewOrder порядок выдачи списков рецензий
+func reviewOrder(sort string) string {
+	switch sort {
+	case domain.SortHelpful:
+		return " order by wilson_lower_bound(r.helpful_count, r.unhelpful_count) desc, r.helpful_count desc, r.id desc"
+	case domain.SortRecent:
+		return " order by r.created_at desc, r.id desc"
+	default:
+		return ""
+	}
+}
 
-		reviews = append(reviews, dto.ToResponse(review, user, song))
+func (r *ReviewRepository) GetAllReviews(ctx context.Context, sort string) ([]dto.Response, error) {
+	return r.listReviews(ctx, reviewSelect+reviewVisible+reviewOrder(sort))
+}
+
+// GetReviewsByTarget возвращает рецензии на песню, альбом или исполнителя
+func (r *ReviewRepository) GetReviewsByTarget(ctx context.Context, target domain.Target, sort string) ([]dto.Response, error) {
+	if sort == "" {
+		sort = domain.SortRecent
 	}
 
-	return reviews, nil
+	query := reviewSelect + reviewVisible + " and r." + targetColumn(target.Type) + " = $1" + reviewOrder(sort)
+
+	return r.listReviews(ctx, query, target.ID)
+}
+
+// GetReviewsByIDs возвращает видимые рецензии из ids в произвольном порядке
+func (r *ReviewRepository) GetReviewsByIDs(ctx context.Context, ids []int) ([]dto.Response, error) {
+	return r.listReviews(ctx, reviewSelect+reviewVisible+" and r.id = any($1)", ids)
+}
+
+// GetReviewsMentioning возвращает опубликованные рецензии, упоминающие сущность, свежие первыми
+func (r *ReviewRepository) GetReviewsMentioning(ctx context.Context, kind mention.Kind, id int) ([]dto.Response, error) {
+	query := reviewSelect + reviewVisible + `
+		and exists (select 1 from mention m
+			where m.review_id = r.id and m.target_type = $1 and m.target_id = $2)
+		order by r.published_at desc, r.id desc`
+
+	return r.listReviews(ctx, query, kind, id)
 }
 
 func (r *ReviewRepository) GetAllReviewsByUserID(ctx context.Context, id int) ([]dto.Response, error) {
+	query := reviewSelect + reviewVisible + `
+		and r.user_id = $1
+		order by r.created_at desc`
+
+	return r.listReviews(ctx, query, id)
+}
+
+// GetDrafts возвращает черновики и запланированные рецензии автора, свежие первыми
+func (r *ReviewRepository) GetDrafts(ctx context.Context, userID int) ([]dto.Response, error) {
+	query := reviewSelect + `
+		where r.user_id = $1 and r.status <> 'published'
+		order by r.updated_at desc, r.id desc`
+
+	return r.listReviews(ctx, query, userID)
+}
+
+// SetStatus меняет стадию публикации; вызывается под блокировкой GetReviewForUpdate
+func (r *ReviewRepository) SetStatus(ctx context.Context, id int, status domain.Status, publishAt *time.Time) error {
 	query := `
-        SELECT r.id, r.user_id, r.song_id,
-        r.body, r.is_like, r.is_valid,
-        r.created_at, r.updated_at,
-        u.id, u.email, u.username, u.avatar_url,
-        s.id, s.title, s.full_title, s.image_url, s.release_date
-        FROM review r
-        JOIN users u ON r.user_id = u.id
-        JOIN song s ON r.song_id = s.id
-        WHERE r.user_id = $1
-        ORDER BY r.created_at DESC`
-
-	rows, err := r.db.Query(ctx, query, id)
+		update review set
+			status = $2,
+			publish_at = $3,
+			published_at = case when $2 = 'published' then now() end,
+			updated_at = now()
+		where id = $1`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id, status, publishAt)
+	if err != nil {
+		r.logger.Error("failed to set review status", "id", id, "status", status, "error", err)
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetValid меняет решение модерации; счётчики объекта пересчитывает вызывающий
+func (r *ReviewRepository) SetValid(ctx context.Context, id int, valid bool) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `update review set is_valid = $2, updated_at = now() where id = $1`, id, valid)
 	if err != nil {
-		r.logger.Error("failed to get all reviews for user", "error", err)
+		r.logger.Error("failed to set review validity", "id", id, "error", err)
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetDuePublications возвращает до limit запланированных рецензий, время публикации которых наступило
+func (r *ReviewRepository) GetDuePublications(ctx context.Context, limit int) ([]int, error) {
+	rows, err := r.db.Query(ctx, `
+		select id from review
+		where status = 'scheduled' and publish_at <= now()
+		order by publish_at, id
+		limit $1`, limit)
+	if err != nil {
+		r.logger.Error("failed to get due publications", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *ReviewRepository) listReviews(ctx context.Context, query string, args ...any) ([]dto.Response, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to get reviews", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -137,65 +375,23 @@ func (r *ReviewRepository) GetAllReviewsByUserID(ctx context.Context, id int) ([
 	reviews := make([]dto.Response, 0)
 
 	for rows.Next() {
-		var (
-			review domain.Review
-			user   users.User
-			song   song.Song
-		)
-		err = rows.Scan(
-			&review.ID,
-			&review.UserID,
-			&review.SongID,
-			&review.Body,
-			&review.IsLike,
-			&review.IsValid,
-			&review.CreatedAt,
-			&review.UpdatedAt,
-			&user.ID,
-			&user.Email,
-			&user.Username,
-			&user.AvatarURL,
-			&song.ID,
-			&song.Title,
-			&song.FullTitle,
-			&song.ImageURL,
-			&song.ReleaseDate,
-		)
+		review, err := scanReview(rows)
 		if err != nil {
-			r.logger.Error("failed to scan review row", "error", err)
+			r.logger.Error("failed to scan rows", "error", err)
 			return nil, err
 		}
 
-		reviews = append(reviews, dto.ToResponse(review, user, song))
+		reviews = append(reviews, review)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return reviews, nil
 }
 
 func (r *ReviewRepository) GetReviewByID(ctx context.Context, id int) (*dto.Response, error) {
-	query := `
-		select r.id, r.user_id, r.song_id,
-		r.body, r.is_like, r.is_valid,
-		r.created_at, r.updated_at,
-		u.id, u.email, u.username, u.avatar_url,
-		s.id, s.title, s.full_title, s.image_url, s.release_date
-		from review r
-		join users u on r.user_id = u.id
-		join song s on r.song_id = s.id
-		where r.id = $1`
-
-	var (
-		review domain.Review
-		user   users.User
-		song   song.Song
-	)
-
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&review.ID, &review.UserID, &review.SongID,
-			&review.Body, &review.IsLike, &review.IsValid,
-			&review.CreatedAt, &review.UpdatedAt,
-			&user.ID, &user.Email, &user.Username, &user.AvatarURL,
-			&song.ID, &song.Title, &song.FullTitle, &song.ImageURL, &song.ReleaseDate)
+	res, err := scanReview(conn(ctx, r.db).QueryRow(ctx, reviewSelect+" where r.id = $1", id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			r.logger.Error("review not found", "id", id)
@@ -205,8 +401,6 @@ func (r *ReviewRepository) GetReviewByID(ctx context.Context, id int) (*dto.Resp
 		return nil, err
 	}
 
-	res := dto.ToResponse(review, user, song)
-
 	return &res, nil
 }
 
@@ -225,6 +419,11 @@ func (r *ReviewRepository) UpdateReview(ctx context.Context, id int, update dto.
 		args = append(args, *update.IsLike)
 		argPos++
 	}
+	if update.Score != nil {
+		fields = append(fields, fmt.Sprintf("score=nullif($%d, 0)", argPos))
+		args = append(args, *update.Score)
+		argPos++
+	}
 
 	if len(fields) == 0 {
 		return nil
@@ -239,7 +438,7 @@ func (r *ReviewRepository) UpdateReview(ctx context.Context, id int, update dto.
 
 	query := fmt.Sprintf("update review set %s %s", strings.Join(fields, ", "), whereClause)
 
-	res, err := r.db.Exec(
+	res, err := conn(ctx, r.db).Exec(
 		ctx,
 		query,
 		args...,
@@ -260,9 +459,201 @@ func (r *ReviewRepository) UpdateReview(ctx context.Context, id int, update dto.
 func (r *ReviewRepository) DeleteReview(ctx context.Context, id int) error {
 	query := `delete from review where id=$1`
 
-	if _, err := r.db.Exec(ctx, query, id); err != nil {
+	if _, err := conn(ctx, r.db).Exec(ctx, query, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LockReview блокирует опубликованную рецензию до конца транзакции UnitOfWork,
+// сериализуя голоса за неё, и возвращает её автора
+func (r *ReviewRepository) LockReview(ctx context.Context, id int) (int, error) {
+	var userID int
+
+	query := `select user_id from review where id=$1 and status='published' for update`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// GetVote возвращает голос пользователя, nil если он не голосовал
+func (r *ReviewRepository) GetVote(ctx context.Context, reviewID, userID int) (*bool, error) {
+	var helpful bool
+
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`select helpful from review_vote where review_id=$1 and user_id=$2`, reviewID, userID,
+	).Scan(&helpful)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &helpful, nil
+}
+
+// SetVote сохраняет голос пользователя, nil снимает его
+func (r *ReviewRepository) SetVote(ctx context.Context, reviewID, userID int, helpful *bool) error {
+	var err error
+
+	if helpful == nil {
+		_, err = conn(ctx, r.db).Exec(ctx,
+			`delete from review_vote where review_id=$1 and user_id=$2`, reviewID, userID)
+	} else {
+		_, err = conn(ctx, r.db).Exec(ctx, `
+			insert into review_vote (review_id, user_id, helpful)
+			values ($1, $2, $3)
+			on conflict (review_id, user_id) do update set helpful = excluded.helpful, updated_at = now()`,
+			reviewID, userID, *helpful)
+	}
+	if err != nil {
+		r.logger.Error("failed to save review vote", "review_id", reviewID, "user_id", userID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReviewRepository) ApplyVoteDelta(ctx context.Context, reviewID, helpful, unhelpful int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		update review set
+			helpful_count = helpful_count + $2,
+			unhelpful_count = unhelpful_count + $3
+		where id = $1`,
+		reviewID, helpful, unhelpful)
+	if err != nil {
+		r.logger.Error("failed to update review votes", "review_id", reviewID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetReaction сохраняет реакцию пользователя, пустая строка снимает её
+func (r *ReviewRepository) SetReaction(ctx context.Context, reviewID, userID int, emoji string) error {
+	var err error
+
+	if emoji == "" {
+		_, err = conn(ctx, r.db).Exec(ctx,
+			`delete from review_reaction where review_id=$1 and user_id=$2`, reviewID, userID)
+	} else {
+		_, err = conn(ctx, r.db).Exec(ctx, `
+			insert into review_reaction (review_id, user_id, emoji)
+			values ($1, $2, $3)
+			on conflict (review_id, user_id) do update set emoji = excluded.emoji, created_at = now()`,
+			reviewID, userID, emoji)
+	}
+	if err != nil {
+		r.logger.Error("failed to save review reaction", "review_id", reviewID, "user_id", userID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// AddRevision сохраняет заменяемую версию рецензии и помечает рецензию
+// отредактированной; вызывается под блокировкой GetReviewForUpdate
+func (r *ReviewRepository) AddRevision(ctx context.Context, before *domain.Review, editedBy int) error {
+	query := `
+		with rev as (
+			insert into review_revision
+			(review_id, revision, body, is_like, score, edited_by, created_at)
+			select $1, coalesce(max(revision), 0) + 1, $2, $3, $4, nullif($5, 0), $6
+			from review_revision where review_id = $1
+		)
+		update review set edited_at = now() where id = $1`
+
+	_, err := conn(ctx, r.db).Exec(
+		ctx,
+		query,
+		before.ID,
+		before.Body,
+		before.IsLike,
+		before.Score,
+		editedBy,
+		before.CurrentRevision(0).CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("failed to save review revision", "review_id", before.ID, "error", err)
 		return err
 	}
 
 	return nil
 }
+
+// GetHistory возвращает версии рецензии по возрастанию номера,
+// последней идёт текущая; pgx.ErrNoRows если рецензии нет
+func (r *ReviewRepository) GetHistory(ctx context.Context, reviewID int) ([]domain.Revision, error) {
+	query := `
+		select review_id, revision, body, is_like, score, edited_by, created_at, replaced_at
+		from review_revision where review_id = $1
+		union all
+		select r.id, (select count(*) from review_revision v where v.review_id = r.id)::int + 1,
+			r.body, r.is_like, r.score, null, coalesce(r.edited_at, r.created_at), null
+		from review r where r.id = $1
+		order by revision`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, reviewID)
+	if err != nil {
+		r.logger.Error("failed to get review history", "review_id", reviewID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]domain.Revision, 0)
+
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		revisions = append(revisions, rev)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(revisions) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return revisions, nil
+}
+
+func (r *ReviewRepository) GetRevision(ctx context.Context, reviewID, revision int) (*domain.Revision, error) {
+	query := `
+		select review_id, revision, body, is_like, score, edited_by, created_at, replaced_at
+		from review_revision where review_id = $1 and revision = $2`
+
+	rev, err := scanRevision(conn(ctx, r.db).QueryRow(ctx, query, reviewID, revision))
+	if err != nil {
+		return nil, err
+	}
+
+	return &rev, nil
+}
+
+func scanRevision(row pgx.Row) (domain.Revision, error) {
+	var rev domain.Revision
+
+	err := row.Scan(
+		&rev.ReviewID,
+		&rev.Revision,
+		&rev.Body,
+		&rev.IsLike,
+		&rev.Score,
+		&rev.EditedBy,
+		&rev.CreatedAt,
+		&rev.ReplacedAt,
+	)
+
+	return rev, err
+}
@@ -0,0 +1,468 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/scrobble"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type ScrobbleRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewScrobbleRepository(db *pgxpool.Pool, logger *logger.Logger) *ScrobbleRepository {
+	return &ScrobbleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetUserByLogin ищет пользователя по почте или имени: скробблеры
+// спрашивают username, а на сайте входят по почте
+func (r *ScrobbleRepository) GetUserByLogin(ctx context.Context, login string) (*users.User, error) {
+	var u users.User
+
+	err := r.db.QueryRow(ctx, `
+		select id, email, username, password_hash, role_id
+		from users
+		where email = $1 or username = $1
+		order by email = $1 desc
+		limit 1`, login,
+	).Scan(&u.ID, &u.Email, &u.Username, &u.PasswordHash, &u.RoleID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Error("failed to get user by login", "error", err)
+		}
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (r *ScrobbleRepository) CreateSession(ctx context.Context, s *domain.Session) error {
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		insert into scrobble_session (key, user_id, client, created_at)
+		values ($1, $2, $3, $4)
+		returning id`,
+		s.Key, s.UserID, s.Client, s.CreatedAt,
+	).Scan(&s.ID)
+	if err != nil {
+		r.logger.Error("failed to create scrobble session", "user_id", s.UserID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// UseSession возвращает сессию по ключу и отмечает время её использования
+func (r *ScrobbleRepository) UseSession(ctx context.Context, key string) (*domain.Session, error) {
+	var s domain.Session
+
+	err := r.db.QueryRow(ctx, `
+		update scrobble_session ss set last_used_at = now()
+		from users u
+		where ss.key = $1 and u.id = ss.user_id
+		returning ss.id, ss.key, ss.user_id, u.username, ss.client, ss.created_at, ss.last_used_at`, key,
+	).Scan(&s.ID, &s.Key, &s.UserID, &s.Username, &s.Client, &s.CreatedAt, &s.LastUsedAt)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Error("failed to get scrobble session", "error", err)
+		}
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (r *ScrobbleRepository) GetSessions(ctx context.Context, userID int) ([]domain.Session, error) {
+	rows, err := r.db.Query(ctx, `
+		select id, key, user_id, client, created_at, last_used_at
+		from scrobble_session
+		where user_id = $1
+		order by created_at desc, id desc`, userID)
+	if err != nil {
+		r.logger.Error("failed to get scrobble sessions", "user_id", userID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]domain.Session, 0)
+
+	for rows.Next() {
+		var s domain.Session
+		if err = rows.Scan(&s.ID, &s.Key, &s.UserID, &s.Client, &s.CreatedAt, &s.LastUsedAt); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		sessions = append(sessions, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *ScrobbleRepository) DeleteSession(ctx context.Context, userID, id int) (bool, error) {
+	res, err := r.db.Exec(ctx, `delete from scrobble_session where id = $1 and user_id = $2`, id, userID)
+	if err != nil {
+		r.logger.Error("failed to delete scrobble session", "id", id, "error", err)
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// CreateToken сохраняет новый токен и заодно удаляет просроченные
+func (r *ScrobbleRepository) CreateToken(ctx context.Context, t domain.Token) error {
+	q := conn(ctx, r.db)
+
+	if _, err := q.Exec(ctx, `delete from scrobble_token where created_at < $1`, t.CreatedAt.Add(-domain.TokenTTL)); err != nil {
+		r.logger.Error("failed to purge scrobble tokens", "error", err)
+		return err
+	}
+
+	_, err := q.Exec(ctx, `
+		insert into scrobble_token (token, client, created_at) values ($1, $2, $3)`,
+		t.Token, t.Client, t.CreatedAt)
+	if err != nil {
+		r.logger.Error("failed to create scrobble token", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// LockToken возвращает токен, блокируя его до конца транзакции UnitOfWork
+func (r *ScrobbleRepository) LockToken(ctx context.Context, token string) (*domain.Token, error) {
+	var t domain.Token
+
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		select t.token, t.client, t.user_id, coalesce(u.username, ''), t.created_at
+		from scrobble_token t
+		left join users u on u.id = t.user_id
+		where t.token = $1
+		for update of t`, token,
+	).Scan(&t.Token, &t.Client, &t.UserID, &t.Username, &t.CreatedAt)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Error("failed to get scrobble token", "error", err)
+		}
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *ScrobbleRepository) ApproveToken(ctx context.Context, token string, userID int) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `update scrobble_token set user_id = $2 where token = $1`, token, userID)
+	if err != nil {
+		r.logger.Error("failed to approve scrobble token", "user_id", userID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ScrobbleRepository) DeleteToken(ctx context.Context, token string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `delete from scrobble_token where token = $1`, token)
+	if err != nil {
+		r.logger.Error("failed to delete scrobble token", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// MatchMBID ищет песню каталога по MusicBrainz id записи; nil, если не нашлась
+func (r *ScrobbleRepository) MatchMBID(ctx context.Context, mbid string) (songID, artistID *int, err error) {
+	var s, a int
+
+	err = r.db.QueryRow(ctx, `
+		select id, artist_id from song
+		where mbid = $1::uuid and deleted_at is null`, mbid,
+	).Scan(&s, &a)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil
+		}
+		r.logger.Error("failed to match song by mbid", "mbid", mbid, "error", err)
+		return nil, nil, err
+	}
+
+	return &s, &a, nil
+}
+
+// MatchArtist ищет исполнителя по имени без учёта регистра; nil, если не нашёлся
+func (r *ScrobbleRepository) MatchArtist(ctx context.Context, name string) (*int, error) {
+	var id int
+
+	err := r.db.QueryRow(ctx, `
+		select id from artist
+		where lower(nickname) = lower($1) and deleted_at is null
+		order by id
+		limit 1`, name,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Error("failed to match artist", "name", name, "error", err)
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// MatchSong ищет песню исполнителя по названию; при нескольких совпадениях
+// выбирается та, что с того же альбома. nil, если не нашлась.
+func (r *ScrobbleRepository) MatchSong(ctx context.Context, artistID int, title, album string) (*int, error) {
+	var id int
+
+	err := r.db.QueryRow(ctx, `
+		select s.id from song s
+		join album al on al.id = s.album_id
+		where s.artist_id = $1 and s.deleted_at is null
+		and (lower(s.title) = lower($2) or lower(s.full_title) = lower($2))
+		order by lower(al.title) = lower($3) desc, s.id
+		limit 1`, artistID, title, album,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Error("failed to match song", "artist_id", artistID, "error", err)
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// AddScrobble сохраняет прослушивание; повтор с тем же временем ничего не меняет
+func (r *ScrobbleRepository) AddScrobble(ctx context.Context, userID int, t domain.Track) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		insert into scrobble (user_id, artist_name, track_name, album_name, song_id, artist_id, duration, played_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		on conflict (user_id, played_at) do nothing`,
+		userID, t.Artist, t.Track, t.Album, t.SongID, t.ArtistID, t.Duration, t.PlayedAt)
+	if err != nil {
+		r.logger.Error("failed to add scrobble", "user_id", userID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *ScrobbleRepository) SetNowPlaying(ctx context.Context, n *domain.NowPlaying) error {
+	_, err := r.db.Exec(ctx, `
+		insert into scrobble_now_playing
+			(user_id, artist_name, track_name, album_name, song_id, artist_id, duration, started_at, expires_at)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		on conflict (user_id) do update set
+			artist_name = excluded.artist_name,
+			track_name = excluded.track_name,
+			album_name = excluded.album_name,
+			song_id = excluded.song_id,
+			artist_id = excluded.artist_id,
+			duration = excluded.duration,
+			started_at = excluded.started_at,
+			expires_at = excluded.expires_at`,
+		n.UserID,
+		n.Track.Artist,
+		n.Track.Track,
+		n.Track.Album,
+		n.Track.SongID,
+		n.Track.ArtistID,
+		n.Track.Duration,
+		n.StartedAt,
+		n.ExpiresAt,
+	)
+	if err != nil {
+		r.logger.Error("failed to set now playing", "user_id", n.UserID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetNowPlaying возвращает текущий трек пользователя, если он ещё не истёк
+func (r *ScrobbleRepository) GetNowPlaying(ctx context.Context, userID int, now time.Time) (*domain.NowPlaying, error) {
+	n := domain.NowPlaying{UserID: userID}
+
+	err := r.db.QueryRow(ctx, `
+		select artist_name, track_name, album_name, song_id, artist_id, duration, started_at, expires_at
+		from scrobble_now_playing
+		where user_id = $1 and expires_at > $2`, userID, now,
+	).Scan(
+		&n.Track.Artist,
+		&n.Track.Track,
+		&n.Track.Album,
+		&n.Track.SongID,
+		&n.Track.ArtistID,
+		&n.Track.Duration,
+		&n.StartedAt,
+		&n.ExpiresAt,
+	)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.Error("failed to get now playing", "user_id", userID, "error", err)
+		}
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+func (r *ScrobbleRepository) GetScrobbles(ctx context.Context, userID, limit, offset int) ([]domain.Scrobble, error) {
+	rows, err := r.db.Query(ctx, `
+		select id, user_id, artist_name, track_name, album_name, song_id, artist_id, duration, played_at, created_at
+		from scrobble
+		where user_id = $1
+		order by played_at desc
+		limit $2 offset $3`, userID, limit, offset)
+	if err != nil {
+		r.logger.Error("failed to get scrobbles", "user_id", userID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	scrobbles := make([]domain.Scrobble, 0)
+
+	for rows.Next() {
+		var s domain.Scrobble
+		err = rows.Scan(
+			&s.ID,
+			&s.UserID,
+			&s.Track.Artist,
+			&s.Track.Track,
+			&s.Track.Album,
+			&s.Track.SongID,
+			&s.Track.ArtistID,
+			&s.Track.Duration,
+			&s.Track.PlayedAt,
+			&s.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		scrobbles = append(scrobbles, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scrobbles, nil
+}
+
+// GetSummary считает прослушивания с since; несопоставленные исполнители
+// и песни различаются по имени без учёта регистра
+func (r *ScrobbleRepository) GetSummary(ctx context.Context, userID int, since time.Time) (*domain.Summary, error) {
+	var s domain.Summary
+
+	err := r.db.QueryRow(ctx, `
+		select count(*),
+		count(distinct coalesce(artist_id::text, lower(artist_name))),
+		count(distinct coalesce(song_id::text, lower(artist_name) || E'\n' || lower(track_name))),
+		min(played_at), max(played_at)
+		from scrobble
+		where user_id = $1 and played_at >= $2`, userID, since,
+	).Scan(&s.Scrobbles, &s.Artists, &s.Songs, &s.First, &s.Last)
+	if err != nil {
+		r.logger.Error("failed to get scrobble summary", "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (r *ScrobbleRepository) GetTopArtists(
+	ctx context.Context,
+	userID int,
+	since time.Time,
+	limit int,
+) ([]domain.ArtistStat, error) {
+	rows, err := r.db.Query(ctx, `
+		select sc.artist_id, min(coalesce(ar.nickname, sc.artist_name)), count(*) as plays
+		from scrobble sc
+		left join artist ar on ar.id = sc.artist_id
+		where sc.user_id = $1 and sc.played_at >= $2
+		group by sc.artist_id, case when sc.artist_id is null then lower(sc.artist_name) end
+		order by plays desc, 2
+		limit $3`, userID, since, limit)
+	if err != nil {
+		r.logger.Error("failed to get top artists", "user_id", userID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]domain.ArtistStat, 0)
+
+	for rows.Next() {
+		var a domain.ArtistStat
+		if err = rows.Scan(&a.ArtistID, &a.Name, &a.Plays); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		stats = append(stats, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *ScrobbleRepository) GetTopSongs(
+	ctx context.Context,
+	userID int,
+	since time.Time,
+	limit int,
+) ([]domain.SongStat, error) {
+	rows, err := r.db.Query(ctx, `
+		select sc.song_id, min(coalesce(s.title, sc.track_name)),
+		min(coalesce(s.artist_id, sc.artist_id)), min(coalesce(ar.nickname, sc.artist_name)),
+		count(*) as plays
+		from scrobble sc
+		left join song s on s.id = sc.song_id
+		left join artist ar on ar.id = coalesce(s.artist_id, sc.artist_id)
+		where sc.user_id = $1 and sc.played_at >= $2
+		group by sc.song_id,
+			case when sc.song_id is null then lower(sc.artist_name) end,
+			case when sc.song_id is null then lower(sc.track_name) end
+		order by plays desc, 2
+		limit $3`, userID, since, limit)
+	if err != nil {
+		r.logger.Error("failed to get top songs", "user_id", userID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]domain.SongStat, 0)
+
+	for rows.Next() {
+		var s domain.SongStat
+		if err = rows.Scan(&s.SongID, &s.Title, &s.ArtistID, &s.Artist, &s.Plays); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maYkiss56/tunes/internal/domain/slug"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+// Сущности со слагами, имя совпадает с таблицей
+const (
+	slugArtist = "artist"
+	slugAlbum  = "album"
+	slugSong   = "song"
+)
+
+// assignSlug выдаёт сущности свободный слаг по source. Если текущий слаг
+// уже построен из той же основы, он сохраняется. Прежний слаг уходит
+// в slug_history, чтобы старые ссылки продолжали работать.
+func assignSlug(ctx context.Context, tx pgx.Tx, entity string, id int, source string) (string, error) {
+	var current string
+	err := tx.QueryRow(ctx,
+		fmt.Sprintf(`select coalesce(slug, '') from %s where id=$1`, entity), id,
+	).Scan(&current)
+	if err != nil {
+		return "", err
+	}
+
+	base := slug.Make(source, entity)
+	if current != "" && slug.HasBase(current, base) {
+		return current, nil
+	}
+
+	var candidate string
+	for n := 1; ; n++ {
+		candidate = slug.WithSuffix(base, n)
+
+		var taken bool
+		err = tx.QueryRow(ctx, fmt.Sprintf(`
+			select exists(select 1 from %s where slug=$1 and id<>$2)
+			or exists(select 1 from slug_history where entity=$3 and slug=$1 and entity_id<>$2)`, entity),
+			candidate, id, entity,
+		).Scan(&taken)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			break
+		}
+	}
+
+	if current != "" {
+		_, err = tx.Exec(ctx, `
+			insert into slug_history (entity, slug, entity_id) values ($1, $2, $3)
+			on conflict (entity, slug) do update set entity_id = excluded.entity_id`,
+			entity, current, id,
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// слаг снова стал текущим и в истории больше не нужен
+	if _, err = tx.Exec(ctx, `delete from slug_history where entity=$1 and slug=$2`, entity, candidate); err != nil {
+		return "", err
+	}
+
+	if _, err = tx.Exec(ctx, fmt.Sprintf(`update %s set slug=$1 where id=$2`, entity), candidate, id); err != nil {
+		return "", err
+	}
+
+	return candidate, nil
+}
+
+// findSlugRedirect ищет сущность по старому слагу и возвращает её текущий слаг
+func findSlugRedirect(ctx context.Context, db *pgxpool.Pool, entity, old string) (string, error) {
+	var current string
+
+	err := db.QueryRow(ctx, fmt.Sprintf(`
+		select e.slug from slug_history h
+		join %s e on e.id = h.entity_id
+		where h.entity=$1 and h.slug=$2 and e.deleted_at is null and e.slug is not null`, entity),
+		entity, old,
+	).Scan(&current)
+	if err != nil {
+		return "", err
+	}
+
+	return current, nil
+}
+
+type SlugRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewSlugRepository(db *pgxpool.Pool, logger *logger.Logger) *SlugRepository {
+	return &SlugRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Backfill выдаёт слаги строкам, созданным до их появления
+func (r *SlugRepository) Backfill(ctx context.Context) (int, error) {
+	sources := map[string]string{
+		slugArtist: "nickname",
+		slugAlbum:  "title",
+		slugSong:   "title",
+	}
+
+	total := 0
+	for _, entity := range []string{slugArtist, slugAlbum, slugSong} {
+		n, err := r.backfill(ctx, entity, sources[entity])
+		if err != nil {
+			r.logger.Error("failed to backfill slugs", "entity", entity, "error", err)
+			return total, err
+		}
+		total += n
+	}
+
+	return total, nil
+}
+
+func (r *SlugRepository) backfill(ctx context.Context, entity, column string) (int, error) {
+	rows, err := r.db.Query(ctx,
+		fmt.Sprintf(`select id, %s from %s where slug is null order by id`, column, entity))
+	if err != nil {
+		return 0, err
+	}
+
+	type pending struct {
+		id     int
+		source string
+	}
+	var items []pending
+
+	for rows.Next() {
+		var p pending
+		if err = rows.Scan(&p.id, &p.source); err != nil {
+			rows.Close()
+			r.logger.Error("failed to scan rows", "error", err)
+			return 0, err
+		}
+		items = append(items, p)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, p := range items {
+		if _, err = assignSlug(ctx, tx, entity, p.id, p.source); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(items), nil
+}
@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maYkiss56/tunes/internal/domain/review"
+	domain "github.com/maYkiss56/tunes/internal/domain/snapshot"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type SnapshotRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewSnapshotRepository(db *pgxpool.Pool, logger *logger.Logger) *SnapshotRepository {
+	return &SnapshotRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ReadSnapshot выполняет fn в транзакции REPEATABLE READ только для чтения:
+// все выгрузки, вызванные с переданным контекстом, видят один и тот же снимок базы
+func (r *SnapshotRepository) ReadSnapshot(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		r.logger.Error("failed to begin snapshot transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *SnapshotRepository) ExportGenres(ctx context.Context) ([]domain.Genre, error) {
+	query := `select id, title, image_url, deleted_at from genre order by id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to export genres", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	genres := make([]domain.Genre, 0)
+
+	for rows.Next() {
+		var g domain.Genre
+		if err = rows.Scan(&g.ID, &g.Title, &g.ImageURL, &g.DeletedAt); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		genres = append(genres, g)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+func (r *SnapshotRepository) ExportArtists(ctx context.Context) ([]domain.Artist, error) {
+	query := `
+		select id, nickname, bio, country, coalesce(mbid::text, ''), coalesce(slug, ''), deleted_at
+		from artist order by id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to export artists", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	artists := make([]domain.Artist, 0)
+
+	for rows.Next() {
+		var a domain.Artist
+		if err = rows.Scan(&a.ID, &a.Nickname, &a.BIO, &a.Country, &a.MBID, &a.Slug, &a.DeletedAt); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		artists = append(artists, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return artists, nil
+}
+
+func (r *SnapshotRepository) ExportAlbums(ctx context.Context) ([]domain.Album, error) {
+	query := `
+		select id, title, image_url, artist_id,
+		coalesce(upc, ''), coalesce(mbid::text, ''), coalesce(slug, ''), deleted_at
+		from album order by id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to export albums", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	albums := make([]domain.Album, 0)
+
+	for rows.Next() {
+		var a domain.Album
+		if err = rows.Scan(
+			&a.ID, &a.Title, &a.ImageURL, &a.ArtistID, &a.UPC, &a.MBID, &a.Slug, &a.DeletedAt,
+		); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		albums = append(albums, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return albums, nil
+}
+
+func (r *SnapshotRepository) ExportSongs(ctx context.Context) ([]domain.Song, error) {
+	query := `
+		select id, title, full_title, image_url, release_date,
+		genre_id, artist_id, album_id, created_at, updated_at,
+		coalesce(isrc, ''), coalesce(mbid::text, ''), coalesce(slug, ''), deleted_at
+		from song order by id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to export songs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := make([]domain.Song, 0)
+
+	for rows.Next() {
+		var s domain.Song
+		if err = rows.Scan(
+			&s.ID, &s.Title, &s.FullTitle, &s.ImageURL, &s.ReleaseDate,
+			&s.GenreID, &s.ArtistID, &s.AlbumID, &s.CreatedAt, &s.UpdatedAt,
+			&s.ISRC, &s.MBID, &s.Slug, &s.DeletedAt,
+		); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		songs = append(songs, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return songs, nil
+}
+
+func (r *SnapshotRepository) ExportLyrics(ctx context.Context) ([]domain.Lyrics, error) {
+	query := `select song_id, plain, lrc, updated_at from song_lyrics order by song_id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to export lyrics", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	lyrics := make([]domain.Lyrics, 0)
+
+	for rows.Next() {
+		var l domain.Lyrics
+		if err = rows.Scan(&l.SongID, &l.Plain, &l.LRC, &l.UpdatedAt); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		lyrics = append(lyrics, l)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lyrics, nil
+}
+
+func (r *SnapshotRepository) ExportReviews(ctx context.Context) ([]domain.Review, error) {
+	query := `
+		select r.id, u.email, r.song_id, r.album_id, r.artist_id, r.body,
+		r.is_like, r.is_valid, r.score, r.created_at, r.updated_at
+		from review r
+		join users u on r.user_id = u.id
+		where r.status = 'published'
+		order by r.id`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to export reviews", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := make([]domain.Review, 0)
+
+	for rows.Next() {
+		var (
+			rv                        domain.Review
+			songID, albumID, artistID *int
+		)
+		if err = rows.Scan(
+			&rv.ID, &rv.UserEmail, &songID, &albumID, &artistID, &rv.Body,
+			&rv.IsLike, &rv.IsValid, &rv.Score, &rv.CreatedAt, &rv.UpdatedAt,
+		); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		target := scanTarget(songID, albumID, artistID)
+		rv.TargetType, rv.TargetID = target.Type, target.ID
+		reviews = append(reviews, rv)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Restore вставляет данные снапшота в пустую базу в одной транзакции.
+// Новые идентификаторы выдаёт база, ссылки переписываются по таблицам соответствия.
+func (r *SnapshotRepository) Restore(ctx context.Context, data *domain.Data) (*domain.RestoreResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var notEmpty bool
+	err = tx.QueryRow(ctx, `
+		select exists(select 1 from genre)
+		or exists(select 1 from artist)
+		or exists(select 1 from album)
+		or exists(select 1 from song)`).Scan(&notEmpty)
+	if err != nil {
+		return nil, err
+	}
+	if notEmpty {
+		return nil, domain.ErrDatabaseNotEmpty
+	}
+
+	res := domain.NewRestoreResult()
+
+	for _, g := range data.Genres {
+		var id int
+		err = tx.QueryRow(ctx,
+			`insert into genre (title, image_url, deleted_at) values ($1, $2, $3) returning id`,
+			g.Title, g.ImageURL, g.DeletedAt,
+		).Scan(&id)
+		if err != nil {
+			r.logger.Error("failed to restore genre", "id", g.ID, "error", err)
+			return nil, err
+		}
+		res.Genres[g.ID] = id
+	}
+
+	for _, a := range data.Artists {
+		var id int
+		err = tx.QueryRow(ctx,
+			`insert into artist (nickname, bio, country, mbid, slug, deleted_at)
+			values ($1, $2, $3, nullif($4, '')::uuid, nullif($5, ''), $6) returning id`,
+			a.Nickname, a.BIO, a.Country, a.MBID, a.Slug, a.DeletedAt,
+		).Scan(&id)
+		if err != nil {
+			r.logger.Error("failed to restore artist", "id", a.ID, "error", err)
+			return nil, err
+		}
+		res.Artists[a.ID] = id
+	}
+
+	for _, a := range data.Albums {
+		artistID, ok := res.Artists[a.ArtistID]
+		if !ok {
+			return nil, fmt.Errorf("album %d references unknown artist %d", a.ID, a.ArtistID)
+		}
+
+		var id int
+		err = tx.QueryRow(ctx,
+			`insert into album (title, image_url, artist_id, upc, mbid, slug, deleted_at)
+			values ($1, $2, $3, nullif($4, ''), nullif($5, '')::uuid, nullif($6, ''), $7) returning id`,
+			a.Title, a.ImageURL, artistID, a.UPC, a.MBID, a.Slug, a.DeletedAt,
+		).Scan(&id)
+		if err != nil {
+			r.logger.Error("failed to restore album", "id", a.ID, "error", err)
+			return nil, err
+		}
+		res.Albums[a.ID] = id
+	}
+
+	for _, s := range data.Songs {
+		genreID, ok := res.Genres[s.GenreID]
+		if !ok {
+			return nil, fmt.Errorf("song %d references unknown genre %d", s.ID, s.GenreID)
+		}
+		artistID, ok := res.Artists[s.ArtistID]
+		if !ok {
+			return nil, fmt.Errorf("song %d references unknown artist %d", s.ID, s.ArtistID)
+		}
+		albumID, ok := res.Albums[s.AlbumID]
+		if !ok {
+			return nil, fmt.Errorf("song %d references unknown album %d", s.ID, s.AlbumID)
+		}
+
+		var id int
+		err = tx.QueryRow(ctx, `insert into song
+			(title, full_title, image_url, release_date, genre_id, artist_id, album_id,
+			isrc, mbid, slug, created_at, updated_at, deleted_at)
+			values ($1, $2, $3, $4, $5, $6, $7, nullif($8, ''), nullif($9, '')::uuid, nullif($10, ''), $11, $12, $13)
+			returning id`,
+			s.Title, s.FullTitle, s.ImageURL, s.ReleaseDate,
+			genreID, artistID, albumID, s.ISRC, s.MBID, s.Slug, s.CreatedAt, s.UpdatedAt, s.DeletedAt,
+		).Scan(&id)
+		if err != nil {
+			r.logger.Error("failed to restore song", "id", s.ID, "error", err)
+			return nil, err
+		}
+		res.Songs[s.ID] = id
+	}
+
+	for _, l := range data.Lyrics {
+		songID, ok := res.Songs[l.SongID]
+		if !ok {
+			return nil, fmt.Errorf("lyrics reference unknown song %d", l.SongID)
+		}
+
+		_, err = tx.Exec(ctx,
+			`insert into song_lyrics (song_id, plain, lrc, updated_at) values ($1, $2, $3, $4)`,
+			songID, l.Plain, l.LRC, l.UpdatedAt,
+		)
+		if err != nil {
+			r.logger.Error("failed to restore lyrics", "song_id", l.SongID, "error", err)
+			return nil, err
+		}
+		res.Lyrics++
+	}
+
+	users := make(map[string]int)
+	for _, rv := range data.Reviews {
+		target := rv.Target()
+		targetID, ok := restoredTargetID(res, target)
+		if !ok {
+			return nil, fmt.Errorf("review %d references unknown %s", rv.ID, target)
+		}
+
+		userID, ok := users[rv.UserEmail]
+		if !ok {
+			err = tx.QueryRow(ctx, `select id from users where email=$1`, rv.UserEmail).Scan(&userID)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return nil, err
+			}
+			users[rv.UserEmail] = userID
+		}
+		if userID == 0 {
+			res.SkippedReviews++
+			continue
+		}
+
+		// в старых снапшотах у пользователя может быть несколько рецензий на песню
+		tag, err := tx.Exec(ctx, `insert into review
+			(user_id, `+targetColumn(target.Type)+`, body, is_like, is_valid, score, created_at, updated_at)
+			values ($1, $2, $3, $4, $5, $6, $7, $8)
+			on conflict (user_id, `+targetColumn(target.Type)+`) do nothing`,
+			userID, targetID, rv.Body, rv.IsLike, rv.IsValid, rv.Score, rv.CreatedAt, rv.UpdatedAt,
+		)
+		if err != nil {
+			r.logger.Error("failed to restore review", "id", rv.ID, "error", err)
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			res.SkippedReviews++
+			continue
+		}
+		res.Reviews++
+	}
+
+	// счётчики всегда пересчитываются по восстановленным рецензиям
+	_, err = tx.Exec(ctx, songCountersRecompute("true"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(ctx, songRankRefresh); err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(ctx, targetCountersRecompute("album", "album_id", "true")); err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(ctx, targetCountersRecompute("artist", "artist_id", "true")); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// restoredTargetID новый id объекта рецензии по таблицам соответствия
+func restoredTargetID(res *domain.RestoreResult, t review.Target) (int, bool) {
+	var ids map[int]int
+	switch t.Type {
+	case review.TargetSong:
+		ids = res.Songs
+	case review.TargetAlbum:
+		ids = res.Albums
+	case review.TargetArtist:
+		ids = res.Artists
+	default:
+		return 0, false
+	}
+
+	id, ok := ids[t.ID]
+	return id, ok
+}
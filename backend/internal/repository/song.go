@@ -13,8 +13,10 @@ import (
 	"github.com/maYkiss56/tunes/internal/domain/album"
 	"github.com/maYkiss56/tunes/internal/domain/artist"
 	"github.com/maYkiss56/tunes/internal/domain/genre"
+	"github.com/maYkiss56/tunes/internal/domain/review"
 	domain "github.com/maYkiss56/tunes/internal/domain/song"
 	"github.com/maYkiss56/tunes/internal/domain/song/dto"
+	"github.com/maYkiss56/tunes/internal/domain/trash"
 	"github.com/maYkiss56/tunes/internal/logger"
 )
 
@@ -30,12 +32,201 @@ func NewSongRepository(db *pgxpool.Pool, logger *logger.Logger) *SongRepository
 	}
 }
 
+// songColumns и songJoins выбирают песню вместе с жанром, исполнителем и альбомом;
+// порядок колонок должен совпадать со scanSong
+const songColumns = `
+	s.id, s.title, s.full_title,
+	s.image_url, s.release_date, s.like_count,
+	s.dislike_count, s.rating, s.genre_id, s.artist_id,
+	s.album_id, s.created_at, s.updated_at,
+	coalesce(s.isrc, ''), coalesce(s.mbid::text, ''), coalesce(s.slug, ''),
+	s.score_avg::float8, s.score_count, s.score_histogram,
+	g.id, g.title, g.image_url,
+	ar.id, ar.nickname, ar.bio, ar.country, coalesce(ar.slug, ''),
+	al.id, al.title, al.image_url, al.artist_id, coalesce(al.slug, ''),
+	al_ar.id, al_ar.nickname, al_ar.bio, al_ar.country, coalesce(al_ar.slug, '')`
+
+const songJoins = `
+	from song s
+	join genre g on s.genre_id = g.id
+	join artist ar on s.artist_id = ar.id
+	join album al on s.album_id = al.id
+	join artist al_ar on al.artist_id = al_ar.id`
+
+const songSelect = "select" + songColumns + songJoins
+
+// songListed отбирает песни для публичных списков, поиска и топов: сама песня,
+// её альбом, исполнитель и жанр не в корзине
+const songListed = "s.deleted_at is null and al.deleted_at is null and ar.deleted_at is null and g.deleted_at is null"
+
+// reviewCounted отбирает рецензии r, входящие в счётчики: валидные и опубликованные
+const reviewCounted = "r.is_valid = true and r.status = 'published'"
+
+// scoreHistogram считает оценки 1..10 по строкам review r в массив int[]
+var scoreHistogram = func() string {
+	parts := make([]string, 0, review.MaxScore)
+	for v := review.MinScore; v <= review.MaxScore; v++ {
+		parts = append(parts, fmt.Sprintf("count(r.id) filter (where r.score = %d)", v))
+	}
+	return "array[" + strings.Join(parts, ", ") + "]::int[]"
+}()
+
+// songCountersActual агрегирует учитываемые рецензии песен s, подходящих под where
+func songCountersActual(where string) string {
+	return `
+		select s.id,
+		count(r.id) filter (where r.is_like = true) as like_count,
+		count(r.id) filter (where r.is_like = false) as dislike_count,
+		count(r.score) as score_count,
+		coalesce(sum(r.score), 0) as score_sum,
+		round(avg(r.score), 2) as score_avg,
+		` + scoreHistogram + ` as score_histogram
+		from song s
+		left join review r on r.song_id = s.id and ` + reviewCounted + `
+		where ` + where + `
+		group by s.id`
+}
+
+// songCountersRecompute перезаписывает счётчики песен, подходящих под where, агрегатами рецензий
+func songCountersRecompute(where string) string {
+	return `
+		update song s set
+			like_count = c.like_count,
+			dislike_count = c.dislike_count,
+			rating = c.like_count - c.dislike_count,
+			score_count = c.score_count,
+			score_sum = c.score_sum,
+			score_avg = c.score_avg,
+			score_histogram = c.score_histogram
+		from (` + songCountersActual(where) + `) c
+		where s.id = c.id`
+}
+
+// songRankRefresh обновляет кэш глобального априора и пересчитывает предрасчитанные
+// ранги всех песен: байесовское среднее зависит от априора и устаревает со временем
+const songRankRefresh = `
+	with p as (
+		update song_rank_prior_cache c set
+			mean = v.mean,
+			weight = v.weight,
+			refreshed_at = now()
+		from song_rank_prior v
+		returning c.mean, c.weight
+	)
+	update song s set
+		rank_wilson = wilson_lower_bound(s.like_count, s.dislike_count),
+		rank_bayesian = coalesce(bayesian_average(s.like_count, s.dislike_count, p.mean, p.weight), 0),
+		rank_hot = hot_score(s.like_count - s.dislike_count, s.created_at)
+	from p`
+
+// rankColumn возвращает колонку song с предрасчитанным рангом
+func rankColumn(rank domain.Rank) string {
+	switch rank {
+	case domain.RankWilson:
+		return "s.rank_wilson"
+	case domain.RankBayesian:
+		return "s.rank_bayesian"
+	case domain.RankHot:
+		return "s.rank_hot"
+	default:
+		return "s.rating"
+	}
+}
+
+// rankExpr считает ранг на лету по счётчикам подзапроса t и априору p,
+// когда предрасчитанные колонки не подходят (голоса за период)
+func rankExpr(rank domain.Rank) string {
+	switch rank {
+	case domain.RankWilson:
+		return "wilson_lower_bound(t.like_count::int, t.dislike_count::int)"
+	case domain.RankBayesian:
+		return "coalesce(bayesian_average(t.like_count::int, t.dislike_count::int, p.mean, p.weight), 0)"
+	case domain.RankHot:
+		return "hot_score(t.rating::int, t.created_at)"
+	default:
+		return "t.rating"
+	}
+}
+
+// extraRow дописывает к колонкам песни дополнительные поля запроса
+type extraRow struct {
+	pgx.Row
+	extra []any
+}
+
+func (r extraRow) Scan(dest ...any) error {
+	return r.Row.Scan(append(dest, r.extra...)...)
+}
+
+func scanSong(row pgx.Row) (dto.Response, error) {
+	var (
+		song        domain.Song
+		genre       genre.Genre
+		songArtist  artist.Artist
+		album       album.Album
+		albumArtist artist.Artist
+	)
+
+	err := row.Scan(
+		&song.ID,
+		&song.Title,
+		&song.FullTitle,
+		&song.ImageURL,
+		&song.ReleaseDate,
+		&song.LikeCount,
+		&song.DislikeCount,
+		&song.Rating,
+		&song.GenreID,
+		&song.ArtistID,
+		&song.AlbumID,
+		&song.CreatedAt,
+		&song.UpdatedAt,
+		&song.ISRC,
+		&song.MBID,
+		&song.Slug,
+		&song.ScoreAverage,
+		&song.ScoreCount,
+		&song.ScoreHistogram,
+		&genre.ID,
+		&genre.Title,
+		&genre.ImageURL,
+		&songArtist.ID,
+		&songArtist.Nickname,
+		&songArtist.BIO,
+		&songArtist.Country,
+		&songArtist.Slug,
+		&album.ID,
+		&album.Title,
+		&album.ImageURL,
+		&album.ArtistID,
+		&album.Slug,
+		&albumArtist.ID,
+		&albumArtist.Nickname,
+		&albumArtist.BIO,
+		&albumArtist.Country,
+		&albumArtist.Slug,
+	)
+	if err != nil {
+		return dto.Response{}, err
+	}
+
+	return dto.ToResponse(song, genre, songArtist, album, albumArtist), nil
+}
+
 func (r *SongRepository) CreateSong(ctx context.Context, song *domain.Song) error {
 	query := `insert into song
-		(title, full_title, image_url, release_date, genre_id, artist_id, album_id, created_at, updated_at)
-		values ($1, $2, $3, $4, $5, $6, $7, $8, $9) returning id`
+		(title, full_title, image_url, release_date, genre_id, artist_id, album_id,
+		isrc, mbid, created_at, updated_at)
+		values ($1, $2, $3, $4, $5, $6, $7, nullif($8, ''), nullif($9, '')::uuid, $10, $11)
+		returning id`
 
-	err := r.db.QueryRow(
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
 		ctx,
 		query,
 		song.Title,
@@ -45,15 +236,27 @@ func (r *SongRepository) CreateSong(ctx context.Context, song *domain.Song) erro
 		song.GenreID,
 		song.ArtistID,
 		song.AlbumID,
+		song.ISRC,
+		song.MBID,
 		time.Now(),
 		time.Now(),
 	).Scan(&song.ID)
 	if err != nil {
 		r.logger.Error("failed to create song", "error", err)
+		return wrapUniqueViolation(err)
+	}
+
+	if song.Slug, err = assignSlug(ctx, tx, slugSong, song.ID, song.Title); err != nil {
+		r.logger.Error("failed to assign song slug", "error", err)
 		return err
 	}
 
-	return nil
+	if err = recordSongRelease(ctx, tx, song.ArtistID, song.ID, song.AlbumID); err != nil {
+		r.logger.Error("failed to record song release", "error", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *SongRepository) GetSongRating(ctx context.Context, songID int) (int, int, int, error) {
@@ -61,12 +264,11 @@ func (r *SongRepository) GetSongRating(ctx context.Context, songID int) (int, in
 		SELECT
       COALESCE(SUM(CASE WHEN is_like = true THEN 1 ELSE 0 END), 0) as like_count,
       COALESCE(SUM(CASE WHEN is_like = false THEN 1 ELSE 0 END), 0) as dislike_count
-     FROM review
-     WHERE song_id = $1 AND is_valid = true
-`
+     FROM review r
+     WHERE r.song_id = $1 AND ` + reviewCounted
 
 	var likeCount, dislikeCount int
-	err := r.db.QueryRow(ctx, query, songID).Scan(&likeCount, &dislikeCount)
+	err := conn(ctx, r.db).QueryRow(ctx, query, songID).Scan(&likeCount, &dislikeCount)
 	if err != nil {
 		return 0, 0, 0, err
 	}
@@ -75,23 +277,10 @@ func (r *SongRepository) GetSongRating(ctx context.Context, songID int) (int, in
 	return likeCount, dislikeCount, rating, nil
 }
 
-func (r *SongRepository) GetAllSongsSortedByRating(ctx context.Context) ([]dto.Response, error) {
-	query := `
-        SELECT s.id, s.title, s.full_title,
-        s.image_url, s.release_date,
-		    s.like_count, s.dislike_count, s.rating,
-		    s.genre_id, s.artist_id, s.album_id,
-        s.created_at, s.updated_at,
-        g.id, g.title, g.image_url,
-        ar.id, ar.nickname, ar.bio, ar.country,
-        al.id, al.title, al.image_url, al.artist_id,
-        al_ar.id, al_ar.nickname, al_ar.bio, al_ar.country
-        FROM song s
-        JOIN genre g ON s.genre_id = g.id
-        JOIN artist ar ON s.artist_id = ar.id
-        JOIN album al ON s.album_id = al.id
-        JOIN artist al_ar ON al.artist_id = al_ar.id
-        ORDER BY s.rating DESC, s.created_at DESC`
+func (r *SongRepository) GetAllSongsSortedByRating(ctx context.Context, rank domain.Rank) ([]dto.Response, error) {
+	query := songSelect + `
+		where ` + songListed + `
+		order by ` + rankColumn(rank) + ` desc, s.rating desc, s.created_at desc`
 
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
@@ -103,49 +292,13 @@ func (r *SongRepository) GetAllSongsSortedByRating(ctx context.Context) ([]dto.R
 	songs := make([]dto.Response, 0)
 
 	for rows.Next() {
-		var (
-			song        domain.Song
-			genre       genre.Genre
-			songArtist  artist.Artist
-			album       album.Album
-			albumArtist artist.Artist
-		)
-		err = rows.Scan(
-			&song.ID,
-			&song.Title,
-			&song.FullTitle,
-			&song.ImageURL,
-			&song.ReleaseDate,
-			&song.LikeCount,
-			&song.DislikeCount,
-			&song.Rating,
-			&song.GenreID,
-			&song.ArtistID,
-			&song.AlbumID,
-			&song.CreatedAt,
-			&song.UpdatedAt,
-			&genre.ID,
-			&genre.Title,
-			&genre.ImageURL,
-			&songArtist.ID,
-			&songArtist.Nickname,
-			&songArtist.BIO,
-			&songArtist.Country,
-			&album.ID,
-			&album.Title,
-			&album.ImageURL,
-			&album.ArtistID,
-			&albumArtist.ID,
-			&albumArtist.Nickname,
-			&albumArtist.BIO,
-			&albumArtist.Country,
-		)
+		song, err := scanSong(rows)
 		if err != nil {
 			r.logger.Error("failed to scan rows", "error", err)
 			return nil, err
 		}
 
-		songs = append(songs, dto.ToResponse(song, genre, songArtist, album, albumArtist))
+		songs = append(songs, song)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
@@ -156,7 +309,12 @@ func (r *SongRepository) GetAllSongsSortedByRating(ctx context.Context) ([]dto.R
 
 // repository/song_repository.go
 
-func (r *SongRepository) GetTopSongs(ctx context.Context, timeRange string, limit int) ([]dto.Response, error) {
+func (r *SongRepository) GetTopSongs(
+	ctx context.Context,
+	timeRange string,
+	limit int,
+	rank domain.Rank,
+) ([]dto.Response, error) {
 	var timeCondition string
 
 	switch timeRange {
@@ -165,10 +323,51 @@ func (r *SongRepository) GetTopSongs(ctx context.Context, timeRange string, limi
 	case "month":
 		timeCondition = "AND r.created_at >= NOW() - INTERVAL '30 days'"
 	default:
+		// за всё время счётчики и ранги уже лежат в song
 		timeCondition = ""
 	}
 
-	query := `
+	query := songSelect + `
+		where ` + songListed + `
+		order by ` + rankColumn(rank) + ` desc, s.like_count desc
+		limit $1`
+
+	if timeCondition != "" {
+		query = `select t.* from (` + topSongsWindow(timeCondition) + `) t
+			cross join song_rank_prior_cache p
+			order by ` + rankExpr(rank) + ` desc, t.like_count desc
+			limit $1`
+	}
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("failed to get top songs", "error", err, "timeRange", timeRange)
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := make([]dto.Response, 0)
+
+	for rows.Next() {
+		song, err := scanSong(rows)
+		if err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+
+		songs = append(songs, song)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return songs, nil
+}
+
+// topSongsWindow пересчитывает счётчики песен по рецензиям, подходящим под timeCondition
+func topSongsWindow(timeCondition string) string {
+	return `
         SELECT 
             s.id, s.title, s.full_title,
             s.image_url, s.release_date,
@@ -178,23 +377,26 @@ func (r *SongRepository) GetTopSongs(ctx context.Context, timeRange string, limi
             COALESCE(SUM(CASE WHEN r.is_like = false THEN 1 ELSE 0 END), 0) as rating,
             s.genre_id, s.artist_id, s.album_id,
             s.created_at, s.updated_at,
+            coalesce(s.isrc, ''), coalesce(s.mbid::text, ''), coalesce(s.slug, ''),
+            s.score_avg::float8, s.score_count, s.score_histogram,
             g.id, g.title, g.image_url,
-            ar.id, ar.nickname, ar.bio, ar.country,
-            al.id, al.title, al.image_url, al.artist_id,
-            al_ar.id, al_ar.nickname, al_ar.bio, al_ar.country
+            ar.id, ar.nickname, ar.bio, ar.country, coalesce(ar.slug, ''),
+            al.id, al.title, al.image_url, al.artist_id, coalesce(al.slug, ''),
+            al_ar.id, al_ar.nickname, al_ar.bio, al_ar.country, coalesce(al_ar.slug, '')
         FROM song s
-        LEFT JOIN review r ON s.id = r.song_id AND r.is_valid = true ` + timeCondition + `
+        LEFT JOIN review r ON s.id = r.song_id AND ` + reviewCounted + ` ` + timeCondition + `
         JOIN genre g ON s.genre_id = g.id
         JOIN artist ar ON s.artist_id = ar.id
         JOIN album al ON s.album_id = al.id
         JOIN artist al_ar ON al.artist_id = al_ar.id
-        GROUP BY s.id, g.id, ar.id, al.id, al_ar.id
-        ORDER BY rating DESC, like_count DESC
-        LIMIT $1`
+        WHERE ` + songListed + `
+        GROUP BY s.id, g.id, ar.id, al.id, al_ar.id`
+}
 
-	rows, err := r.db.Query(ctx, query, limit)
+func (r *SongRepository) GetAllSongs(ctx context.Context) ([]dto.Response, error) {
+	rows, err := r.db.Query(ctx, songSelect+" where "+songListed)
 	if err != nil {
-		r.logger.Error("failed to get top songs", "error", err, "timeRange", timeRange)
+		r.logger.Error("failed to get all songs", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
@@ -202,51 +404,14 @@ func (r *SongRepository) GetTopSongs(ctx context.Context, timeRange string, limi
 	songs := make([]dto.Response, 0)
 
 	for rows.Next() {
-		var (
-			song        domain.Song
-			genre       genre.Genre
-			songArtist  artist.Artist
-			album       album.Album
-			albumArtist artist.Artist
-		)
-		err = rows.Scan(
-			&song.ID,
-			&song.Title,
-			&song.FullTitle,
-			&song.ImageURL,
-			&song.ReleaseDate,
-			&song.LikeCount,
-			&song.DislikeCount,
-			&song.Rating,
-			&song.GenreID,
-			&song.ArtistID,
-			&song.AlbumID,
-			&song.CreatedAt,
-			&song.UpdatedAt,
-			&genre.ID,
-			&genre.Title,
-			&genre.ImageURL,
-			&songArtist.ID,
-			&songArtist.Nickname,
-			&songArtist.BIO,
-			&songArtist.Country,
-			&album.ID,
-			&album.Title,
-			&album.ImageURL,
-			&album.ArtistID,
-			&albumArtist.ID,
-			&albumArtist.Nickname,
-			&albumArtist.BIO,
-			&albumArtist.Country,
-		)
+		song, err := scanSong(rows)
 		if err != nil {
 			r.logger.Error("failed to scan rows", "error", err)
 			return nil, err
 		}
 
-		songs = append(songs, dto.ToResponse(song, genre, songArtist, album, albumArtist))
+		songs = append(songs, song)
 	}
-
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
@@ -254,157 +419,160 @@ func (r *SongRepository) GetTopSongs(ctx context.Context, timeRange string, limi
 	return songs, nil
 }
 
-func (r *SongRepository) GetAllSongs(ctx context.Context) ([]dto.Response, error) {
+// SearchSongs ищет песни по названию и строкам текста
+func (r *SongRepository) SearchSongs(ctx context.Context, q string, limit int) ([]dto.SearchResult, error) {
 	query := `
-		select s.id, s.title, s.full_title,
-		s.image_url, s.release_date, s.like_count,
-		s.dislike_count, s.rating, s.genre_id,
-		s.artist_id, s.album_id, s.created_at, s.updated_at,
-		g.id, g.title, g.image_url,
-		ar.id, ar.nickname, ar.bio, ar.country,
-		al.id, al.title, al.image_url, al.artist_id,
-		al_ar.id, al_ar.nickname, al_ar.bio, al_ar.country
-		from song s
-		join genre g on s.genre_id = g.id
-		join artist ar on s.artist_id = ar.id
-		join album al on s.album_id = al.id
-		join artist al_ar on al.artist_id = al_ar.id`
-
-	rows, err := r.db.Query(ctx, query)
+		with q as (select websearch_to_tsquery('simple', $1) as query)
+		select` + songColumns + `,
+		case when l.search @@ q.query then ts_headline('simple', l.plain, q.query,
+			'StartSel="",StopSel="",MaxFragments=1,MaxWords=12,MinWords=4') else '' end
+		` + songJoins + `
+		left join song_lyrics l on l.song_id = s.id
+		cross join q
+		where ` + songListed + `
+		and (to_tsvector('simple', s.title || ' ' || s.full_title) @@ q.query
+			or l.search @@ q.query)
+		order by greatest(
+			ts_rank(to_tsvector('simple', s.title || ' ' || s.full_title), q.query) * 2,
+			coalesce(ts_rank(l.search, q.query), 0)
+		) desc, s.id
+		limit $2`
+
+	rows, err := r.db.Query(ctx, query, q, limit)
 	if err != nil {
-		r.logger.Error("failed to get all songs", "error", err)
+		r.logger.Error("failed to search songs", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	songs := make([]dto.Response, 0)
+	results := make([]dto.SearchResult, 0)
 
 	for rows.Next() {
-		var (
-			song        domain.Song
-			genre       genre.Genre
-			songArtist  artist.Artist
-			album       album.Album
-			albumArtist artist.Artist
-		)
-		err = rows.Scan(
-			&song.ID,
-			&song.Title,
-			&song.FullTitle,
-			&song.ImageURL,
-			&song.ReleaseDate,
-			&song.LikeCount,
-			&song.DislikeCount,
-			&song.Rating,
-			&song.GenreID,
-			&song.ArtistID,
-			&song.AlbumID,
-			&song.CreatedAt,
-			&song.UpdatedAt,
-			&genre.ID,
-			&genre.Title,
-			&genre.ImageURL,
-			&songArtist.ID,
-			&songArtist.Nickname,
-			&songArtist.BIO,
-			&songArtist.Country,
-			&album.ID,
-			&album.Title,
-			&album.ImageURL,
-			&album.ArtistID,
-			&albumArtist.ID,
-			&albumArtist.Nickname,
-			&albumArtist.BIO,
-			&albumArtist.Country,
-		)
+		var snippet string
+		song, err := scanSong(extraRow{Row: rows, extra: []any{&snippet}})
 		if err != nil {
 			r.logger.Error("failed to scan rows", "error", err)
 			return nil, err
 		}
 
-		songs = append(songs, dto.ToResponse(song, genre, songArtist, album, albumArtist))
+		results = append(results, dto.SearchResult{Song: song, Snippet: snippet})
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return songs, nil
+	return results, nil
 }
 
 func (r *SongRepository) GetSongByID(ctx context.Context, id int) (*dto.Response, error) {
-	query := `
-		select s.id, s.title, s.full_title,
-		s.image_url, s.release_date, s.like_count,
-		s.dislike_count, s.rating, s.genre_id, s.artist_id,
-		s.album_id, s.created_at, s.updated_at,
-		g.id, g.title, g.image_url,
-		ar.id, ar.nickname, ar.bio, ar.country,
-		al.id, al.title, al.image_url, al.artist_id,
-		al_ar.id, al_ar.nickname, al_ar.bio, al_ar.country
-		from song s
-		join genre g on s.genre_id = g.id
-		join artist ar on s.artist_id = ar.id
-		join album al on s.album_id = al.id
-		join artist al_ar on al.artist_id = al_ar.id
-		where s.id = $1`
+	return r.getSong(ctx, "s.id = $1", id)
+}
 
-	var (
-		song        domain.Song
-		genre       genre.Genre
-		songArtist  artist.Artist
-		album       album.Album
-		albumArtist artist.Artist
-	)
+func (r *SongRepository) GetSongByISRC(ctx context.Context, isrc string) (*dto.Response, error) {
+	return r.getSong(ctx, "s.isrc = $1", isrc)
+}
 
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&song.ID, &song.Title, &song.FullTitle,
-			&song.ImageURL, &song.ReleaseDate, &song.LikeCount,
-			&song.DislikeCount, &song.Rating, &song.GenreID, &song.ArtistID,
-			&song.AlbumID, &song.CreatedAt, &song.UpdatedAt,
-			&genre.ID, &genre.Title, &genre.ImageURL,
-			&songArtist.ID, &songArtist.Nickname, &songArtist.BIO, &songArtist.Country,
-			&album.ID, &album.Title, &album.ImageURL, &album.ArtistID,
-			&albumArtist.ID, &albumArtist.Nickname, &albumArtist.BIO, &albumArtist.Country)
+func (r *SongRepository) GetSongByMBID(ctx context.Context, mbid string) (*dto.Response, error) {
+	return r.getSong(ctx, "s.mbid = $1::uuid", mbid)
+}
+
+func (r *SongRepository) GetSongBySlug(ctx context.Context, slug string) (*dto.Response, error) {
+	return r.getSong(ctx, "s.slug = $1", slug)
+}
+
+// GetSongSlugRedirect возвращает текущий слаг по слагу из истории
+func (r *SongRepository) GetSongSlugRedirect(ctx context.Context, slug string) (string, error) {
+	return findSlugRedirect(ctx, r.db, slugSong, slug)
+}
+
+func (r *SongRepository) getSong(ctx context.Context, where string, arg any) (*dto.Response, error) {
+	query := songSelect + " where s.deleted_at is null and " + where
+
+	res, err := scanSong(conn(ctx, r.db).QueryRow(ctx, query, arg))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			r.logger.Error("song not found", "id", id)
+			r.logger.Error("song not found", "where", where, "value", arg)
 			return nil, err
 		}
 		r.logger.Error("failed to search song", "error", err)
 		return nil, err
 	}
 
-	res := dto.ToResponse(song, genre, songArtist, album, albumArtist)
-
 	return &res, nil
 }
 
-func (r *SongRepository) UpdateSongRating(ctx context.Context, songID int) error {
-	// Сначала проверяем существование песни
-	var exists bool
-	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM song WHERE id = $1)", songID).Scan(&exists)
+// CountPublicLists считает публичные списки с песней для её карточки
+func (r *SongRepository) CountPublicLists(ctx context.Context, songID int) (int, error) {
+	var lists int
+
+	err := r.db.QueryRow(ctx, `
+		select count(*) from playlist_item i
+		join playlist p on p.id = i.playlist_id
+		where i.song_id = $1 and p.visibility = 'public'`,
+		songID,
+	).Scan(&lists)
 	if err != nil {
+		r.logger.Error("failed to count song lists", "song_id", songID, "error", err)
+		return 0, err
+	}
+
+	return lists, nil
+}
+
+// ApplyRatingDelta прибавляет к счётчикам песни вклад изменения рецензии
+// и обновляет ранги. Блокировка строки song сериализует встречные изменения,
+// поэтому вызывать его нужно в одной транзакции UnitOfWork с записью рецензии.
+// Априор берётся из кэша, чтобы не читать весь каталог под блокировкой.
+func (r *SongRepository) ApplyRatingDelta(ctx context.Context, songID int, d review.RatingDelta) error {
+	query := `
+		update song set
+			like_count = like_count + $2,
+			dislike_count = dislike_count + $3,
+			rating = rating + $2 - $3,
+			score_count = score_count + $4,
+			score_sum = score_sum + $5,
+			score_avg = round((score_sum + $5)::numeric / nullif(score_count + $4, 0), 2),
+			score_histogram = array(
+				select a + b from unnest(score_histogram, $6::int[]) with ordinality t(a, b, i) order by i
+			),
+			rank_wilson = wilson_lower_bound(like_count + $2, dislike_count + $3),
+			rank_bayesian = coalesce(bayesian_average(like_count + $2, dislike_count + $3, p.mean, p.weight), 0),
+			rank_hot = hot_score(rating + $2 - $3, song.created_at),
+			updated_at = now()
+		from song_rank_prior_cache p
+		where song.id = $1`
+
+	res, err := conn(ctx, r.db).Exec(
+		ctx,
+		query,
+		songID,
+		d.Likes,
+		d.Dislikes,
+		d.ScoreCount,
+		d.ScoreSum,
+		d.Histogram[:],
+	)
+	if err != nil {
+		r.logger.Error("failed to apply rating delta", "song_id", songID, "error", err)
 		return err
 	}
-	if !exists {
+
+	if res.RowsAffected() == 0 {
 		return fmt.Errorf("song with id %d does not exist", songID)
 	}
 
-	likeCount, dislikeCount, rating, err := r.GetSongRating(ctx, songID)
+	return nil
+}
+
+// RefreshRankings пересчитывает ранги всех песен по текущему априору
+func (r *SongRepository) RefreshRankings(ctx context.Context) (int64, error) {
+	res, err := r.db.Exec(ctx, songRankRefresh)
 	if err != nil {
-		return err
+		r.logger.Error("failed to refresh song rankings", "error", err)
+		return 0, err
 	}
 
-	query := `
-		UPDATE song SET
-      like_count = $1,
-      dislike_count = $2,
-      rating = $3,
-      updated_at = NOW()
-    WHERE id = $4`
-	_, err = r.db.Exec(ctx, query, likeCount, dislikeCount, rating, songID)
-
-	return err
+	return res.RowsAffected(), nil
 }
 
 func (r *SongRepository) UpdateSong(
@@ -456,6 +624,18 @@ func (r *SongRepository) UpdateSong(
 		argPos++
 	}
 
+	if update.ISRC != nil {
+		fields = append(fields, fmt.Sprintf("isrc=nullif($%d, '')", argPos))
+		args = append(args, *update.ISRC)
+		argPos++
+	}
+
+	if update.MBID != nil {
+		fields = append(fields, fmt.Sprintf("mbid=nullif($%d, '')::uuid", argPos))
+		args = append(args, *update.MBID)
+		argPos++
+	}
+
 	if len(fields) == 0 {
 		return nil
 	}
@@ -469,13 +649,30 @@ func (r *SongRepository) UpdateSong(
 
 	query := fmt.Sprintf("update song set %s %s", strings.Join(fields, ", "), whereClause)
 
-	res, err := r.db.Exec(
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(
 		ctx,
 		query,
 		args...,
 	)
 	if err != nil {
 		r.logger.Error("failed to update song", "id", id, "error", err)
+		return wrapUniqueViolation(err)
+	}
+
+	if update.Title != nil && res.RowsAffected() > 0 {
+		if _, err = assignSlug(ctx, tx, slugSong, id, *update.Title); err != nil {
+			r.logger.Error("failed to assign song slug", "id", id, "error", err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
 		return err
 	}
 
@@ -487,12 +684,36 @@ func (r *SongRepository) UpdateSong(
 	return nil
 }
 
+// DeleteSong переносит песню в корзину, окончательно её удаляет TrashRepository.Purge
 func (r *SongRepository) DeleteSong(ctx context.Context, id int) error {
-	query := `DELETE FROM song WHERE id=$1`
+	query := `update song set deleted_at = now() where id=$1 and deleted_at is null`
 
-	if _, err := r.db.Exec(ctx, query, id); err != nil {
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to delete song", "id", id, "error", err)
 		return err
 	}
 
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// RestoreSong возвращает song из корзины
+func (r *SongRepository) RestoreSong(ctx context.Context, id int) error {
+	query := `update song set deleted_at = null where id=$1 and deleted_at is not null`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to restore song", "id", id, "error", err)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return trash.ErrNotInTrash
+	}
+
 	return nil
 }
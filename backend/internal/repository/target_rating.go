@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maYkiss56/tunes/internal/domain/rating"
+	"github.com/maYkiss56/tunes/internal/domain/review"
+)
+
+// Счётчики рецензий альбомов и исполнителей устроены как у песен,
+// но без предрасчитанных рангов
+
+// ratingSummaryColumns колонки сводки строки alias в порядке summaryDest
+func ratingSummaryColumns(alias string) string {
+	return fmt.Sprintf("%[1]s.like_count, %[1]s.dislike_count, %[1]s.rating, %[1]s.score_avg, %[1]s.score_count", alias)
+}
+
+func summaryDest(s *rating.Summary) []any {
+	return []any{&s.Likes, &s.Dislikes, &s.Rating, &s.ScoreAverage, &s.ScoreCount}
+}
+
+// targetCountersActual агрегирует валидные рецензии, ссылающиеся колонкой column
+// на строки x из table, подходящие под where
+func targetCountersActual(table, column, where string) string {
+	return `
+		select x.id,
+		count(r.id) filter (where r.is_like = true) as like_count,
+		count(r.id) filter (where r.is_like = false) as dislike_count,
+		count(r.score) as score_count,
+		coalesce(sum(r.score), 0) as score_sum,
+		round(avg(r.score), 2) as score_avg,
+		` + scoreHistogram + ` as score_histogram
+		from ` + table + ` x
+		left join review r on r.` + column + ` = x.id and ` + reviewCounted + `
+		where ` + where + `
+		group by x.id`
+}
+
+// targetCountersRecompute перезаписывает счётчики строк table, подходящих под where,
+// агрегатами валидных рецензий, ссылающихся на них колонкой column
+func targetCountersRecompute(table, column, where string) string {
+	return `
+		update ` + table + ` t set
+			like_count = c.like_count,
+			dislike_count = c.dislike_count,
+			rating = c.like_count - c.dislike_count,
+			score_count = c.score_count,
+			score_sum = c.score_sum,
+			score_avg = c.score_avg,
+			score_histogram = c.score_histogram
+		from (` + targetCountersActual(table, column, where) + `) c
+		where t.id = c.id`
+}
+
+// targetRatingDelta прибавляет изменение рецензии к счётчикам строки table с id $1
+func targetRatingDelta(table string) string {
+	return `
+		update ` + table + ` set
+			like_count = like_count + $2,
+			dislike_count = dislike_count + $3,
+			rating = rating + $2 - $3,
+			score_count = score_count + $4,
+			score_sum = score_sum + $5,
+			score_avg = round((score_sum + $5)::numeric / nullif(score_count + $4, 0), 2),
+			score_histogram = array(
+				select a + b from unnest(score_histogram, $6::int[]) with ordinality t(a, b, i) order by i
+			)
+		where id = $1`
+}
+
+func applyTargetRatingDelta(ctx context.Context, q querier, table string, id int, d review.RatingDelta) error {
+	res, err := q.Exec(
+		ctx,
+		targetRatingDelta(table),
+		id,
+		d.Likes,
+		d.Dislikes,
+		d.ScoreCount,
+		d.ScoreSum,
+		d.Histogram[:],
+	)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return fmt.Errorf("%s with id %d does not exist", table, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/trash"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type TrashRepository struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewTrashRepository(db *pgxpool.Pool, logger *logger.Logger) *TrashRepository {
+	return &TrashRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListTrash возвращает удалённые сущности, entity == "" означает все типы
+func (r *TrashRepository) ListTrash(ctx context.Context, entity string) ([]domain.Item, error) {
+	query := `
+		select entity, id, title, deleted_at from (
+			select 'song' as entity, id, title, deleted_at from song where deleted_at is not null
+			union all
+			select 'album', id, title, deleted_at from album where deleted_at is not null
+			union all
+			select 'artist', id, nickname, deleted_at from artist where deleted_at is not null
+			union all
+			select 'genre', id, title, deleted_at from genre where deleted_at is not null
+		) t
+		where $1 = '' or entity = $1
+		order by deleted_at desc`
+
+	rows, err := r.db.Query(ctx, query, entity)
+	if err != nil {
+		r.logger.Error("failed to list trash", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]domain.Item, 0)
+
+	for rows.Next() {
+		var item domain.Item
+		if err = rows.Scan(&item.Entity, &item.ID, &item.Title, &item.DeletedAt); err != nil {
+			r.logger.Error("failed to scan rows", "error", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Purge окончательно удаляет сущности, лежащие в корзине дольше before.
+// Родительская сущность удаляется только когда на неё не осталось ссылок,
+// иначе она остаётся в корзине до следующего запуска.
+func (r *TrashRepository) Purge(ctx context.Context, before time.Time) (*domain.PurgeResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	res := &domain.PurgeResult{}
+
+	steps := []struct {
+		query    string
+		affected *int64
+	}{
+		{`delete from review where song_id in
+			(select id from song where deleted_at < $1)`, &res.Reviews},
+		{`delete from song where deleted_at < $1`, &res.Songs},
+		{`delete from album a where a.deleted_at < $1
+			and not exists (select 1 from song s where s.album_id = a.id)`, &res.Albums},
+		{`delete from artist ar where ar.deleted_at < $1
+			and not exists (select 1 from album a where a.artist_id = ar.id)
+			and not exists (select 1 from song s where s.artist_id = ar.id)`, &res.Artists},
+		{`delete from genre g where g.deleted_at < $1
+			and not exists (select 1 from song s where s.genre_id = g.id)`, &res.Genres},
+	}
+
+	for _, step := range steps {
+		tag, err := tx.Exec(ctx, step.query, before)
+		if err != nil {
+			r.logger.Error("failed to purge trash", "error", err)
+			return nil, err
+		}
+		*step.affected = tag.RowsAffected()
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type txKey struct{}
+
+// querier общий интерфейс пула и транзакции
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn возвращает транзакцию, открытую UnitOfWork.Do, или пул, если её нет
+func conn(ctx context.Context, db *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// begin открывает транзакцию репозитория; внутри UnitOfWork.Do она становится
+// точкой сохранения общей транзакции и фиксируется только вместе с ней
+func begin(ctx context.Context, db *pgxpool.Pool) (pgx.Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Begin(ctx)
+	}
+	return db.Begin(ctx)
+}
+
+// UnitOfWork объединяет вызовы нескольких репозиториев в одну транзакцию
+type UnitOfWork struct {
+	db     *pgxpool.Pool
+	logger *logger.Logger
+}
+
+func NewUnitOfWork(db *pgxpool.Pool, logger *logger.Logger) *UnitOfWork {
+	return &UnitOfWork{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Do выполняет fn в транзакции: методы репозиториев, вызванные с переданным
+// в fn контекстом, работают в ней. Ошибка fn откатывает транзакцию.
+// Вложенный Do выполняется в уже открытой транзакции.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		u.logger.Error("failed to begin transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
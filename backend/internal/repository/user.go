@@ -78,19 +78,23 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*dom
 }
 
 func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
-	query := `select id, email, username, password_hash,avatar_url, role_id from users where id=$1`
+	query := `select id, email, username, password_hash,avatar_url, role_id,
+		follower_count, following_count from users where id=$1`
 
 	var (
-		userID       int
-		userEmail    string
-		userUsername string
-		userPassword string
-		avatar_url   string
-		userRoleID   int
+		userID         int
+		userEmail      string
+		userUsername   string
+		userPassword   string
+		avatar_url     string
+		userRoleID     int
+		followerCount  int
+		followingCount int
 	)
 
 	err := r.db.QueryRow(ctx, query, id).
-		Scan(&userID, &userEmail, &userUsername, &userPassword, &avatar_url, &userRoleID)
+		Scan(&userID, &userEmail, &userUsername, &userPassword, &avatar_url, &userRoleID,
+			&followerCount, &followingCount)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			r.logger.Error("user not found", "email", id, "error", err)
@@ -101,32 +105,40 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id int) (*domain.User,
 	}
 
 	return &domain.User{
-		ID:           userID,
-		Email:        userEmail,
-		Username:     userUsername,
-		PasswordHash: userPassword,
-		AvatarURL:    avatar_url,
-		RoleID:       userRoleID,
+		ID:             userID,
+		Email:          userEmail,
+		Username:       userUsername,
+		PasswordHash:   userPassword,
+		AvatarURL:      avatar_url,
+		RoleID:         userRoleID,
+		FollowerCount:  followerCount,
+		FollowingCount: followingCount,
 	}, nil
 }
 
-func (r *UserRepository) GetTopReviewers(ctx context.Context) ([]dto.TopResponse, error) {
+func (r *UserRepository) GetTopReviewers(ctx context.Context, by string) ([]dto.TopResponse, error) {
+	order := "review_count DESC"
+	if by == domain.TopByHelpful {
+		order = "helpful_count DESC, review_count DESC"
+	}
+
 	query := `
         SELECT
             u.id,
             u.username,
             u.avatar_url,
-            COUNT(r.id) AS review_count
+            COUNT(r.id) AS review_count,
+            COALESCE(SUM(r.helpful_count), 0) AS helpful_count
         FROM
             users u
         JOIN
             review r ON u.id = r.user_id
         WHERE
-            r.is_valid = true
+            ` + reviewCounted + `
         GROUP BY
             u.id
         ORDER BY
-            review_count DESC`
+            ` + order
 
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
@@ -142,6 +154,7 @@ func (r *UserRepository) GetTopReviewers(ctx context.Context) ([]dto.TopResponse
 			&reviewer.Username,
 			&reviewer.AvatarURL,
 			&reviewer.ReviewCount,
+			&reviewer.HelpfulCount,
 		)
 		if err != nil {
 			return nil, err
@@ -2,9 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
 
 	domain "github.com/maYkiss56/tunes/internal/domain/album"
 	"github.com/maYkiss56/tunes/internal/domain/album/dto"
+	"github.com/maYkiss56/tunes/internal/domain/audit"
+	"github.com/maYkiss56/tunes/internal/domain/library"
+	libraryDTO "github.com/maYkiss56/tunes/internal/domain/library/dto"
+	"github.com/maYkiss56/tunes/internal/domain/slug"
 	"github.com/maYkiss56/tunes/internal/logger"
 )
 
@@ -14,45 +21,165 @@ type AlbumRepository interface {
 	GetAlbumByID(ctx context.Context, id int) (*dto.Response, error)
 	UpdateAlbum(ctx context.Context, id int, update dto.UpdateAlbumRequest) error
 	DeleteAlbum(ctx context.Context, id int) error
+	RestoreAlbum(ctx context.Context, id int) error
+	GetAlbumBySlug(ctx context.Context, slug string) (*dto.Response, error)
+	GetAlbumSlugRedirect(ctx context.Context, slug string) (string, error)
 }
 
 type AlbumService struct {
-	repo   AlbumRepository
-	logger *logger.Logger
+	repo    AlbumRepository
+	library LibraryStatuses
+	auditor Auditor
+	uow     UnitOfWork
+	logger  *logger.Logger
 }
 
-func NewAlbumService(repo AlbumRepository, logger *logger.Logger) *AlbumService {
+func NewAlbumService(
+	repo AlbumRepository,
+	library LibraryStatuses,
+	auditor Auditor,
+	uow UnitOfWork,
+	logger *logger.Logger,
+) *AlbumService {
 	return &AlbumService{
-		repo:   repo,
-		logger: logger,
+		repo:    repo,
+		library: library,
+		auditor: auditor,
+		uow:     uow,
+		logger:  logger,
 	}
 }
 
+// CreateAlbum сохраняет альбом и запись журнала аудита в одной транзакции
 func (s *AlbumService) CreateAlbum(ctx context.Context, album *domain.Album) error {
-	return s.repo.CreateAlbum(ctx, album)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateAlbum(ctx, album); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityAlbum, album.ID, audit.ActionCreate, nil, s.auditState(ctx, album.ID))
+	})
 }
 
-func (s *AlbumService) GetAllAlbums(ctx context.Context) ([]dto.Response, error) {
+func (s *AlbumService) GetAllAlbums(ctx context.Context, viewerID int) ([]dto.Response, error) {
 	albums, err := s.repo.GetAllAlbums(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return albums, nil
+	refs := make([]*dto.Response, 0, len(albums))
+	for i := range albums {
+		refs = append(refs, &albums[i])
+	}
+
+	return albums, s.attachLibrary(ctx, viewerID, refs)
 }
 
 func (s *AlbumService) GetAlbumByID(ctx context.Context, id int) (*dto.Response, error) {
 	return s.repo.GetAlbumByID(ctx, id)
 }
 
+// GetAlbumByRef принимает id или слаг. Для слага из истории
+// возвращается *slug.MovedError с текущим слагом.
+func (s *AlbumService) GetAlbumByRef(ctx context.Context, viewerID int, ref string) (*dto.Response, error) {
+	var (
+		res *dto.Response
+		err error
+	)
+	id, isID := slug.ParseID(ref)
+	if isID {
+		res, err = s.repo.GetAlbumByID(ctx, id)
+	} else {
+		res, err = s.repo.GetAlbumBySlug(ctx, ref)
+	}
+	if err == nil {
+		return res, s.attachLibrary(ctx, viewerID, []*dto.Response{res})
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if isID {
+		return nil, domain.ErrNotFound
+	}
+
+	current, err := s.repo.GetAlbumSlugRedirect(ctx, ref)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return nil, &slug.MovedError{Slug: current}
+}
+
 func (s *AlbumService) UpdateAlbum(
 	ctx context.Context,
 	id int,
 	update dto.UpdateAlbumRequest,
 ) error {
-	return s.repo.UpdateAlbum(ctx, id, update)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, id)
+
+		if err := s.repo.UpdateAlbum(ctx, id, update); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityAlbum, id, audit.ActionUpdate, before, s.auditState(ctx, id))
+	})
 }
 
 func (s *AlbumService) DeleteAlbum(ctx context.Context, id int) error {
-	return s.repo.DeleteAlbum(ctx, id)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, id)
+
+		if err := s.repo.DeleteAlbum(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityAlbum, id, audit.ActionDelete, before, nil)
+	})
+}
+
+func (s *AlbumService) RestoreAlbum(ctx context.Context, id int) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreAlbum(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityAlbum, id, audit.ActionRestore, nil, s.auditState(ctx, id))
+	})
+}
+
+// attachLibrary добавляет к альбомам отметки библиотеки зрителя одним запросом
+func (s *AlbumService) attachLibrary(ctx context.Context, viewerID int, albums []*dto.Response) error {
+	if viewerID == 0 || len(albums) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(albums))
+	for _, a := range albums {
+		ids = append(ids, a.ID)
+	}
+
+	statuses, err := s.library.GetStatuses(ctx, viewerID, library.KindAlbum, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range albums {
+		status := libraryDTO.ToStatusResponse(statuses[a.ID])
+		a.Library = &status
+	}
+
+	return nil
+}
+
+// auditState возвращает текущее состояние альбома для журнала аудита
+func (s *AlbumService) auditState(ctx context.Context, id int) any {
+	album, err := s.repo.GetAlbumByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return album
 }
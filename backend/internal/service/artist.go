@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
 
 	domain "github.com/maYkiss56/tunes/internal/domain/artist"
 	"github.com/maYkiss56/tunes/internal/domain/artist/dto"
+	"github.com/maYkiss56/tunes/internal/domain/audit"
+	"github.com/maYkiss56/tunes/internal/domain/slug"
 	"github.com/maYkiss56/tunes/internal/logger"
 )
 
@@ -14,22 +19,58 @@ type ArtistRepository interface {
 	GetArtistByID(ctx context.Context, id int) (*domain.Artist, error)
 	UpdateArtist(ctx context.Context, id int, update dto.UpdateArtistRequest) error
 	DeleteArtist(ctx context.Context, id int) error
+	RestoreArtist(ctx context.Context, id int) error
+	GetArtistRedirect(ctx context.Context, id int) (int, error)
+	GetDuplicateCandidates(ctx context.Context) ([]domain.Candidate, error)
+	MergeArtists(ctx context.Context, sourceID, targetID int) (*domain.MergeResult, error)
+	GetArtistBySlug(ctx context.Context, slug string) (*domain.Artist, error)
+	GetArtistSlugRedirect(ctx context.Context, slug string) (string, error)
+}
+
+// BioMentioner сохраняет упоминания из биографии исполнителя
+type BioMentioner interface {
+	SyncArtist(ctx context.Context, artistID int, bio string) error
 }
 
 type ArtistService struct {
-	repo   ArtistRepository
-	logger *logger.Logger
+	repo     ArtistRepository
+	mentions BioMentioner
+	auditor  Auditor
+	uow      UnitOfWork
+	logger   *logger.Logger
 }
 
-func NewArtistService(repo ArtistRepository, logger *logger.Logger) *ArtistService {
+func NewArtistService(
+	repo ArtistRepository,
+	mentions BioMentioner,
+	auditor Auditor,
+	uow UnitOfWork,
+	logger *logger.Logger,
+) *ArtistService {
 	return &ArtistService{
-		repo:   repo,
-		logger: logger,
+		repo:     repo,
+		mentions: mentions,
+		auditor:  auditor,
+		uow:      uow,
+		logger:   logger,
 	}
 }
 
+// CreateArtist сохраняет исполнителя и запись журнала аудита в одной транзакции
 func (s *ArtistService) CreateArtist(ctx context.Context, artist *domain.Artist) error {
-	return s.repo.CreateArtist(ctx, artist)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateArtist(ctx, artist); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityArtist, artist.ID, audit.ActionCreate, nil, s.auditState(ctx, artist.ID))
+	})
+	if err != nil {
+		return err
+	}
+	s.syncMentions(ctx, artist.ID, artist.BIO)
+
+	return nil
 }
 
 func (s *ArtistService) GetAllArtists(ctx context.Context) ([]*domain.Artist, error) {
@@ -41,8 +82,90 @@ func (s *ArtistService) GetAllArtists(ctx context.Context) ([]*domain.Artist, er
 	return artists, nil
 }
 
+// GetArtistByID для влитого артиста возвращает *domain.MovedError
+// с идентификатором оставшегося
 func (s *ArtistService) GetArtistByID(ctx context.Context, id int) (*domain.Artist, error) {
-	return s.repo.GetArtistByID(ctx, id)
+	artist, err := s.repo.GetArtistByID(ctx, id)
+	if err == nil {
+		return artist, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	toID, redirectErr := s.repo.GetArtistRedirect(ctx, id)
+	if redirectErr != nil {
+		if errors.Is(redirectErr, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, redirectErr
+	}
+
+	return nil, &domain.MovedError{ID: toID}
+}
+
+// GetArtistByRef принимает id или слаг. Для слага из истории
+// возвращается *slug.MovedError с текущим слагом.
+func (s *ArtistService) GetArtistByRef(ctx context.Context, ref string) (*domain.Artist, error) {
+	if id, ok := slug.ParseID(ref); ok {
+		return s.GetArtistByID(ctx, id)
+	}
+
+	artist, err := s.repo.GetArtistBySlug(ctx, ref)
+	if err == nil {
+		return artist, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	current, err := s.repo.GetArtistSlugRedirect(ctx, ref)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return nil, &slug.MovedError{Slug: current}
+}
+
+// FindDuplicates считает близость имён только для пар, отобранных базой по триграммам
+func (s *ArtistService) FindDuplicates(ctx context.Context, threshold float64) ([]domain.Duplicate, error) {
+	candidates, err := s.repo.GetDuplicateCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.FindDuplicates(candidates, threshold), nil
+}
+
+// MergeArtists вливает source в target и записывает слияние в журнал аудита
+func (s *ArtistService) MergeArtists(ctx context.Context, sourceID, targetID int) (*domain.MergeResult, error) {
+	if sourceID == targetID {
+		return nil, domain.ErrMergeSelf
+	}
+
+	var res *domain.MergeResult
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, sourceID)
+
+		var err error
+		if res, err = s.repo.MergeArtists(ctx, sourceID, targetID); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityArtist, sourceID, audit.ActionMerge, before, s.auditState(ctx, targetID))
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		s.logger.Error("failed to merge artists", "source", sourceID, "target", targetID, "error", err)
+		return nil, err
+	}
+
+	return res, nil
 }
 
 func (s *ArtistService) UpdateArtist(
@@ -50,9 +173,60 @@ func (s *ArtistService) UpdateArtist(
 	id int,
 	update dto.UpdateArtistRequest,
 ) error {
-	return s.repo.UpdateArtist(ctx, id, update)
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, id)
+
+		if err := s.repo.UpdateArtist(ctx, id, update); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityArtist, id, audit.ActionUpdate, before, s.auditState(ctx, id))
+	})
+	if err != nil {
+		return err
+	}
+	if update.BIO != nil {
+		s.syncMentions(ctx, id, *update.BIO)
+	}
+
+	return nil
 }
 
 func (s *ArtistService) DeleteArtist(ctx context.Context, id int) error {
-	return s.repo.DeleteArtist(ctx, id)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, id)
+
+		if err := s.repo.DeleteArtist(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityArtist, id, audit.ActionDelete, before, nil)
+	})
+}
+
+func (s *ArtistService) RestoreArtist(ctx context.Context, id int) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreArtist(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityArtist, id, audit.ActionRestore, nil, s.auditState(ctx, id))
+	})
+}
+
+// syncMentions обновляет упоминания биографии; исполнитель уже сохранён,
+// поэтому ошибка только записывается в журнал
+func (s *ArtistService) syncMentions(ctx context.Context, id int, bio string) {
+	if err := s.mentions.SyncArtist(ctx, id, bio); err != nil {
+		s.logger.Error("failed to sync artist mentions", "id", id, "error", err)
+	}
+}
+
+// auditState возвращает текущее состояние артиста для журнала аудита
+func (s *ArtistService) auditState(ctx context.Context, id int) any {
+	artist, err := s.repo.GetArtistByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return dto.ToResponse(*artist)
 }
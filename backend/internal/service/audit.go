@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/audit"
+	"github.com/maYkiss56/tunes/internal/domain/audit/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+)
+
+// Auditor записывает изменения каталога и действия модерации в журнал
+type Auditor interface {
+	Record(ctx context.Context, entity string, entityID int, action string, before, after any) error
+}
+
+type AuditRepository interface {
+	CreateEntry(ctx context.Context, entry *domain.Entry) error
+	ListEntries(ctx context.Context, filter domain.Filter) ([]domain.Entry, error)
+}
+
+type AuditService struct {
+	repo   AuditRepository
+	logger *logger.Logger
+}
+
+func NewAuditService(repo AuditRepository, logger *logger.Logger) *AuditService {
+	return &AuditService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Record берёт автора изменения из сессии в контексте. Вызывается в транзакции
+// изменения, поэтому ошибка записи журнала возвращается и откатывает само изменение
+func (s *AuditService) Record(ctx context.Context, entity string, entityID int, action string, before, after any) error {
+	var actorID int
+	var actorEmail string
+	if sess := session.FromContext(ctx); sess != nil {
+		actorID = sess.UserID
+		actorEmail = sess.UserEmail
+	}
+
+	entry, err := domain.NewEntry(actorID, actorEmail, entity, entityID, action, before, after)
+	if err != nil {
+		s.logger.Error("failed to build audit entry",
+			"entity", entity, "id", entityID, "action", action, "error", err)
+		return err
+	}
+
+	if err = s.repo.CreateEntry(ctx, entry); err != nil {
+		s.logger.Error("failed to record audit entry",
+			"entity", entity, "id", entityID, "action", action, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AuditService) ListEntries(ctx context.Context, filter domain.Filter) ([]dto.Response, error) {
+	entries, err := s.repo.ListEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.Response, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, dto.ToResponse(e))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/maYkiss56/tunes/internal/domain/audit"
+	domain "github.com/maYkiss56/tunes/internal/domain/comment"
+	"github.com/maYkiss56/tunes/internal/domain/comment/dto"
+	"github.com/maYkiss56/tunes/internal/domain/moderation"
+	"github.com/maYkiss56/tunes/internal/domain/notification"
+	reviewDomain "github.com/maYkiss56/tunes/internal/domain/review"
+	reviewDTO "github.com/maYkiss56/tunes/internal/domain/review/dto"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
+)
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, c *domain.Comment) error
+	GetCommentByID(ctx context.Context, id int) (*domain.Comment, error)
+	GetThread(ctx context.Context, reviewID, limit, offset int) ([]domain.Comment, int, error)
+	GetCommentsByStatus(ctx context.Context, status moderation.Status, limit, offset int) ([]domain.Comment, error)
+	UpdateCommentBody(ctx context.Context, id int, body string) error
+	DeleteComment(ctx context.Context, id int) error
+	ModerateComment(ctx context.Context, id int, status moderation.Status, moderatorID int, reason string) error
+}
+
+// ReviewGetter проверяет, что рецензия существует и видна пользователю
+type ReviewGetter interface {
+	GetReviewByID(ctx context.Context, id int) (*reviewDTO.Response, error)
+}
+
+type CommentService struct {
+	repo     CommentRepository
+	reviews  ReviewGetter
+	notifier Notifier
+	auditor  Auditor
+	uow      UnitOfWork
+	logger   *logger.Logger
+}
+
+func NewCommentService(
+	repo CommentRepository,
+	reviews ReviewGetter,
+	notifier Notifier,
+	auditor Auditor,
+	uow UnitOfWork,
+	logger *logger.Logger,
+) *CommentService {
+	return &CommentService{
+		repo:     repo,
+		reviews:  reviews,
+		notifier: notifier,
+		auditor:  auditor,
+		uow:      uow,
+		logger:   logger,
+	}
+}
+
+// CreateComment добавляет комментарий к рецензии, parentID != nil делает его ответом
+func (s *CommentService) CreateComment(ctx context.Context, c *domain.Comment, parentID *int) (*dto.Response, error) {
+	review, err := s.reviews.GetReviewByID(ctx, c.ReviewID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, reviewDomain.ErrNotFound) {
+			return nil, domain.ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	// ответ получает автор родительского комментария, корневой комментарий автор рецензии
+	recipientID := review.User.ID
+
+	if parentID != nil {
+		parent, err := s.repo.GetCommentByID(ctx, *parentID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, domain.ErrParentNotFound
+			}
+			return nil, err
+		}
+		if err = c.ReplyTo(parent); err != nil {
+			return nil, err
+		}
+		recipientID = parent.UserID
+	}
+
+	if err := s.repo.CreateComment(ctx, c); err != nil {
+		return nil, err
+	}
+
+	if c.IsVisible() && recipientID != c.UserID {
+		s.notifyReply(ctx, recipientID, c)
+	}
+
+	return s.GetCommentByID(ctx, c.ID)
+}
+
+// notifyReply не отменяет сохранённый комментарий, если уведомление не записалось
+func (s *CommentService) notifyReply(ctx context.Context, recipientID int, c *domain.Comment) {
+	payload := notification.ReplyPayload{
+		ReviewID:  c.ReviewID,
+		CommentID: c.ID,
+		ParentID:  c.ParentID,
+	}
+
+	n, err := notification.New(recipientID, c.UserID, notification.TypeReply, payload)
+	if err == nil {
+		err = s.notifier.Notify(ctx, n)
+	}
+	if err != nil {
+		s.logger.Error("failed to notify about reply", "comment_id", c.ID, "error", err)
+	}
+}
+
+func (s *CommentService) GetCommentByID(ctx context.Context, id int) (*dto.Response, error) {
+	c, err := s.getComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToResponse(*c)
+	return &res, nil
+}
+
+// GetThread возвращает страницу корневых комментариев рецензии с деревьями ответов
+func (s *CommentService) GetThread(ctx context.Context, reviewID int, req dto.ListRequest) (*dto.ThreadResponse, error) {
+	if _, err := s.reviews.GetReviewByID(ctx, reviewID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, reviewDomain.ErrNotFound) {
+			return nil, domain.ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	comments, total, err := s.repo.GetThread(ctx, reviewID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToThreadResponse(domain.BuildTree(comments), total, req.Limit, req.Offset)
+	return &res, nil
+}
+
+// GetModerationQueue возвращает комментарии с указанным статусом для модераторов
+func (s *CommentService) GetModerationQueue(
+	ctx context.Context,
+	status moderation.Status,
+	req dto.ListRequest,
+) ([]dto.Response, error) {
+	comments, err := s.repo.GetCommentsByStatus(ctx, status, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.Response, 0, len(comments))
+	for _, c := range comments {
+		res = append(res, dto.ToResponse(c))
+	}
+
+	return res, nil
+}
+
+// UpdateComment меняет текст; править можно только свой комментарий
+func (s *CommentService) UpdateComment(ctx context.Context, id int, body string) (*dto.Response, error) {
+	c, err := s.getComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.IsDeleted() {
+		return nil, domain.ErrNotFound
+	}
+	if sess := session.FromContext(ctx); sess == nil || sess.UserID != c.UserID {
+		return nil, domain.ErrForbidden
+	}
+
+	if err = s.repo.UpdateCommentBody(ctx, id, body); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetCommentByID(ctx, id)
+}
+
+// DeleteComment удаляет комментарий автора; администратор может удалить
+// любой, такое удаление попадает в журнал аудита как модерация
+func (s *CommentService) DeleteComment(ctx context.Context, id int) error {
+	c, err := s.getComment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	sess := session.FromContext(ctx)
+	if sess == nil {
+		return domain.ErrForbidden
+	}
+	isModeration := sess.UserID != c.UserID
+	if isModeration && sess.UserRoleID != users.AdminRoleID {
+		return domain.ErrForbidden
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteComment(ctx, id); err != nil {
+			return err
+		}
+		if !isModeration {
+			return nil
+		}
+
+		return s.auditor.Record(ctx, audit.EntityComment, id, audit.ActionModerate, dto.ToResponse(*c), nil)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ModerateComment выставляет статус модерации; отклонённые и ожидающие
+// комментарии вместе с ответами пропадают из ветки
+func (s *CommentService) ModerateComment(
+	ctx context.Context,
+	id int,
+	req dto.ModerateCommentRequest,
+) (*dto.Response, error) {
+	before, err := s.getComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var moderatorID int
+	if sess := session.FromContext(ctx); sess != nil {
+		moderatorID = sess.UserID
+	}
+
+	var res *dto.Response
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		err := s.repo.ModerateComment(ctx, id, moderation.Status(req.Status), moderatorID, req.Reason)
+		if err != nil {
+			return err
+		}
+
+		if res, err = s.GetCommentByID(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityComment, id, audit.ActionModerate, dto.ToResponse(*before), res)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *CommentService) getComment(ctx context.Context, id int) (*domain.Comment, error) {
+	c, err := s.repo.GetCommentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
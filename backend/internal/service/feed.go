@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/maYkiss56/tunes/internal/domain/feed"
+	"github.com/maYkiss56/tunes/internal/domain/feed/dto"
+	"github.com/maYkiss56/tunes/internal/domain/playlist"
+	playlistDTO "github.com/maYkiss56/tunes/internal/domain/playlist/dto"
+	reviewDTO "github.com/maYkiss56/tunes/internal/domain/review/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type FeedRepository interface {
+	GetFeed(ctx context.Context, userID int, cursor *feed.Cursor, limit int) ([]feed.Item, error)
+}
+
+// FeedReviewRepository загружает рецензии, на которые ссылаются события ленты
+type FeedReviewRepository interface {
+	GetReviewsByIDs(ctx context.Context, ids []int) ([]reviewDTO.Response, error)
+}
+
+// FeedListRepository загружает списки, на которые ссылаются события ленты
+type FeedListRepository interface {
+	GetPublicListsByIDs(ctx context.Context, ids []int) ([]playlist.List, error)
+}
+
+type FeedService struct {
+	repo    FeedRepository
+	reviews FeedReviewRepository
+	lists   FeedListRepository
+	cache   *feedCache
+	logger  *logger.Logger
+}
+
+func NewFeedService(
+	repo FeedRepository,
+	reviews FeedReviewRepository,
+	lists FeedListRepository,
+	cacheTTL time.Duration,
+	cacheSize int,
+	logger *logger.Logger,
+) *FeedService {
+	return &FeedService{
+		repo:    repo,
+		reviews: reviews,
+		lists:   lists,
+		cache:   newFeedCache(cacheTTL, cacheSize),
+		logger:  logger,
+	}
+}
+
+// GetFeed возвращает страницу ленты подписок пользователя. Первая страница
+// кэшируется на CacheTTL: свежие события подписок появляются с этой задержкой,
+// а подписка, отписка и блокировка сбрасывают кэш сразу.
+func (s *FeedService) GetFeed(ctx context.Context, userID int, req dto.Request) (*dto.PageResponse, error) {
+	if req.Cursor == nil {
+		if page, ok := s.cache.get(userID, req.Limit); ok {
+			return page, nil
+		}
+	}
+
+	items, err := s.repo.GetFeed(ctx, userID, req.Cursor, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewIDs := make([]int, 0, len(items))
+	listIDs := make([]int, 0)
+	for _, item := range items {
+		if item.Kind == feed.KindList {
+			listIDs = append(listIDs, item.ObjectID)
+		} else {
+			reviewIDs = append(reviewIDs, item.ObjectID)
+		}
+	}
+
+	reviews, err := s.reviews.GetReviewsByIDs(ctx, reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewsByID := make(map[int]reviewDTO.Response, len(reviews))
+	for _, r := range reviews {
+		reviewsByID[r.ID] = r
+	}
+
+	lists, err := s.lists.GetPublicListsByIDs(ctx, listIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	listsByID := make(map[int]playlistDTO.Response, len(lists))
+	for _, l := range lists {
+		listsByID[l.ID] = playlistDTO.ToResponse(l)
+	}
+
+	page := &dto.PageResponse{Items: make([]dto.ItemResponse, 0, len(items))}
+	for _, item := range items {
+		res := dto.ToItemResponse(item)
+
+		// рецензию или список могли удалить между запросами
+		if item.Kind == feed.KindList {
+			list, ok := listsByID[item.ObjectID]
+			if !ok {
+				continue
+			}
+			res.List = &list
+		} else {
+			review, ok := reviewsByID[item.ObjectID]
+			if !ok {
+				continue
+			}
+			res.Review = &review
+		}
+
+		page.Items = append(page.Items, res)
+	}
+
+	// курсор берётся по последнему событию, даже если его рецензия отброшена
+	if len(items) == req.Limit {
+		page.NextCursor = feed.After(items[len(items)-1]).Encode()
+	}
+
+	if req.Cursor == nil {
+		s.cache.put(userID, req.Limit, page)
+	}
+
+	return page, nil
+}
+
+// Invalidate сбрасывает кэш ленты пользователя после изменения его подписок
+func (s *FeedService) Invalidate(userID int) {
+	s.cache.drop(userID)
+}
+
+// feedCache хранит первые страницы лент. При переполнении сначала
+// вытесняются устаревшие записи, затем произвольные.
+type feedCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[int]feedEntry
+}
+
+type feedEntry struct {
+	limit   int
+	page    *dto.PageResponse
+	expires time.Time
+}
+
+func newFeedCache(ttl time.Duration, size int) *feedCache {
+	return &feedCache{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[int]feedEntry),
+	}
+}
+
+func (c *feedCache) get(userID, limit int) (*dto.PageResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[userID]
+	if !ok || e.limit != limit || time.Now().After(e.expires) {
+		return nil, false
+	}
+
+	return e.page, true
+}
+
+func (c *feedCache) put(userID, limit int, page *dto.PageResponse) {
+	if c.ttl <= 0 || c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[userID]; !ok && len(c.entries) >= c.size {
+		c.evict()
+	}
+
+	c.entries[userID] = feedEntry{limit: limit, page: page, expires: time.Now().Add(c.ttl)}
+}
+
+func (c *feedCache) drop(userID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, userID)
+}
+
+// evict освобождает место под одну запись; вызывается под mu
+func (c *feedCache) evict() {
+	now := time.Now()
+	for id, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, id)
+		}
+	}
+
+	for id := range c.entries {
+		if len(c.entries) < c.size {
+			return
+		}
+		delete(c.entries, id)
+	}
+}
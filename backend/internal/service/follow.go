@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/follow"
+	"github.com/maYkiss56/tunes/internal/domain/follow/dto"
+	"github.com/maYkiss56/tunes/internal/domain/notification"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type FollowRepository interface {
+	GetProfile(ctx context.Context, userID int) (*users.User, error)
+	GetRelation(ctx context.Context, viewerID, userID int) (domain.Relation, error)
+	LockUsers(ctx context.Context, a, b int) (bool, error)
+	IsBlockedEither(ctx context.Context, a, b int) (bool, error)
+	Follow(ctx context.Context, followerID, followeeID int) (bool, error)
+	Unfollow(ctx context.Context, followerID, followeeID int) (bool, error)
+	Block(ctx context.Context, blockerID, blockedID int) error
+	Unblock(ctx context.Context, blockerID, blockedID int) error
+	GetFollowers(ctx context.Context, userID, limit, offset int) ([]users.User, error)
+	GetFollowing(ctx context.Context, userID, limit, offset int) ([]users.User, error)
+	GetBlocked(ctx context.Context, userID, limit, offset int) ([]users.User, error)
+}
+
+// FeedInvalidator сбрасывает кэш ленты пользователя
+type FeedInvalidator interface {
+	Invalidate(userID int)
+}
+
+type FollowService struct {
+	repo     FollowRepository
+	feed     FeedInvalidator
+	notifier Notifier
+	uow      UnitOfWork
+	logger   *logger.Logger
+}
+
+func NewFollowService(
+	repo FollowRepository,
+	feed FeedInvalidator,
+	notifier Notifier,
+	uow UnitOfWork,
+	logger *logger.Logger,
+) *FollowService {
+	return &FollowService{
+		repo:     repo,
+		feed:     feed,
+		notifier: notifier,
+		uow:      uow,
+		logger:   logger,
+	}
+}
+
+// GetProfile возвращает публичный профиль; viewerID 0 означает анонимный просмотр
+func (s *FollowService) GetProfile(ctx context.Context, viewerID, userID int) (*dto.ProfileResponse, error) {
+	user, err := s.repo.GetProfile(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	var rel *domain.Relation
+	if viewerID != 0 && viewerID != userID {
+		r, err := s.repo.GetRelation(ctx, viewerID, userID)
+		if err != nil {
+			return nil, err
+		}
+		rel = &r
+	}
+
+	res := dto.ToProfileResponse(*user, rel)
+	return &res, nil
+}
+
+// Follow подписывает followerID на followeeID; повторная подписка ничего не меняет.
+// Подписаться нельзя, если кто-то из двоих заблокировал другого.
+func (s *FollowService) Follow(ctx context.Context, followerID, followeeID int) error {
+	if followerID == followeeID {
+		return domain.ErrSelf
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.lockPair(ctx, followerID, followeeID); err != nil {
+			return err
+		}
+
+		blocked, err := s.repo.IsBlockedEither(ctx, followerID, followeeID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return domain.ErrBlocked
+		}
+
+		created, err := s.repo.Follow(ctx, followerID, followeeID)
+		if err != nil || !created {
+			return err
+		}
+
+		n, err := notification.New(followeeID, followerID, notification.TypeFollow, struct{}{})
+		if err != nil {
+			return err
+		}
+		return s.notifier.Notify(ctx, n)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.feed.Invalidate(followerID)
+
+	return nil
+}
+
+func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		_, err := s.repo.Unfollow(ctx, followerID, followeeID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.feed.Invalidate(followerID)
+
+	return nil
+}
+
+// Block блокирует blockedID и снимает подписки между пользователями в обе стороны
+func (s *FollowService) Block(ctx context.Context, blockerID, blockedID int) error {
+	if blockerID == blockedID {
+		return domain.ErrSelf
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.lockPair(ctx, blockerID, blockedID); err != nil {
+			return err
+		}
+
+		if err := s.repo.Block(ctx, blockerID, blockedID); err != nil {
+			return err
+		}
+		if _, err := s.repo.Unfollow(ctx, blockerID, blockedID); err != nil {
+			return err
+		}
+		_, err := s.repo.Unfollow(ctx, blockedID, blockerID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.feed.Invalidate(blockerID)
+	s.feed.Invalidate(blockedID)
+
+	return nil
+}
+
+// Unblock снимает блокировку; прежние подписки не восстанавливаются
+func (s *FollowService) Unblock(ctx context.Context, blockerID, blockedID int) error {
+	if err := s.repo.Unblock(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	s.feed.Invalidate(blockerID)
+
+	return nil
+}
+
+func (s *FollowService) GetFollowers(ctx context.Context, userID int, req dto.ListRequest) ([]dto.UserResponse, error) {
+	list, err := s.repo.GetFollowers(ctx, userID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToUserResponses(list), nil
+}
+
+func (s *FollowService) GetFollowing(ctx context.Context, userID int, req dto.ListRequest) ([]dto.UserResponse, error) {
+	list, err := s.repo.GetFollowing(ctx, userID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToUserResponses(list), nil
+}
+
+func (s *FollowService) GetBlocked(ctx context.Context, userID int, req dto.ListRequest) ([]dto.UserResponse, error) {
+	list, err := s.repo.GetBlocked(ctx, userID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToUserResponses(list), nil
+}
+
+// lockPair блокирует обоих пользователей до конца транзакции
+func (s *FollowService) lockPair(ctx context.Context, a, b int) error {
+	ok, err := s.repo.LockUsers(ctx, a, b)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
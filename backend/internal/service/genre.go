@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 
+	"github.com/maYkiss56/tunes/internal/domain/audit"
 	domain "github.com/maYkiss56/tunes/internal/domain/genre"
 	"github.com/maYkiss56/tunes/internal/domain/genre/dto"
 	"github.com/maYkiss56/tunes/internal/logger"
@@ -14,22 +15,34 @@ type GenreRepository interface {
 	GetGenreByID(ctx context.Context, id int) (*domain.Genre, error)
 	UpdateGenre(ctx context.Context, id int, update dto.UpdateGenreRequest) error
 	DeleteGenre(ctx context.Context, id int) error
+	RestoreGenre(ctx context.Context, id int) error
 }
 
 type GenreService struct {
-	repo   GenreRepository
-	logger *logger.Logger
+	repo    GenreRepository
+	auditor Auditor
+	uow     UnitOfWork
+	logger  *logger.Logger
 }
 
-func NewGenreService(repo GenreRepository, logger *logger.Logger) *GenreService {
+func NewGenreService(repo GenreRepository, auditor Auditor, uow UnitOfWork, logger *logger.Logger) *GenreService {
 	return &GenreService{
-		repo:   repo,
-		logger: logger,
+		repo:    repo,
+		auditor: auditor,
+		uow:     uow,
+		logger:  logger,
 	}
 }
 
+// CreateGenre сохраняет жанр и запись журнала аудита в одной транзакции
 func (s *GenreService) CreateGenre(ctx context.Context, genre *domain.Genre) error {
-	return s.repo.CreateGenre(ctx, genre)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateGenre(ctx, genre); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityGenre, genre.ID, audit.ActionCreate, nil, s.auditState(ctx, genre.ID))
+	})
 }
 
 func (s *GenreService) GetAllGenre(ctx context.Context) ([]*domain.Genre, error) {
@@ -50,9 +63,44 @@ func (s *GenreService) UpdateGenre(
 	id int,
 	update dto.UpdateGenreRequest,
 ) error {
-	return s.repo.UpdateGenre(ctx, id, update)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, id)
+
+		if err := s.repo.UpdateGenre(ctx, id, update); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityGenre, id, audit.ActionUpdate, before, s.auditState(ctx, id))
+	})
 }
 
 func (s *GenreService) DeleteGenre(ctx context.Context, id int) error {
-	return s.repo.DeleteGenre(ctx, id)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, id)
+
+		if err := s.repo.DeleteGenre(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityGenre, id, audit.ActionDelete, before, nil)
+	})
+}
+
+func (s *GenreService) RestoreGenre(ctx context.Context, id int) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreGenre(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityGenre, id, audit.ActionRestore, nil, s.auditState(ctx, id))
+	})
+}
+
+// auditState возвращает текущее состояние жанра для журнала аудита
+func (s *GenreService) auditState(ctx context.Context, id int) any {
+	genre, err := s.repo.GetGenreByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return dto.ToResponse(*genre)
 }
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/library"
+	"github.com/maYkiss56/tunes/internal/domain/library/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type LibraryRepository interface {
+	TargetExists(ctx context.Context, kind domain.Kind, id int) (bool, error)
+	GetStatus(ctx context.Context, userID int, kind domain.Kind, id int) (domain.Status, error)
+	AddFavorite(ctx context.Context, userID int, kind domain.Kind, id int) error
+	SetShelf(ctx context.Context, userID int, kind domain.Kind, id int, shelf domain.Shelf) error
+	RemoveFromShelf(ctx context.Context, userID int, kind domain.Kind, id int, shelf domain.Shelf) error
+	GetItems(ctx context.Context, userID int, req dto.ListRequest) ([]domain.Item, error)
+}
+
+// LibraryStatuses отметки библиотеки для страниц песен и альбомов
+type LibraryStatuses interface {
+	GetStatuses(ctx context.Context, userID int, kind domain.Kind, ids []int) (map[int]domain.Status, error)
+}
+
+type LibraryService struct {
+	repo   LibraryRepository
+	uow    UnitOfWork
+	logger *logger.Logger
+}
+
+func NewLibraryService(repo LibraryRepository, uow UnitOfWork, logger *logger.Logger) *LibraryService {
+	return &LibraryService{
+		repo:   repo,
+		uow:    uow,
+		logger: logger,
+	}
+}
+
+func (s *LibraryService) GetLibrary(ctx context.Context, userID int, req dto.ListRequest) ([]dto.ItemResponse, error) {
+	items, err := s.repo.GetItems(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToItemResponses(items), nil
+}
+
+func (s *LibraryService) GetStatus(
+	ctx context.Context,
+	userID int,
+	kind domain.Kind,
+	id int,
+) (*dto.StatusResponse, error) {
+	if err := s.checkTarget(ctx, kind, id); err != nil {
+		return nil, err
+	}
+
+	status, err := s.repo.GetStatus(ctx, userID, kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToStatusResponse(status)
+	return &res, nil
+}
+
+// AddToShelf ставит песню или альбом на полку; повторный вызов ничего не меняет
+func (s *LibraryService) AddToShelf(
+	ctx context.Context,
+	userID int,
+	kind domain.Kind,
+	id int,
+	shelf domain.Shelf,
+) (*dto.StatusResponse, error) {
+	if !shelf.IsValid() {
+		return nil, domain.ErrInvalidShelf
+	}
+	if err := s.checkTarget(ctx, kind, id); err != nil {
+		return nil, err
+	}
+
+	var status domain.Status
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if shelf == domain.ShelfFavorite {
+			err = s.repo.AddFavorite(ctx, userID, kind, id)
+		} else {
+			err = s.repo.SetShelf(ctx, userID, kind, id, shelf)
+		}
+		if err != nil {
+			return err
+		}
+
+		status, err = s.repo.GetStatus(ctx, userID, kind, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToStatusResponse(status)
+	return &res, nil
+}
+
+// RemoveFromShelf снимает песню или альбом с полки; объект может быть уже в корзине
+func (s *LibraryService) RemoveFromShelf(
+	ctx context.Context,
+	userID int,
+	kind domain.Kind,
+	id int,
+	shelf domain.Shelf,
+) (*dto.StatusResponse, error) {
+	if !kind.IsValid() {
+		return nil, domain.ErrInvalidKind
+	}
+	if !shelf.IsValid() {
+		return nil, domain.ErrInvalidShelf
+	}
+
+	var status domain.Status
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.RemoveFromShelf(ctx, userID, kind, id, shelf); err != nil {
+			return err
+		}
+
+		var err error
+		status, err = s.repo.GetStatus(ctx, userID, kind, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToStatusResponse(status)
+	return &res, nil
+}
+
+func (s *LibraryService) checkTarget(ctx context.Context, kind domain.Kind, id int) error {
+	if !kind.IsValid() {
+		return domain.ErrInvalidKind
+	}
+
+	exists, err := s.repo.TargetExists(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrTargetNotFound
+	}
+
+	return nil
+}
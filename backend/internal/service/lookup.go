@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	albumDTO "github.com/maYkiss56/tunes/internal/domain/album/dto"
+	"github.com/maYkiss56/tunes/internal/domain/artist"
+	artistDTO "github.com/maYkiss56/tunes/internal/domain/artist/dto"
+	"github.com/maYkiss56/tunes/internal/domain/lookup/dto"
+	songDTO "github.com/maYkiss56/tunes/internal/domain/song/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type SongLookupRepository interface {
+	GetSongByISRC(ctx context.Context, isrc string) (*songDTO.Response, error)
+	GetSongByMBID(ctx context.Context, mbid string) (*songDTO.Response, error)
+}
+
+type AlbumLookupRepository interface {
+	GetAlbumByUPC(ctx context.Context, upc string) (*albumDTO.Response, error)
+	GetAlbumByMBID(ctx context.Context, mbid string) (*albumDTO.Response, error)
+}
+
+type ArtistLookupRepository interface {
+	GetArtistByMBID(ctx context.Context, mbid string) (*artist.Artist, error)
+}
+
+type LookupService struct {
+	songRepo   SongLookupRepository
+	albumRepo  AlbumLookupRepository
+	artistRepo ArtistLookupRepository
+	logger     *logger.Logger
+}
+
+func NewLookupService(
+	songRepo SongLookupRepository,
+	albumRepo AlbumLookupRepository,
+	artistRepo ArtistLookupRepository,
+	logger *logger.Logger,
+) *LookupService {
+	return &LookupService{
+		songRepo:   songRepo,
+		albumRepo:  albumRepo,
+		artistRepo: artistRepo,
+		logger:     logger,
+	}
+}
+
+// Lookup ищет сущности по внешнему идентификатору.
+// MBID проверяется по всем типам сущностей, так как по нему тип не определить.
+func (s *LookupService) Lookup(ctx context.Context, req dto.Request) ([]dto.Response, error) {
+	results := make([]dto.Response, 0, 1)
+
+	if req.ISRC != "" {
+		song, err := s.songRepo.GetSongByISRC(ctx, req.ISRC)
+		if err = skipNotFound(err); err != nil {
+			return nil, err
+		}
+		if song != nil {
+			results = append(results, dto.Response{Entity: dto.EntitySong, ID: song.ID, Song: song})
+		}
+	}
+
+	if req.UPC != "" {
+		album, err := s.albumRepo.GetAlbumByUPC(ctx, req.UPC)
+		if err = skipNotFound(err); err != nil {
+			return nil, err
+		}
+		if album != nil {
+			results = append(results, dto.Response{Entity: dto.EntityAlbum, ID: album.ID, Album: album})
+		}
+	}
+
+	if req.MBID != "" {
+		song, err := s.songRepo.GetSongByMBID(ctx, req.MBID)
+		if err = skipNotFound(err); err != nil {
+			return nil, err
+		}
+		if song != nil {
+			results = append(results, dto.Response{Entity: dto.EntitySong, ID: song.ID, Song: song})
+		}
+
+		album, err := s.albumRepo.GetAlbumByMBID(ctx, req.MBID)
+		if err = skipNotFound(err); err != nil {
+			return nil, err
+		}
+		if album != nil {
+			results = append(results, dto.Response{Entity: dto.EntityAlbum, ID: album.ID, Album: album})
+		}
+
+		a, err := s.artistRepo.GetArtistByMBID(ctx, req.MBID)
+		if err = skipNotFound(err); err != nil {
+			return nil, err
+		}
+		if a != nil {
+			res := artistDTO.ToResponse(*a)
+			results = append(results, dto.Response{Entity: dto.EntityArtist, ID: a.ID, Artist: &res})
+		}
+	}
+
+	return results, nil
+}
+
+func skipNotFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	return err
+}
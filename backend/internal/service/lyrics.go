@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/maYkiss56/tunes/internal/domain/audit"
+	domain "github.com/maYkiss56/tunes/internal/domain/lyrics"
+	"github.com/maYkiss56/tunes/internal/domain/lyrics/dto"
+	songDTO "github.com/maYkiss56/tunes/internal/domain/song/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type LyricsRepository interface {
+	GetLyrics(ctx context.Context, songID int) (*domain.Lyrics, error)
+	UpsertLyrics(ctx context.Context, lyrics *domain.Lyrics) error
+	DeleteLyrics(ctx context.Context, songID int) error
+}
+
+// SongResolver находит песню по id или слагу
+type SongResolver interface {
+	GetSongByRef(ctx context.Context, viewerID int, ref string) (*songDTO.Response, error)
+}
+
+type LyricsService struct {
+	repo    LyricsRepository
+	songs   SongResolver
+	auditor Auditor
+	uow     UnitOfWork
+	logger  *logger.Logger
+}
+
+func NewLyricsService(
+	repo LyricsRepository,
+	songs SongResolver,
+	auditor Auditor,
+	uow UnitOfWork,
+	logger *logger.Logger,
+) *LyricsService {
+	return &LyricsService{
+		repo:    repo,
+		songs:   songs,
+		auditor: auditor,
+		uow:     uow,
+		logger:  logger,
+	}
+}
+
+func (s *LyricsService) GetLyrics(ctx context.Context, songRef string) (*dto.Response, error) {
+	song, err := s.songs.GetSongByRef(ctx, 0, songRef)
+	if err != nil {
+		return nil, err
+	}
+
+	lyrics, err := s.repo.GetLyrics(ctx, song.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToResponse(*lyrics)
+	return &res, nil
+}
+
+func (s *LyricsService) UpsertLyrics(
+	ctx context.Context,
+	songID int,
+	req dto.UpsertLyricsRequest,
+) (*dto.Response, error) {
+	if _, err := s.songs.GetSongByRef(ctx, 0, strconv.Itoa(songID)); err != nil {
+		return nil, err
+	}
+
+	lyrics, err := domain.NewLyrics(songID, req.Plain, req.LRC)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetLyrics(ctx, songID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+
+		if err = s.repo.UpsertLyrics(ctx, lyrics); err != nil {
+			return err
+		}
+
+		action := audit.ActionUpdate
+		if before == nil {
+			action = audit.ActionCreate
+		}
+		return s.auditor.Record(ctx, audit.EntityLyrics, songID, action, before, lyrics)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToResponse(*lyrics)
+	return &res, nil
+}
+
+func (s *LyricsService) DeleteLyrics(ctx context.Context, songID int) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetLyrics(ctx, songID)
+		if err != nil {
+			return err
+		}
+
+		if err = s.repo.DeleteLyrics(ctx, songID); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntityLyrics, songID, audit.ActionDelete, before, nil)
+	})
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/mention"
+	"github.com/maYkiss56/tunes/internal/domain/notification"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type MentionRepository interface {
+	Resolve(ctx context.Context, refs []domain.Ref) ([]domain.Mention, error)
+	ReplaceReviewMentions(ctx context.Context, reviewID int, mentions []domain.Mention) ([]domain.Mention, error)
+	ReplaceArtistMentions(ctx context.Context, artistID int, mentions []domain.Mention) ([]domain.Mention, error)
+}
+
+// Notifier доставляет уведомления пользователям
+type Notifier interface {
+	Notify(ctx context.Context, n *notification.Notification) error
+}
+
+type MentionService struct {
+	repo     MentionRepository
+	notifier Notifier
+	logger   *logger.Logger
+}
+
+func NewMentionService(repo MentionRepository, notifier Notifier, logger *logger.Logger) *MentionService {
+	return &MentionService{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// SyncReview разбирает текст рецензии и сохраняет найденные упоминания.
+// При notify впервые упомянутые пользователи получают уведомление.
+func (s *MentionService) SyncReview(ctx context.Context, reviewID, authorID int, body string, notify bool) error {
+	mentions, err := s.repo.Resolve(ctx, domain.Parse(body))
+	if err != nil {
+		return err
+	}
+
+	added, err := s.repo.ReplaceReviewMentions(ctx, reviewID, mentions)
+	if err != nil {
+		return err
+	}
+
+	if !notify {
+		return nil
+	}
+
+	return s.notify(ctx, added, authorID, notification.MentionPayload{ReviewID: reviewID})
+}
+
+// NotifyReview уведомляет всех упомянутых в рецензии пользователей; вызывается
+// при публикации черновика, правки которого уведомлений не рассылали
+func (s *MentionService) NotifyReview(ctx context.Context, reviewID, authorID int, body string) error {
+	mentions, err := s.repo.Resolve(ctx, domain.Parse(body))
+	if err != nil {
+		return err
+	}
+
+	return s.notify(ctx, mentions, authorID, notification.MentionPayload{ReviewID: reviewID})
+}
+
+// SyncArtist сохраняет упоминания из биографии исполнителя
+func (s *MentionService) SyncArtist(ctx context.Context, artistID int, bio string) error {
+	mentions, err := s.repo.Resolve(ctx, domain.Parse(bio))
+	if err != nil {
+		return err
+	}
+
+	added, err := s.repo.ReplaceArtistMentions(ctx, artistID, mentions)
+	if err != nil {
+		return err
+	}
+
+	return s.notify(ctx, added, editorID(ctx), notification.MentionPayload{ArtistID: artistID})
+}
+
+// notify отправляет по одному уведомлению каждому упомянутому пользователю, кроме автора текста
+func (s *MentionService) notify(
+	ctx context.Context,
+	mentions []domain.Mention,
+	actorID int,
+	payload notification.MentionPayload,
+) error {
+	seen := make(map[int]bool)
+
+	for _, userID := range domain.UserIDs(mentions) {
+		if userID == actorID || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		n, err := notification.New(userID, actorID, notification.TypeMention, payload)
+		if err != nil {
+			return err
+		}
+		if err = s.notifier.Notify(ctx, n); err != nil {
+			s.logger.Error("failed to notify mentioned user", "user_id", userID, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/notification"
+	"github.com/maYkiss56/tunes/internal/domain/notification/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+const (
+	// streamBuffer столько уведомлений ждут медленного клиента, потом поток закрывается
+	streamBuffer = 16
+	// maxStreamsPerUser открытых потоков на пользователя в одном экземпляре
+	maxStreamsPerUser = 5
+	// replayLimit столько пропущенных уведомлений досылается при переподключении
+	replayLimit      = 100
+	listenRetryDelay = 5 * time.Second
+)
+
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, n *domain.Notification) error
+	CreateArtistFollowerNotifications(ctx context.Context, artistID int, t domain.Type, payload []byte) (int64, error)
+	GetNotificationByID(ctx context.Context, id int) (*domain.Notification, error)
+	GetNotifications(
+		ctx context.Context,
+		userID int,
+		unread bool,
+		t domain.Type,
+		limit, offset int,
+	) ([]domain.Notification, error)
+	GetNotificationsAfter(ctx context.Context, userID, afterID, limit int) ([]domain.Notification, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	MarkRead(ctx context.Context, userID int, ids []int) (int64, error)
+	Listen(ctx context.Context, handle func(domain.Event)) error
+}
+
+// stream открытый у клиента поток уведомлений
+type stream struct {
+	ch chan dto.Response
+}
+
+type NotificationService struct {
+	repo   NotificationRepository
+	logger *logger.Logger
+
+	mu      sync.Mutex
+	streams map[int]map[*stream]struct{}
+}
+
+func NewNotificationService(repo NotificationRepository, logger *logger.Logger) *NotificationService {
+	return &NotificationService{
+		repo:    repo,
+		logger:  logger,
+		streams: make(map[int]map[*stream]struct{}),
+	}
+}
+
+// Notify сохраняет уведомление; внутри UnitOfWork.Do оно пишется в той же транзакции
+func (s *NotificationService) Notify(ctx context.Context, n *domain.Notification) error {
+	return s.repo.CreateNotification(ctx, n)
+}
+
+// NotifyArtistFollowers рассылает системное уведомление подписчикам исполнителя
+func (s *NotificationService) NotifyArtistFollowers(
+	ctx context.Context,
+	artistID int,
+	t domain.Type,
+	payload any,
+) (int64, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.repo.CreateArtistFollowerNotifications(ctx, artistID, t, data)
+}
+
+func (s *NotificationService) GetNotifications(
+	ctx context.Context,
+	userID int,
+	req dto.ListRequest,
+) ([]dto.Response, error) {
+	notifications, err := s.repo.GetNotifications(ctx, userID, req.Unread, req.Type, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToResponses(notifications), nil
+}
+
+// GetNotificationsAfter возвращает уведомления, пропущенные потоком после afterID
+func (s *NotificationService) GetNotificationsAfter(ctx context.Context, userID, afterID int) ([]dto.Response, error) {
+	notifications, err := s.repo.GetNotificationsAfter(ctx, userID, afterID, replayLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToResponses(notifications), nil
+}
+
+func (s *NotificationService) CountUnread(ctx context.Context, userID int) (*dto.UnreadResponse, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UnreadResponse{Unread: count}, nil
+}
+
+// MarkRead отмечает прочитанными уведомления из запроса, при пустом списке все
+func (s *NotificationService) MarkRead(ctx context.Context, userID int, req dto.ReadRequest) (*dto.ReadResponse, error) {
+	updated, err := s.repo.MarkRead(ctx, userID, req.IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReadResponse{Updated: updated}, nil
+}
+
+// MarkOneRead отмечает прочитанным одно уведомление; чужое считается несуществующим
+func (s *NotificationService) MarkOneRead(ctx context.Context, userID, id int) error {
+	updated, err := s.repo.MarkRead(ctx, userID, []int{id})
+	if err != nil || updated > 0 {
+		return err
+	}
+
+	// ничего не изменилось: уведомление уже прочитано, или его нет
+	n, err := s.repo.GetNotificationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	if n.UserID != userID {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Subscribe открывает поток новых уведомлений пользователя. Канал закрывается,
+// если клиент не успевает читать или прервалось прослушивание базы: клиенту
+// нужно переподключиться и дочитать пропущенное через GetNotificationsAfter.
+// Возвращённая функция закрывает поток.
+func (s *NotificationService) Subscribe(userID int) (<-chan dto.Response, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.streams[userID]) >= maxStreamsPerUser {
+		return nil, nil, domain.ErrTooManyStreams
+	}
+
+	st := &stream{ch: make(chan dto.Response, streamBuffer)}
+	if s.streams[userID] == nil {
+		s.streams[userID] = make(map[*stream]struct{})
+	}
+	s.streams[userID][st] = struct{}{}
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.drop(userID, st)
+	}
+
+	return st.ch, cancel, nil
+}
+
+// RunListener доставляет открытым потокам уведомления, созданные на любом
+// экземпляре, и переподключается к базе при обрыве
+func (s *NotificationService) RunListener(ctx context.Context) {
+	for {
+		err := s.repo.Listen(ctx, func(e domain.Event) {
+			s.deliver(ctx, e)
+		})
+
+		// пока соединения не было, события терялись: клиенты переподключатся и дочитают их
+		s.closeStreams()
+
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Error("notification listener stopped", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (s *NotificationService) deliver(ctx context.Context, e domain.Event) {
+	s.mu.Lock()
+	listening := len(s.streams[e.UserID]) > 0
+	s.mu.Unlock()
+	if !listening {
+		return
+	}
+
+	n, err := s.repo.GetNotificationByID(ctx, e.ID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Error("failed to load notification for stream", "id", e.ID, "error", err)
+		}
+		return
+	}
+	res := dto.ToResponse(*n)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for st := range s.streams[e.UserID] {
+		select {
+		case st.ch <- res:
+		default:
+			s.drop(e.UserID, st)
+		}
+	}
+}
+
+// drop закрывает поток; вызывается под s.mu
+func (s *NotificationService) drop(userID int, st *stream) {
+	if _, ok := s.streams[userID][st]; !ok {
+		return
+	}
+
+	delete(s.streams[userID], st)
+	if len(s.streams[userID]) == 0 {
+		delete(s.streams, userID)
+	}
+	close(st.ch)
+}
+
+func (s *NotificationService) closeStreams() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for userID, streams := range s.streams {
+		for st := range streams {
+			s.drop(userID, st)
+		}
+	}
+}
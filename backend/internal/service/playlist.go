@@ -0,0 +1,498 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/playlist"
+	"github.com/maYkiss56/tunes/internal/domain/playlist/dto"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type PlaylistRepository interface {
+	CreateList(ctx context.Context, l *domain.List) error
+	GetList(ctx context.Context, id int) (*domain.List, error)
+	LockList(ctx context.Context, id int) (*domain.List, error)
+	IsEditor(ctx context.Context, listID, userID int) (bool, error)
+	IsLiked(ctx context.Context, listID, userID int) (bool, error)
+	UpdateList(ctx context.Context, id int, req dto.UpdateRequest) error
+	DeleteList(ctx context.Context, id int) error
+	GetPublicLists(ctx context.Context, req dto.ListRequest) ([]domain.List, error)
+	GetUserLists(ctx context.Context, userID, limit, offset int) ([]domain.List, error)
+	GetItems(ctx context.Context, listID int) ([]domain.Item, error)
+	GetItem(ctx context.Context, listID, itemID int) (*domain.Item, error)
+	TargetExists(ctx context.Context, t domain.Target) (bool, error)
+	HasItem(ctx context.Context, listID int, t domain.Target) (bool, error)
+	AddItem(ctx context.Context, item *domain.Item) error
+	MoveItem(ctx context.Context, listID, itemID, from, to int) error
+	UpdateItemNote(ctx context.Context, listID, itemID int, note string) error
+	DeleteItem(ctx context.Context, listID, itemID, position int) error
+	Reorder(ctx context.Context, listID int, ids []int) error
+	LikeList(ctx context.Context, listID, userID int) (int, error)
+	UnlikeList(ctx context.Context, listID, userID int) (int, error)
+	GetEditors(ctx context.Context, listID int) ([]users.User, error)
+	AddEditor(ctx context.Context, listID, userID int) error
+	RemoveEditor(ctx context.Context, listID, userID int) error
+}
+
+// PlaylistUsers проверки пользователей при добавлении соавторов
+type PlaylistUsers interface {
+	GetProfile(ctx context.Context, userID int) (*users.User, error)
+	IsBlockedEither(ctx context.Context, a, b int) (bool, error)
+}
+
+type PlaylistService struct {
+	repo   PlaylistRepository
+	users  PlaylistUsers
+	uow    UnitOfWork
+	logger *logger.Logger
+}
+
+func NewPlaylistService(
+	repo PlaylistRepository,
+	users PlaylistUsers,
+	uow UnitOfWork,
+	logger *logger.Logger,
+) *PlaylistService {
+	return &PlaylistService{
+		repo:   repo,
+		users:  users,
+		uow:    uow,
+		logger: logger,
+	}
+}
+
+func (s *PlaylistService) CreateList(ctx context.Context, userID int, req dto.CreateRequest) (*dto.Response, error) {
+	l := domain.NewList(userID, req.Title, req.Description, req.Visibility, *req.Ranked)
+	if err := s.repo.CreateList(ctx, l); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.GetList(ctx, l.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToResponse(*created)
+	res.Items = []dto.ItemResponse{}
+	return &res, nil
+}
+
+// GetLists возвращает подборку публичных списков
+func (s *PlaylistService) GetLists(ctx context.Context, req dto.ListRequest) ([]dto.Response, error) {
+	lists, err := s.repo.GetPublicLists(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToResponses(lists), nil
+}
+
+// GetUserLists возвращает все списки пользователя, включая скрытые и те, где он соавтор
+func (s *PlaylistService) GetUserLists(ctx context.Context, userID int, req dto.ListRequest) ([]dto.Response, error) {
+	lists, err := s.repo.GetUserLists(ctx, userID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToResponses(lists), nil
+}
+
+// GetList открывает список с позициями; viewerID 0 означает анонимный просмотр.
+// Закрытый список для посторонних не существует.
+func (s *PlaylistService) GetList(ctx context.Context, viewerID, id int) (*dto.Response, error) {
+	l, role, err := s.visibleList(ctx, viewerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToResponse(*l)
+	res.Items = dto.ToItemResponses(items)
+
+	if viewerID != 0 {
+		liked, err := s.repo.IsLiked(ctx, id, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		canEdit := role.CanEdit()
+		res.IsLiked = &liked
+		res.CanEdit = &canEdit
+	}
+
+	return &res, nil
+}
+
+// UpdateList меняет название, описание и видимость; только владелец
+func (s *PlaylistService) UpdateList(ctx context.Context, userID, id int, req dto.UpdateRequest) (*dto.Response, error) {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.lockList(ctx, userID, id, domain.RoleOwner); err != nil {
+			return err
+		}
+		return s.repo.UpdateList(ctx, id, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetList(ctx, userID, id)
+}
+
+func (s *PlaylistService) DeleteList(ctx context.Context, userID, id int) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.lockList(ctx, userID, id, domain.RoleOwner); err != nil {
+			return err
+		}
+		return s.repo.DeleteList(ctx, id)
+	})
+}
+
+// AddItem добавляет песню или альбом на место position, по умолчанию в конец
+func (s *PlaylistService) AddItem(
+	ctx context.Context,
+	userID, id int,
+	req dto.AddItemRequest,
+) (*dto.ItemResponse, error) {
+	var item *domain.Item
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		l, err := s.lockList(ctx, userID, id, domain.RoleEditor)
+		if err != nil {
+			return err
+		}
+		if l.ItemCount >= domain.MaxItems {
+			return domain.ErrTooManyItems
+		}
+
+		target := req.Target()
+		exists, err := s.repo.TargetExists(ctx, target)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrTargetNotFound
+		}
+
+		dup, err := s.repo.HasItem(ctx, id, target)
+		if err != nil {
+			return err
+		}
+		if dup {
+			return domain.ErrItemExists
+		}
+
+		position := l.ItemCount + 1
+		if req.Position != nil && *req.Position < position {
+			position = *req.Position
+		}
+
+		added := &domain.Item{
+			ListID:    id,
+			Target:    target,
+			Position:  position,
+			Note:      req.Note,
+			AddedBy:   &userID,
+			CreatedAt: time.Now(),
+		}
+		if err = s.repo.AddItem(ctx, added); err != nil {
+			return err
+		}
+
+		item, err = s.repo.GetItem(ctx, id, added.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToItemResponse(*item)
+	return &res, nil
+}
+
+// UpdateItem меняет заметку позиции и переносит её на новое место
+func (s *PlaylistService) UpdateItem(
+	ctx context.Context,
+	userID, id, itemID int,
+	req dto.UpdateItemRequest,
+) (*dto.ItemResponse, error) {
+	var item *domain.Item
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		l, err := s.lockList(ctx, userID, id, domain.RoleEditor)
+		if err != nil {
+			return err
+		}
+
+		current, err := s.getItem(ctx, id, itemID)
+		if err != nil {
+			return err
+		}
+
+		if req.Position != nil {
+			to := min(*req.Position, l.ItemCount)
+			if to != current.Position {
+				if err = s.repo.MoveItem(ctx, id, itemID, current.Position, to); err != nil {
+					return err
+				}
+			}
+		}
+		if req.Note != nil {
+			if err = s.repo.UpdateItemNote(ctx, id, itemID, *req.Note); err != nil {
+				return err
+			}
+		}
+
+		item, err = s.repo.GetItem(ctx, id, itemID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := dto.ToItemResponse(*item)
+	return &res, nil
+}
+
+func (s *PlaylistService) DeleteItem(ctx context.Context, userID, id, itemID int) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.lockList(ctx, userID, id, domain.RoleEditor); err != nil {
+			return err
+		}
+
+		item, err := s.getItem(ctx, id, itemID)
+		if err != nil {
+			return err
+		}
+
+		return s.repo.DeleteItem(ctx, id, itemID, item.Position)
+	})
+}
+
+// Reorder расставляет позиции в переданном порядке; в нём должны быть
+// все видимые позиции списка ровно по одному разу
+func (s *PlaylistService) Reorder(ctx context.Context, userID, id int, req dto.OrderRequest) ([]dto.ItemResponse, error) {
+	var items []domain.Item
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.lockList(ctx, userID, id, domain.RoleEditor); err != nil {
+			return err
+		}
+
+		current, err := s.repo.GetItems(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !sameItems(current, req.ItemIDs) {
+			return domain.ErrInvalidOrder
+		}
+
+		if err = s.repo.Reorder(ctx, id, req.ItemIDs); err != nil {
+			return err
+		}
+
+		items, err = s.repo.GetItems(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToItemResponses(items), nil
+}
+
+func (s *PlaylistService) LikeList(ctx context.Context, userID, id int) (*dto.LikeResponse, error) {
+	if _, _, err := s.visibleList(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.LikeList(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &dto.LikeResponse{LikeCount: count, IsLiked: true}, nil
+}
+
+// UnlikeList снимает отметку; со скрытого от пользователя списка тоже можно
+func (s *PlaylistService) UnlikeList(ctx context.Context, userID, id int) (*dto.LikeResponse, error) {
+	count, err := s.repo.UnlikeList(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &dto.LikeResponse{LikeCount: count, IsLiked: false}, nil
+}
+
+func (s *PlaylistService) GetEditors(ctx context.Context, viewerID, id int) ([]dto.UserResponse, error) {
+	if _, _, err := s.visibleList(ctx, viewerID, id); err != nil {
+		return nil, err
+	}
+
+	editors, err := s.repo.GetEditors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToUserResponses(editors), nil
+}
+
+// AddEditor приглашает соавтора; только владелец и не между заблокировавшими друг друга
+func (s *PlaylistService) AddEditor(ctx context.Context, userID, id, editorID int) ([]dto.UserResponse, error) {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		l, err := s.lockList(ctx, userID, id, domain.RoleOwner)
+		if err != nil {
+			return err
+		}
+		if editorID == l.OwnerID {
+			return domain.ErrOwnerEditor
+		}
+
+		if _, err = s.users.GetProfile(ctx, editorID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrUserNotFound
+			}
+			return err
+		}
+
+		blocked, err := s.users.IsBlockedEither(ctx, userID, editorID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return domain.ErrBlocked
+		}
+
+		return s.repo.AddEditor(ctx, id, editorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	editors, err := s.repo.GetEditors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToUserResponses(editors), nil
+}
+
+// RemoveEditor убирает соавтора; владелец убирает любого, соавтор только себя
+func (s *PlaylistService) RemoveEditor(ctx context.Context, userID, id, editorID int) error {
+	need := domain.RoleOwner
+	if userID == editorID {
+		need = domain.RoleEditor
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.lockList(ctx, userID, id, need); err != nil {
+			return err
+		}
+		return s.repo.RemoveEditor(ctx, id, editorID)
+	})
+}
+
+// visibleList возвращает список и права пользователя, если тот может его открыть
+func (s *PlaylistService) visibleList(ctx context.Context, userID, id int) (*domain.List, domain.Role, error) {
+	l, err := s.repo.GetList(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.RoleNone, domain.ErrNotFound
+		}
+		return nil, domain.RoleNone, err
+	}
+
+	role, err := s.roleOf(ctx, l, userID)
+	if err != nil {
+		return nil, domain.RoleNone, err
+	}
+	if !l.VisibleTo(role) {
+		return nil, domain.RoleNone, domain.ErrNotFound
+	}
+
+	return l, role, nil
+}
+
+// lockList блокирует список внутри UnitOfWork и проверяет, что у пользователя
+// есть права need; невидимый ему список считается несуществующим
+func (s *PlaylistService) lockList(ctx context.Context, userID, id int, need domain.Role) (*domain.List, error) {
+	l, err := s.repo.LockList(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	role, err := s.roleOf(ctx, l, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !l.VisibleTo(role) {
+		return nil, domain.ErrNotFound
+	}
+	if role < need {
+		return nil, domain.ErrForbidden
+	}
+
+	return l, nil
+}
+
+func (s *PlaylistService) roleOf(ctx context.Context, l *domain.List, userID int) (domain.Role, error) {
+	if userID == 0 || userID == l.OwnerID {
+		return l.RoleOf(userID, false), nil
+	}
+
+	editor, err := s.repo.IsEditor(ctx, l.ID, userID)
+	if err != nil {
+		return domain.RoleNone, err
+	}
+
+	return l.RoleOf(userID, editor), nil
+}
+
+func (s *PlaylistService) getItem(ctx context.Context, listID, itemID int) (*domain.Item, error) {
+	item, err := s.repo.GetItem(ctx, listID, itemID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrItemNotFound
+		}
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// sameItems сообщает, что ids содержит каждую позицию items ровно один раз
+func sameItems(items []domain.Item, ids []int) bool {
+	if len(items) != len(ids) {
+		return false
+	}
+
+	seen := make(map[int]bool, len(items))
+	for _, i := range items {
+		seen[i.ID] = false
+	}
+	for _, id := range ids {
+		done, ok := seen[id]
+		if !ok || done {
+			return false
+		}
+		seen[id] = true
+	}
+
+	return true
+}
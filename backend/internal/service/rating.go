@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/rating"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+// ratingMetrics публикует итоги проверок счётчиков через expvar
+var (
+	ratingMetrics   = expvar.NewMap("rating_consistency")
+	ratingLastDrift = new(expvar.Int)
+	ratingLastRun   = new(expvar.String)
+)
+
+func init() {
+	ratingMetrics.Set("last_drift", ratingLastDrift)
+	ratingMetrics.Set("last_run", ratingLastRun)
+}
+
+type RatingRepository interface {
+	GetCountersBatch(ctx context.Context, kind domain.Kind, afterID, limit int) ([]domain.TargetCounters, error)
+	RepairCounters(ctx context.Context, kind domain.Kind, ids []int) (int64, error)
+}
+
+type RatingService struct {
+	repo      RatingRepository
+	batchSize int
+	logger    *logger.Logger
+}
+
+func NewRatingService(repo RatingRepository, batchSize int, logger *logger.Logger) *RatingService {
+	return &RatingService{
+		repo:      repo,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Check сверяет счётчики всех песен, альбомов и исполнителей с рецензиями пачками по batchSize;
+// при fix разошедшиеся объекты каждой пачки сразу пересчитываются
+func (s *RatingService) Check(ctx context.Context, fix bool) (*domain.CheckResult, error) {
+	res := &domain.CheckResult{StartedAt: time.Now()}
+
+	for _, kind := range domain.Kinds {
+		checked := res.Checked
+		if err := s.checkKind(ctx, kind, fix, res); err != nil {
+			return nil, err
+		}
+		ratingMetrics.Add(string(kind)+"s_checked", int64(res.Checked-checked))
+	}
+
+	res.FinishedAt = time.Now()
+
+	ratingMetrics.Add("runs", 1)
+	ratingMetrics.Add("drift_found", int64(res.Drifted))
+	ratingMetrics.Add("drift_fixed", res.Fixed)
+	ratingLastDrift.Set(int64(res.Drifted))
+	ratingLastRun.Set(res.FinishedAt.Format(time.RFC3339))
+
+	s.logger.Info("Counters checked",
+		"checked", res.Checked,
+		"drifted", res.Drifted,
+		"fixed", res.Fixed,
+		"duration", res.FinishedAt.Sub(res.StartedAt),
+	)
+
+	return res, nil
+}
+
+func (s *RatingService) checkKind(ctx context.Context, kind domain.Kind, fix bool, res *domain.CheckResult) error {
+	afterID := 0
+	for {
+		batch, err := s.repo.GetCountersBatch(ctx, kind, afterID, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		afterID = batch[len(batch)-1].ID
+
+		ids := res.Add(batch)
+		for _, c := range batch {
+			if c.Drifted() {
+				s.logger.Info("Counters drifted",
+					"kind", c.Kind,
+					"id", c.ID,
+					"stored_likes", c.Stored.Likes, "actual_likes", c.Actual.Likes,
+					"stored_dislikes", c.Stored.Dislikes, "actual_dislikes", c.Actual.Dislikes,
+					"stored_scores", c.Stored.ScoreCount, "actual_scores", c.Actual.ScoreCount,
+				)
+			}
+		}
+
+		if fix && len(ids) > 0 {
+			n, err := s.repo.RepairCounters(ctx, kind, ids)
+			if err != nil {
+				return err
+			}
+			res.Fixed += n
+		}
+	}
+}
+
+// RunCheck периодически проверяет счётчики до отмены контекста
+func (s *RatingService) RunCheck(ctx context.Context, interval time.Duration, fix bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Check(ctx, fix); err != nil {
+				s.logger.Error("failed to check counters", "error", err)
+			}
+		}
+	}
+}
+
+// Metrics возвращает метрики проверок в формате JSON
+func (s *RatingService) Metrics() string {
+	return ratingMetrics.String()
+}
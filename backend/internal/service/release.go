@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/maYkiss56/tunes/internal/domain/notification"
+	domain "github.com/maYkiss56/tunes/internal/domain/release"
+	"github.com/maYkiss56/tunes/internal/domain/release/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+const (
+	releaseDispatchBatchSize = 100
+	digestBatchSize          = 100
+	// digestMaxReleases больше релизов в одно письмо не попадает
+	digestMaxReleases = 50
+)
+
+type ReleaseRepository interface {
+	FollowArtist(ctx context.Context, userID, artistID int) (bool, error)
+	UnfollowArtist(ctx context.Context, userID, artistID int) error
+	GetArtistFollow(ctx context.Context, userID, artistID int) (bool, int, error)
+	GetFollowedReleases(ctx context.Context, userID, limit, offset int) ([]domain.Release, error)
+	GetFollowedReleasesBetween(
+		ctx context.Context,
+		userID int,
+		since, until time.Time,
+		limit int,
+	) ([]domain.Release, error)
+	ClaimPendingReleases(ctx context.Context, limit int) ([]domain.Release, error)
+	MarkNotified(ctx context.Context, ids []int64) error
+	GetDigest(ctx context.Context, userID int) (domain.Digest, error)
+	SetDigest(ctx context.Context, userID int, d domain.Digest) error
+	GetDueSubscribers(
+		ctx context.Context,
+		d domain.Digest,
+		dueBefore time.Time,
+		afterID, limit int,
+	) ([]domain.Subscriber, error)
+	SetDigestSent(ctx context.Context, userID int, at time.Time) error
+}
+
+// FollowerNotifier рассылает уведомление подписчикам исполнителя
+type FollowerNotifier interface {
+	NotifyArtistFollowers(ctx context.Context, artistID int, t notification.Type, payload any) (int64, error)
+}
+
+// Mailer отправляет письмо на один адрес
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type ReleaseService struct {
+	repo     ReleaseRepository
+	notifier FollowerNotifier
+	uow      UnitOfWork
+	// mailer == nil, если почта не настроена: сводки тогда не отправляются
+	mailer  Mailer
+	siteURL string
+	logger  *logger.Logger
+}
+
+func NewReleaseService(
+	repo ReleaseRepository,
+	notifier FollowerNotifier,
+	uow UnitOfWork,
+	mailer Mailer,
+	siteURL string,
+	logger *logger.Logger,
+) *ReleaseService {
+	return &ReleaseService{
+		repo:     repo,
+		notifier: notifier,
+		uow:      uow,
+		mailer:   mailer,
+		siteURL:  strings.TrimRight(siteURL, "/"),
+		logger:   logger,
+	}
+}
+
+// FollowArtist подписывает пользователя на релизы исполнителя; повторная подписка ничего не меняет
+func (s *ReleaseService) FollowArtist(ctx context.Context, userID, artistID int) (*dto.FollowResponse, error) {
+	found, err := s.repo.FollowArtist(ctx, userID, artistID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, domain.ErrArtistNotFound
+	}
+
+	return s.GetArtistFollow(ctx, userID, artistID)
+}
+
+func (s *ReleaseService) UnfollowArtist(ctx context.Context, userID, artistID int) error {
+	return s.repo.UnfollowArtist(ctx, userID, artistID)
+}
+
+func (s *ReleaseService) GetArtistFollow(ctx context.Context, userID, artistID int) (*dto.FollowResponse, error) {
+	following, count, err := s.repo.GetArtistFollow(ctx, userID, artistID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrArtistNotFound
+		}
+		s.logger.Error("failed to get artist follow", "user_id", userID, "artist_id", artistID, "error", err)
+		return nil, err
+	}
+
+	return &dto.FollowResponse{
+		ArtistID:      artistID,
+		IsFollowing:   following,
+		FollowerCount: count,
+	}, nil
+}
+
+// GetReleases возвращает свежие релизы исполнителей, на которых подписан пользователь
+func (s *ReleaseService) GetReleases(ctx context.Context, userID int, req dto.ListRequest) ([]dto.Response, error) {
+	releases, err := s.repo.GetFollowedReleases(ctx, userID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToResponses(releases), nil
+}
+
+func (s *ReleaseService) GetDigest(ctx context.Context, userID int) (*dto.DigestResponse, error) {
+	d, err := s.repo.GetDigest(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.DigestResponse{Frequency: d}, nil
+}
+
+func (s *ReleaseService) SetDigest(ctx context.Context, userID int, req dto.DigestRequest) (*dto.DigestResponse, error) {
+	if err := s.repo.SetDigest(ctx, userID, req.Frequency); err != nil {
+		return nil, err
+	}
+
+	return &dto.DigestResponse{Frequency: req.Frequency}, nil
+}
+
+// DispatchPending рассылает уведомления о релизах из очереди пачками, каждую
+// в своей транзакции вместе с отметкой о рассылке, и возвращает число релизов.
+// Релизы, убранные в корзину до рассылки, закрываются без уведомлений.
+func (s *ReleaseService) DispatchPending(ctx context.Context) (int, error) {
+	dispatched := 0
+
+	for {
+		var claimed int
+
+		err := s.uow.Do(ctx, func(ctx context.Context) error {
+			releases, err := s.repo.ClaimPendingReleases(ctx, releaseDispatchBatchSize)
+			if err != nil {
+				return err
+			}
+			claimed = len(releases)
+			if claimed == 0 {
+				return nil
+			}
+
+			ids := make([]int64, 0, len(releases))
+			for _, rel := range releases {
+				ids = append(ids, rel.ID)
+				if rel.Deleted {
+					continue
+				}
+
+				_, err = s.notifier.NotifyArtistFollowers(ctx, rel.Artist.ID, notification.TypeRelease, rel.Payload())
+				if err != nil {
+					return err
+				}
+				dispatched++
+			}
+
+			return s.repo.MarkNotified(ctx, ids)
+		})
+		if err != nil {
+			s.logger.Error("failed to dispatch releases", "error", err)
+			return dispatched, err
+		}
+
+		if claimed < releaseDispatchBatchSize {
+			return dispatched, nil
+		}
+	}
+}
+
+func (s *ReleaseService) RunDispatch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.DispatchPending(ctx); err == nil && n > 0 {
+				s.logger.Info("dispatched release notifications", "releases", n)
+			}
+		}
+	}
+}
+
+// SendDigests отправляет сводки всем, кому пора, и возвращает число писем.
+// Сводка без новых релизов не отправляется, но период всё равно закрывается;
+// письмо, которое не ушло, повторится при следующей проверке.
+func (s *ReleaseService) SendDigests(ctx context.Context) (int, error) {
+	if s.mailer == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	sent := 0
+
+	for _, d := range []domain.Digest{domain.DigestDaily, domain.DigestWeekly} {
+		afterID := 0
+
+		for {
+			subscribers, err := s.repo.GetDueSubscribers(ctx, d, now.Add(-d.Period()), afterID, digestBatchSize)
+			if err != nil {
+				return sent, err
+			}
+
+			for _, sub := range subscribers {
+				afterID = sub.UserID
+
+				ok, err := s.sendDigest(ctx, sub, now)
+				if err != nil {
+					s.logger.Error("failed to send digest", "user_id", sub.UserID, "error", err)
+					continue
+				}
+				if ok {
+					sent++
+				}
+			}
+
+			if len(subscribers) < digestBatchSize {
+				break
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+// sendDigest отправляет одну сводку за период до now; false, если новых релизов не было
+func (s *ReleaseService) sendDigest(ctx context.Context, sub domain.Subscriber, now time.Time) (bool, error) {
+	releases, err := s.repo.GetFollowedReleasesBetween(ctx, sub.UserID, sub.Since(now), now, digestMaxReleases)
+	if err != nil {
+		return false, err
+	}
+
+	if len(releases) > 0 {
+		subject := fmt.Sprintf("Новые релизы в ваших подписках: %d", len(releases))
+		if err = s.mailer.Send(sub.Email, subject, s.digestBody(sub, releases)); err != nil {
+			return false, err
+		}
+	}
+
+	if err = s.repo.SetDigestSent(ctx, sub.UserID, now); err != nil {
+		return false, err
+	}
+
+	return len(releases) > 0, nil
+}
+
+func (s *ReleaseService) digestBody(sub domain.Subscriber, releases []domain.Release) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Здравствуйте, %s!\n\nНовые релизы исполнителей, на которых вы подписаны:\n\n", sub.Username)
+
+	for _, rel := range releases {
+		kind := "песня"
+		if rel.Kind == domain.KindAlbum {
+			kind = "альбом"
+		}
+		fmt.Fprintf(&b, "- %s — %s (%s)\n  %s%s\n", rel.Artist.Nickname, rel.Title, kind, s.siteURL, rel.Path())
+	}
+
+	b.WriteString("\nОтключить сводку можно в настройках профиля.\n")
+
+	return b.String()
+}
+
+func (s *ReleaseService) RunDigests(ctx context.Context, interval time.Duration) {
+	if s.mailer == nil {
+		s.logger.Info("mail is not configured, release digests are disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.SendDigests(ctx); err == nil && n > 0 {
+				s.logger.Info("sent release digests", "count", n)
+			}
+		}
+	}
+}
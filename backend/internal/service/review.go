@@ -2,51 +2,142 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+
+	"github.com/maYkiss56/tunes/internal/domain/audit"
+	"github.com/maYkiss56/tunes/internal/domain/mention"
+	"github.com/maYkiss56/tunes/internal/domain/moderation"
+	"github.com/maYkiss56/tunes/internal/domain/notification"
 	domain "github.com/maYkiss56/tunes/internal/domain/review"
 	"github.com/maYkiss56/tunes/internal/domain/review/dto"
+	"github.com/maYkiss56/tunes/internal/domain/users"
 	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/session"
 )
 
 type ReviewRepository interface {
 	CreateReview(ctx context.Context, review *domain.Review) error
-	GetAllReviews(ctx context.Context) ([]dto.Response, error)
+	GetAllReviews(ctx context.Context, sort string) ([]dto.Response, error)
+	GetReviewsByTarget(ctx context.Context, target domain.Target, sort string) ([]dto.Response, error)
+	GetReviewsMentioning(ctx context.Context, kind mention.Kind, id int) ([]dto.Response, error)
 	GetAllReviewsByUserID(ctx context.Context, id int) ([]dto.Response, error)
 	GetReviewByID(ctx context.Context, id int) (*dto.Response, error)
+	GetReviewForUpdate(ctx context.Context, id int) (*domain.Review, error)
+	LockReview(ctx context.Context, id int) (int, error)
+	GetVote(ctx context.Context, reviewID, userID int) (*bool, error)
+	SetVote(ctx context.Context, reviewID, userID int, helpful *bool) error
+	ApplyVoteDelta(ctx context.Context, reviewID, helpful, unhelpful int) error
+	SetReaction(ctx context.Context, reviewID, userID int, emoji string) error
 	UpdateReview(ctx context.Context, id int, update dto.UpdateReviewRequest) error
 	DeleteReview(ctx context.Context, id int) error
+	AddRevision(ctx context.Context, before *domain.Review, editedBy int) error
+	GetHistory(ctx context.Context, reviewID int) ([]domain.Revision, error)
+	GetRevision(ctx context.Context, reviewID, revision int) (*domain.Revision, error)
+	GetDrafts(ctx context.Context, userID int) ([]dto.Response, error)
+	SetStatus(ctx context.Context, id int, status domain.Status, publishAt *time.Time) error
+	SetValid(ctx context.Context, id int, valid bool) error
+	GetDuePublications(ctx context.Context, limit int) ([]int, error)
+}
+
+// RatingCounter ведёт счётчики рецензий объектов одного вида
+type RatingCounter interface {
+	ApplyRatingDelta(ctx context.Context, id int, d domain.RatingDelta) error
+}
+
+// Mentioner сохраняет упоминания из текста рецензии и уведомляет упомянутых
+type Mentioner interface {
+	SyncReview(ctx context.Context, reviewID, authorID int, body string, notify bool) error
+	NotifyReview(ctx context.Context, reviewID, authorID int, body string) error
 }
 
+// publishBatchSize число запланированных рецензий, выбираемых планировщиком за раз
+const publishBatchSize = 100
+
 type ReviewService struct {
 	repo     ReviewRepository
-	songRepo SongRepository
+	counters map[domain.TargetType]RatingCounter
+	mentions Mentioner
+	notifier Notifier
+	uow      UnitOfWork
+	auditor  Auditor
 	logger   *logger.Logger
 }
 
-func NewReviewService(repo ReviewRepository, songRepo SongRepository, logger *logger.Logger) *ReviewService {
+func NewReviewService(
+	repo ReviewRepository,
+	songs RatingCounter,
+	albums RatingCounter,
+	artists RatingCounter,
+	mentions Mentioner,
+	notifier Notifier,
+	uow UnitOfWork,
+	auditor Auditor,
+	logger *logger.Logger,
+) *ReviewService {
 	return &ReviewService{
-		repo:     repo,
-		songRepo: songRepo,
+		repo: repo,
+		counters: map[domain.TargetType]RatingCounter{
+			domain.TargetSong:   songs,
+			domain.TargetAlbum:  albums,
+			domain.TargetArtist: artists,
+		},
+		mentions: mentions,
+		notifier: notifier,
+		uow:      uow,
+		auditor:  auditor,
 		logger:   logger,
 	}
 }
 
 func (s *ReviewService) CreateReview(ctx context.Context, review *domain.Review) error {
-	err := s.repo.CreateReview(ctx, review)
-	if err != nil {
-		return err
-	}
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateReview(ctx, review); err != nil {
+			return err
+		}
 
-	if err := s.songRepo.UpdateSongRating(ctx, review.SongID); err != nil {
-		s.logger.Error("Failed to update song rating", "error", err)
-		return err
+		return s.created(ctx, review)
+	})
+}
+
+// UpsertReview сохраняет единственную рецензию пользователя на песню,
+// created сообщает, была ли она создана
+func (s *ReviewService) UpsertReview(ctx context.Context, review *domain.Review) (bool, error) {
+	var created bool
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		err := s.repo.CreateReview(ctx, review)
+		if err == nil {
+			created = true
+			return s.created(ctx, review)
+		}
+
+		var exists *domain.ExistsError
+		if !errors.As(err, &exists) {
+			return err
+		}
+
+		// 0 снимает оценку, если в новой версии её нет
+		score := scoreValue(review.Score)
+		review.ID = exists.ID
+
+		return s.updateReview(ctx, exists.ID, dto.UpdateReviewRequest{
+			Body:   &review.Body,
+			IsLike: &review.IsLike,
+			Score:  &score,
+		})
+	})
+	if err != nil {
+		return false, err
 	}
 
-	return nil
+	return created, nil
 }
 
-func (s *ReviewService) GetAllReviews(ctx context.Context) ([]dto.Response, error) {
-	reviews, err := s.repo.GetAllReviews(ctx)
+func (s *ReviewService) GetAllReviews(ctx context.Context, sort string) ([]dto.Response, error) {
+	reviews, err := s.repo.GetAllReviews(ctx, sort)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +145,15 @@ func (s *ReviewService) GetAllReviews(ctx context.Context) ([]dto.Response, erro
 	return reviews, nil
 }
 
+func (s *ReviewService) GetReviewsByTarget(ctx context.Context, target domain.Target, sort string) ([]dto.Response, error) {
+	return s.repo.GetReviewsByTarget(ctx, target, sort)
+}
+
+// GetReviewsMentioning возвращает рецензии, в которых упомянута сущность
+func (s *ReviewService) GetReviewsMentioning(ctx context.Context, kind mention.Kind, id int) ([]dto.Response, error) {
+	return s.repo.GetReviewsMentioning(ctx, kind, id)
+}
+
 func (s *ReviewService) GetAllReviewsByUserID(ctx context.Context, id int) ([]dto.Response, error) {
 	reviews, err := s.repo.GetAllReviewsByUserID(ctx, id)
 	if err != nil {
@@ -63,8 +163,21 @@ func (s *ReviewService) GetAllReviewsByUserID(ctx context.Context, id int) ([]dt
 	return reviews, nil
 }
 
+// GetReviewByID не показывает чужие черновики
 func (s *ReviewService) GetReviewByID(ctx context.Context, id int) (*dto.Response, error) {
-	return s.repo.GetReviewByID(ctx, id)
+	review, err := s.repo.GetReviewByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if review.Status != domain.StatusPublished && editorID(ctx) != review.User.ID {
+		return nil, domain.ErrNotFound
+	}
+
+	return review, nil
 }
 
 func (s *ReviewService) UpdateReview(
@@ -75,45 +188,477 @@ func (s *ReviewService) UpdateReview(
 	// Сначала получаем текущую рецензию
 	currentReview, err := s.repo.GetReviewByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
 		return err
 	}
 
-	// Сохраняем предыдущее значение isLike
-	oldIsLike := currentReview.IsLike
+	// Рецензия и счётчики песни меняются в одной транзакции
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOwnReview(ctx, id); err != nil {
+			return err
+		}
 
-	// Обновляем рецензию
-	if err := s.repo.UpdateReview(ctx, id, update); err != nil {
-		return err
-	}
+		if err := s.updateReview(ctx, id, update); err != nil {
+			return err
+		}
+		if !isModeration(ctx, currentReview) {
+			return nil
+		}
 
-	// Если изменился isLike, обновляем рейтинг песни
-	if update.IsLike != nil && *update.IsLike != oldIsLike {
-		if err := s.songRepo.UpdateSongRating(ctx, currentReview.Song.ID); err != nil {
-			s.logger.Error("Failed to update song rating after review update", "error", err)
+		after, err := s.repo.GetReviewByID(ctx, id)
+		if err != nil {
 			return err
 		}
+		return s.auditor.Record(ctx, audit.EntityReview, id, audit.ActionModerate, currentReview, after)
+	})
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func (s *ReviewService) DeleteReview(ctx context.Context, id int) error {
-	// Сначала получаем рецензию, чтобы знать songID
 	review, err := s.repo.GetReviewByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
 		return err
 	}
 
-	// Удаляем рецензию
-	if err := s.repo.DeleteReview(ctx, id); err != nil {
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.lockOwnReview(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.DeleteReview(ctx, id); err != nil {
+			return err
+		}
+
+		if err := s.applyDelta(ctx, before.Target, domain.Delta(before, nil)); err != nil {
+			return err
+		}
+		if !isModeration(ctx, review) {
+			return nil
+		}
+
+		return s.auditor.Record(ctx, audit.EntityReview, id, audit.ActionModerate, review, nil)
+	})
+	if err != nil {
 		return err
 	}
 
-	// Обновляем рейтинг песни
-	if err := s.songRepo.UpdateSongRating(ctx, review.Song.ID); err != nil {
-		s.logger.Error("Failed to update song rating after review deletion", "error", err)
+	return nil
+}
+
+// VoteReview сохраняет голос «полезно/бесполезно» пользователя, nil снимает его.
+// Голосовать за свою рецензию нельзя.
+func (s *ReviewService) VoteReview(ctx context.Context, reviewID, userID int, helpful *bool) (*dto.Response, error) {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		authorID, err := s.lockReview(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+		if authorID == userID {
+			return domain.ErrOwnReview
+		}
+
+		before, err := s.repo.GetVote(ctx, reviewID, userID)
+		if err != nil {
+			return err
+		}
+
+		if err = s.repo.SetVote(ctx, reviewID, userID, helpful); err != nil {
+			return err
+		}
+
+		up, down := domain.VoteDelta(before, helpful)
+		if up == 0 && down == 0 {
+			return nil
+		}
+		return s.repo.ApplyVoteDelta(ctx, reviewID, up, down)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReviewByID(ctx, reviewID)
+}
+
+// ReactToReview сохраняет реакцию пользователя, пустая строка снимает её
+func (s *ReviewService) ReactToReview(ctx context.Context, reviewID, userID int, emoji string) (*dto.Response, error) {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.lockReview(ctx, reviewID); err != nil {
+			return err
+		}
+
+		return s.repo.SetReaction(ctx, reviewID, userID, emoji)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReviewByID(ctx, reviewID)
+}
+
+// GetHistory возвращает все версии рецензии с изменениями между соседними;
+// историю черновика видит только автор
+func (s *ReviewService) GetHistory(ctx context.Context, id int) (*dto.HistoryResponse, error) {
+	if _, err := s.GetReviewByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.GetHistory(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	res := dto.ToHistoryResponse(id, revisions)
+	return &res, nil
+}
+
+// RestoreRevision делает прежнюю версию текущей; заменяемая версия
+// сохраняется в истории, поэтому откат тоже можно отменить
+func (s *ReviewService) RestoreRevision(ctx context.Context, id, revision int) (*dto.Response, error) {
+	current, err := s.repo.GetReviewByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var restored *dto.Response
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		rev, err := s.repo.GetRevision(ctx, id, revision)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrRevisionNotFound
+			}
+			return err
+		}
+
+		score := scoreValue(rev.Score)
+
+		err = s.updateReview(ctx, id, dto.UpdateReviewRequest{
+			Body:   &rev.Body,
+			IsLike: &rev.IsLike,
+			Score:  &score,
+		})
+		if err != nil {
+			return err
+		}
+
+		if restored, err = s.repo.GetReviewByID(ctx, id); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.EntityReview, id, audit.ActionRestore, current, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// ModerateReview одобряет или отклоняет рецензию: отклонённая перестаёт учитываться
+// в счётчиках объекта. Автор получает уведомление, если решение изменилось.
+func (s *ReviewService) ModerateReview(
+	ctx context.Context,
+	id int,
+	req dto.ModerateReviewRequest,
+) (*dto.Response, error) {
+	current, err := s.repo.GetReviewByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	status := moderation.Status(req.Status)
+	valid := status == moderation.StatusApproved
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetReviewForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.IsValid == valid {
+			return nil
+		}
+
+		if err = s.repo.SetValid(ctx, id, valid); err != nil {
+			return err
+		}
+
+		after := *before
+		after.IsValid = valid
+		if err = s.applyDelta(ctx, before.Target, domain.Delta(before, &after)); err != nil {
+			return err
+		}
+
+		t := notification.TypeReviewRejected
+		if valid {
+			t = notification.TypeReviewApproved
+		}
+		// решение приходит от имени сервиса, а не конкретного модератора
+		n, err := notification.New(before.UserID, 0, t, notification.ModerationPayload{
+			ReviewID: id,
+			Reason:   req.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		if err = s.notifier.Notify(ctx, n); err != nil {
+			return err
+		}
+
+		moderated, err := s.repo.GetReviewByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.EntityReview, id, audit.ActionModerate, current, moderated)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return s.repo.GetReviewByID(ctx, id)
+}
+
+func (s *ReviewService) lockReview(ctx context.Context, id int) (int, error) {
+	authorID, err := s.repo.LockReview(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, err
+	}
+
+	return authorID, nil
+}
+
+// lockOwnReview блокирует рецензию в любом статусе, которую меняет её автор
+// или администратор; вызывается внутри UnitOfWork.Do
+func (s *ReviewService) lockOwnReview(ctx context.Context, id int) (*domain.Review, error) {
+	review, err := s.repo.GetReviewForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	sess := session.FromContext(ctx)
+	if sess == nil || (sess.UserID != review.UserID && sess.UserRoleID != users.AdminRoleID) {
+		return nil, domain.ErrNotAuthor
+	}
+
+	return review, nil
+}
+
+// created учитывает новую рецензию в счётчиках и сохраняет её упоминания;
+// вызывается внутри UnitOfWork.Do
+func (s *ReviewService) created(ctx context.Context, review *domain.Review) error {
+	if err := s.mentions.SyncReview(ctx, review.ID, review.UserID, review.Body, review.IsPublished()); err != nil {
+		return err
+	}
+
+	return s.applyDelta(ctx, review.Target, domain.Delta(nil, review))
+}
+
+// updateReview меняет заблокированную рецензию и прибавляет разницу к счётчикам песни;
+// вызывается внутри UnitOfWork.Do
+func (s *ReviewService) updateReview(ctx context.Context, id int, update dto.UpdateReviewRequest) error {
+	before, err := s.repo.GetReviewForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateReview(ctx, id, update); err != nil {
+		return err
+	}
+
+	after, err := s.repo.GetReviewForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// правки черновика не попадают в историю и не делают рецензию отредактированной
+	if before.IsPublished() && !before.SameContent(after) {
+		if err := s.repo.AddRevision(ctx, before, editorID(ctx)); err != nil {
+			return err
+		}
+	}
+
+	if before.Body != after.Body {
+		if err := s.mentions.SyncReview(ctx, id, after.UserID, after.Body, after.IsPublished()); err != nil {
+			return err
+		}
+	}
+
+	return s.applyDelta(ctx, after.Target, domain.Delta(before, after))
+}
+
+func (s *ReviewService) applyDelta(ctx context.Context, target domain.Target, d domain.RatingDelta) error {
+	if d.IsZero() {
+		return nil
+	}
+
+	counter, ok := s.counters[target.Type]
+	if !ok {
+		return domain.ErrInvalidTarget
+	}
+
+	if err := counter.ApplyRatingDelta(ctx, target.ID, d); err != nil {
+		s.logger.Error("Failed to update rating", "target", target.String(), "error", err)
 		return err
 	}
 
 	return nil
 }
+
+// editorID возвращает автора правки из сессии, 0 для правок вне HTTP-запроса
+func editorID(ctx context.Context) int {
+	if s := session.FromContext(ctx); s != nil {
+		return s.UserID
+	}
+	return 0
+}
+
+// isModeration сообщает, что администратор меняет чужую рецензию
+func isModeration(ctx context.Context, review *dto.Response) bool {
+	s := session.FromContext(ctx)
+	return s != nil && s.UserRoleID == users.AdminRoleID && s.UserID != review.User.ID
+}
+
+// scoreValue возвращает оценку рецензии, 0 означает её отсутствие
+func scoreValue(score *int) int {
+	if score == nil {
+		return 0
+	}
+	return *score
+}
+
+func (s *ReviewService) GetDrafts(ctx context.Context, userID int) ([]dto.Response, error) {
+	return s.repo.GetDrafts(ctx, userID)
+}
+
+// PublishReview публикует черновик автора сразу или, если at в будущем, в момент at
+func (s *ReviewService) PublishReview(ctx context.Context, id, userID int, at *time.Time) (*dto.Response, error) {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetReviewForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+		if before.UserID != userID {
+			return domain.ErrNotAuthor
+		}
+		if before.IsPublished() {
+			return domain.ErrAlreadyPublished
+		}
+
+		if at != nil && at.After(time.Now()) {
+			return s.repo.SetStatus(ctx, id, domain.StatusScheduled, at)
+		}
+
+		return s.publish(ctx, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReviewByID(ctx, id)
+}
+
+// PublishDue публикует запланированные рецензии, время которых наступило,
+// каждую в своей транзакции, и возвращает число опубликованных
+func (s *ReviewService) PublishDue(ctx context.Context) (int, error) {
+	published := 0
+
+	for {
+		ids, err := s.repo.GetDuePublications(ctx, publishBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		for _, id := range ids {
+			var due bool
+
+			err = s.uow.Do(ctx, func(ctx context.Context) error {
+				review, err := s.repo.GetReviewForUpdate(ctx, id)
+				if err != nil {
+					return err
+				}
+				// автор мог перенести публикацию, пока рецензия ждала блокировки
+				if due = review.IsDue(time.Now()); !due {
+					return nil
+				}
+
+				return s.publish(ctx, review)
+			})
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				// рецензию удалили
+			case err != nil:
+				s.logger.Error("failed to publish scheduled review", "id", id, "error", err)
+				return published, err
+			case due:
+				published++
+			}
+		}
+
+		if len(ids) < publishBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// RunPublishScheduled периодически публикует запланированные рецензии до отмены контекста
+func (s *ReviewService) RunPublishScheduled(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.PublishDue(ctx); err == nil && n > 0 {
+				s.logger.Info("published scheduled reviews", "count", n)
+			}
+		}
+	}
+}
+
+// publish делает заблокированную рецензию опубликованной и только теперь
+// добавляет её вклад в счётчики объекта
+func (s *ReviewService) publish(ctx context.Context, before *domain.Review) error {
+	if err := s.repo.SetStatus(ctx, before.ID, domain.StatusPublished, nil); err != nil {
+		return err
+	}
+
+	// упоминания черновика сохранены без уведомлений, они уходят при публикации
+	if err := s.mentions.NotifyReview(ctx, before.ID, before.UserID, before.Body); err != nil {
+		return err
+	}
+
+	after := *before
+	after.Status = domain.StatusPublished
+
+	return s.applyDelta(ctx, before.Target, domain.Delta(before, &after))
+}
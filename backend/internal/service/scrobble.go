@@ -0,0 +1,408 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/scrobble"
+	"github.com/maYkiss56/tunes/internal/domain/scrobble/dto"
+	"github.com/maYkiss56/tunes/internal/domain/users"
+	"github.com/maYkiss56/tunes/internal/logger"
+	"github.com/maYkiss56/tunes/internal/utilites"
+)
+
+type ScrobbleRepository interface {
+	GetUserByLogin(ctx context.Context, login string) (*users.User, error)
+	CreateSession(ctx context.Context, s *domain.Session) error
+	UseSession(ctx context.Context, key string) (*domain.Session, error)
+	GetSessions(ctx context.Context, userID int) ([]domain.Session, error)
+	DeleteSession(ctx context.Context, userID, id int) (bool, error)
+	CreateToken(ctx context.Context, t domain.Token) error
+	LockToken(ctx context.Context, token string) (*domain.Token, error)
+	ApproveToken(ctx context.Context, token string, userID int) error
+	DeleteToken(ctx context.Context, token string) error
+	MatchMBID(ctx context.Context, mbid string) (songID, artistID *int, err error)
+	MatchArtist(ctx context.Context, name string) (*int, error)
+	MatchSong(ctx context.Context, artistID int, title, album string) (*int, error)
+	AddScrobble(ctx context.Context, userID int, t domain.Track) error
+	SetNowPlaying(ctx context.Context, n *domain.NowPlaying) error
+	GetNowPlaying(ctx context.Context, userID int, now time.Time) (*domain.NowPlaying, error)
+	GetScrobbles(ctx context.Context, userID, limit, offset int) ([]domain.Scrobble, error)
+	GetSummary(ctx context.Context, userID int, since time.Time) (*domain.Summary, error)
+	GetTopArtists(ctx context.Context, userID int, since time.Time, limit int) ([]domain.ArtistStat, error)
+	GetTopSongs(ctx context.Context, userID int, since time.Time, limit int) ([]domain.SongStat, error)
+}
+
+type ScrobbleService struct {
+	repo ScrobbleRepository
+	uow  UnitOfWork
+	// secret общий секрет клиентов; без него протокол скробблеров отключён
+	secret string
+	logger *logger.Logger
+}
+
+func NewScrobbleService(repo ScrobbleRepository, uow UnitOfWork, secret string, logger *logger.Logger) *ScrobbleService {
+	if secret == "" {
+		logger.Info("Scrobble secret is not set, scrobbler API is disabled")
+	}
+
+	return &ScrobbleService{
+		repo:   repo,
+		uow:    uow,
+		secret: secret,
+		logger: logger,
+	}
+}
+
+// Enabled сообщает, что секрет настроен и протокол скробблеров принимает запросы
+func (s *ScrobbleService) Enabled() bool {
+	return s.secret != ""
+}
+
+// CheckSignature сверяет api_sig запроса скробблера с настроенным секретом
+func (s *ScrobbleService) CheckSignature(params map[string]string) bool {
+	if !s.Enabled() {
+		return false
+	}
+
+	want := domain.Sign(params, s.secret)
+	got := strings.ToLower(params["api_sig"])
+	return subtle.ConstantTimeCompare([]byte(want), []byte(got)) == 1
+}
+
+// CreateToken выдаёт токен для входа настольного клиента (auth.getToken)
+func (s *ScrobbleService) CreateToken(ctx context.Context, client string) (string, error) {
+	token, err := newScrobbleKey()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repo.CreateToken(ctx, domain.Token{
+		Token:     token,
+		Client:    client,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// AuthorizeToken подтверждает токен настольного клиента от имени пользователя
+func (s *ScrobbleService) AuthorizeToken(ctx context.Context, userID int, req dto.AuthorizeRequest) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		t, err := s.lockToken(ctx, req.Token)
+		if err != nil {
+			return err
+		}
+		if t.UserID != nil && *t.UserID != userID {
+			return domain.ErrTokenNotFound
+		}
+
+		return s.repo.ApproveToken(ctx, t.Token, userID)
+	})
+}
+
+// GetSession обменивает подтверждённый токен на ключ сессии (auth.getSession);
+// токен одноразовый
+func (s *ScrobbleService) GetSession(ctx context.Context, token string) (*domain.Session, error) {
+	var session *domain.Session
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		t, err := s.lockToken(ctx, token)
+		if err != nil {
+			return err
+		}
+		if t.UserID == nil {
+			return domain.ErrTokenNotApproved
+		}
+
+		if err = s.repo.DeleteToken(ctx, t.Token); err != nil {
+			return err
+		}
+
+		session, err = s.createSession(ctx, *t.UserID, t.Username, t.Client)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// MobileSession выдаёт ключ сессии по имени или почте и паролю (auth.getMobileSession)
+func (s *ScrobbleService) MobileSession(ctx context.Context, login, password, client string) (*domain.Session, error) {
+	user, err := s.repo.GetUserByLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAuthFailed
+		}
+		return nil, err
+	}
+
+	if err = utilites.CompareHashAndPassword(user.PasswordHash, password); err != nil {
+		return nil, domain.ErrAuthFailed
+	}
+
+	return s.createSession(ctx, user.ID, user.Username, client)
+}
+
+// Authenticate возвращает сессию по ключу sk из запроса скробблера
+func (s *ScrobbleService) Authenticate(ctx context.Context, key string) (*domain.Session, error) {
+	session, err := s.repo.UseSession(ctx, key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidSession
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Scrobble сохраняет пакет прослушиваний. Прослушивания, не прошедшие
+// проверку, возвращаются с кодом причины и не сохраняются; повторы молча
+// принимаются, чтобы клиент не слал их снова.
+func (s *ScrobbleService) Scrobble(ctx context.Context, userID int, tracks []domain.Track) ([]domain.Result, error) {
+	now := time.Now()
+	m := newTrackMatcher(s.repo)
+
+	results := make([]domain.Result, 0, len(tracks))
+	for _, t := range tracks {
+		t.Normalize()
+
+		res := domain.Result{Track: t, Ignored: t.Check(now, true)}
+		if res.Ignored == domain.IgnoredNone {
+			if err := m.match(ctx, &res.Track); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, res)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		for _, res := range results {
+			if res.Ignored != domain.IgnoredNone {
+				continue
+			}
+			if err := s.repo.AddScrobble(ctx, userID, res.Track); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// UpdateNowPlaying отмечает трек, который пользователь слушает сейчас
+func (s *ScrobbleService) UpdateNowPlaying(ctx context.Context, userID int, t domain.Track) (*domain.Result, error) {
+	now := time.Now()
+	t.Normalize()
+
+	res := &domain.Result{Track: t, Ignored: t.Check(now, false)}
+	if res.Ignored != domain.IgnoredNone {
+		return res, nil
+	}
+
+	if err := newTrackMatcher(s.repo).match(ctx, &res.Track); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetNowPlaying(ctx, domain.NewNowPlaying(userID, res.Track, now)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *ScrobbleService) GetScrobblers(ctx context.Context, userID int) ([]dto.ScrobblerResponse, error) {
+	sessions, err := s.repo.GetSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToScrobblerResponses(sessions), nil
+}
+
+// DeleteScrobbler отключает скробблер: его ключ сессии перестаёт действовать
+func (s *ScrobbleService) DeleteScrobbler(ctx context.Context, userID, id int) error {
+	deleted, err := s.repo.DeleteSession(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return domain.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (s *ScrobbleService) GetScrobbles(ctx context.Context, userID int, req dto.ListRequest) ([]dto.Response, error) {
+	scrobbles, err := s.repo.GetScrobbles(ctx, userID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToResponses(scrobbles), nil
+}
+
+// GetNowPlaying возвращает текущий трек пользователя или nil, если ничего не играет
+func (s *ScrobbleService) GetNowPlaying(ctx context.Context, userID int) (*dto.NowPlayingResponse, error) {
+	n, err := s.repo.GetNowPlaying(ctx, userID, time.Now())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := dto.ToNowPlayingResponse(*n)
+	return &res, nil
+}
+
+func (s *ScrobbleService) GetSummary(ctx context.Context, userID int, req dto.StatsRequest) (*dto.SummaryResponse, error) {
+	summary, err := s.repo.GetSummary(ctx, userID, req.Period.Since(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.SummaryResponse{
+		Period:    req.Period,
+		Scrobbles: summary.Scrobbles,
+		Artists:   summary.Artists,
+		Songs:     summary.Songs,
+		First:     summary.First,
+		Last:      summary.Last,
+	}, nil
+}
+
+func (s *ScrobbleService) GetTopArtists(
+	ctx context.Context,
+	userID int,
+	req dto.StatsRequest,
+) ([]dto.ArtistStatResponse, error) {
+	stats, err := s.repo.GetTopArtists(ctx, userID, req.Period.Since(time.Now()), req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToArtistStatResponses(stats), nil
+}
+
+func (s *ScrobbleService) GetTopSongs(ctx context.Context, userID int, req dto.StatsRequest) ([]dto.SongStatResponse, error) {
+	stats, err := s.repo.GetTopSongs(ctx, userID, req.Period.Since(time.Now()), req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ToSongStatResponses(stats), nil
+}
+
+func (s *ScrobbleService) createSession(ctx context.Context, userID int, username, client string) (*domain.Session, error) {
+	key, err := newScrobbleKey()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		Key:       key,
+		UserID:    userID,
+		Username:  username,
+		Client:    client,
+		CreatedAt: time.Now(),
+	}
+	if err = s.repo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// lockToken блокирует токен внутри UnitOfWork. Просроченный не удаляется:
+// ошибка откатит транзакцию, а такие токены чистит CreateToken.
+func (s *ScrobbleService) lockToken(ctx context.Context, token string) (*domain.Token, error) {
+	t, err := s.repo.LockToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrTokenNotFound
+		}
+		return nil, err
+	}
+
+	if t.Expired(time.Now()) {
+		return nil, domain.ErrTokenExpired
+	}
+
+	return t, nil
+}
+
+// newScrobbleKey 32 шестнадцатеричных символа, как у ключей Last.fm
+func newScrobbleKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// trackMatcher сопоставляет строки скробблера с каталогом; исполнители
+// запоминаются на время одного пакета
+type trackMatcher struct {
+	repo    ScrobbleRepository
+	artists map[string]*int
+}
+
+func newTrackMatcher(repo ScrobbleRepository) *trackMatcher {
+	return &trackMatcher{
+		repo:    repo,
+		artists: make(map[string]*int),
+	}
+}
+
+func (m *trackMatcher) match(ctx context.Context, t *domain.Track) error {
+	if t.MBID != "" {
+		songID, artistID, err := m.repo.MatchMBID(ctx, t.MBID)
+		if err != nil {
+			return err
+		}
+		if songID != nil {
+			t.SongID, t.ArtistID = songID, artistID
+			return nil
+		}
+	}
+
+	name := strings.ToLower(t.Artist)
+	artistID, ok := m.artists[name]
+	if !ok {
+		var err error
+		if artistID, err = m.repo.MatchArtist(ctx, t.Artist); err != nil {
+			return err
+		}
+		m.artists[name] = artistID
+	}
+	if artistID == nil {
+		return nil
+	}
+	t.ArtistID = artistID
+
+	songID, err := m.repo.MatchSong(ctx, *artistID, t.Track, t.Album)
+	if err != nil {
+		return err
+	}
+	t.SongID = songID
+
+	return nil
+}
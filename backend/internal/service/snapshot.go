@@ -0,0 +1,413 @@
+package service
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/snapshot"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+const staticDir = "static"
+
+type SnapshotRepository interface {
+	ReadSnapshot(ctx context.Context, fn func(ctx context.Context) error) error
+	ExportGenres(ctx context.Context) ([]domain.Genre, error)
+	ExportArtists(ctx context.Context) ([]domain.Artist, error)
+	ExportAlbums(ctx context.Context) ([]domain.Album, error)
+	ExportSongs(ctx context.Context) ([]domain.Song, error)
+	ExportLyrics(ctx context.Context) ([]domain.Lyrics, error)
+	ExportReviews(ctx context.Context) ([]domain.Review, error)
+	Restore(ctx context.Context, data *domain.Data) (*domain.RestoreResult, error)
+}
+
+type SnapshotService struct {
+	repo   SnapshotRepository
+	logger *logger.Logger
+}
+
+func NewSnapshotService(repo SnapshotRepository, logger *logger.Logger) *SnapshotService {
+	return &SnapshotService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Export пишет в w tar.gz архив: manifest.json, файлы JSON Lines
+// по каждой сущности и загруженные изображения в каталоге images/.
+// Сущности читаются из одного снимка базы, чтобы ссылки между файлами сходились
+func (s *SnapshotService) Export(ctx context.Context, w io.Writer, includeReviews bool) error {
+	data := &domain.Data{}
+
+	err := s.repo.ReadSnapshot(ctx, func(ctx context.Context) error {
+		var err error
+		if data.Genres, err = s.repo.ExportGenres(ctx); err != nil {
+			return err
+		}
+		if data.Artists, err = s.repo.ExportArtists(ctx); err != nil {
+			return err
+		}
+		if data.Albums, err = s.repo.ExportAlbums(ctx); err != nil {
+			return err
+		}
+		if data.Songs, err = s.repo.ExportSongs(ctx); err != nil {
+			return err
+		}
+		if data.Lyrics, err = s.repo.ExportLyrics(ctx); err != nil {
+			return err
+		}
+		if includeReviews {
+			if data.Reviews, err = s.repo.ExportReviews(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	images := collectImages(data)
+
+	data.Manifest = domain.Manifest{
+		Version:        domain.FormatVersion,
+		CreatedAt:      time.Now().UTC(),
+		IncludeReviews: includeReviews,
+		Files: []domain.FileInfo{
+			{Name: domain.GenresFile, Count: len(data.Genres)},
+			{Name: domain.ArtistsFile, Count: len(data.Artists)},
+			{Name: domain.AlbumsFile, Count: len(data.Albums)},
+			{Name: domain.SongsFile, Count: len(data.Songs)},
+			{Name: domain.LyricsFile, Count: len(data.Lyrics)},
+		},
+	}
+	if includeReviews {
+		data.Manifest.Files = append(data.Manifest.Files,
+			domain.FileInfo{Name: domain.ReviewsFile, Count: len(data.Reviews)})
+	}
+
+	var packed []string
+	for _, img := range images {
+		if _, err := os.Stat(filepath.Join(staticDir, img)); err != nil {
+			s.logger.Error("snapshot image not found, skipping", "path", img, "error", err)
+			continue
+		}
+		packed = append(packed, img)
+	}
+	data.Manifest.Images = len(packed)
+
+	manifest, err := json.MarshalIndent(data.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{domain.ManifestFile: manifest}
+	if files[domain.GenresFile], err = encodeJSONLines(data.Genres); err != nil {
+		return err
+	}
+	if files[domain.ArtistsFile], err = encodeJSONLines(data.Artists); err != nil {
+		return err
+	}
+	if files[domain.AlbumsFile], err = encodeJSONLines(data.Albums); err != nil {
+		return err
+	}
+	if files[domain.SongsFile], err = encodeJSONLines(data.Songs); err != nil {
+		return err
+	}
+	if files[domain.LyricsFile], err = encodeJSONLines(data.Lyrics); err != nil {
+		return err
+	}
+	if includeReviews {
+		if files[domain.ReviewsFile], err = encodeJSONLines(data.Reviews); err != nil {
+			return err
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	// манифест идёт первым, чтобы читатель мог проверить версию до данных
+	order := []string{domain.ManifestFile}
+	for _, f := range data.Manifest.Files {
+		order = append(order, f.Name)
+	}
+	for _, name := range order {
+		if err = writeTarFile(tw, name, files[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, img := range packed {
+		if err := writeTarImage(tw, img); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Restore читает архив, созданный Export, и восстанавливает каталог в пустой базе.
+// Изображения распаковываются во временный каталог и переносятся в static/
+// только после успешного восстановления базы.
+func (s *SnapshotService) Restore(ctx context.Context, r io.Reader) (*domain.RestoreResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot archive: %w", err)
+	}
+	defer gz.Close()
+
+	if err = os.MkdirAll(staticDir, 0755); err != nil {
+		return nil, err
+	}
+	// временный каталог внутри static/, чтобы перенос был переименованием
+	tmpDir, err := os.MkdirTemp(staticDir, ".snapshot-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	data := &domain.Data{}
+	var images []string
+	var hasManifest bool
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		switch {
+		case hdr.Name == domain.ManifestFile:
+			if err = json.NewDecoder(tr).Decode(&data.Manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			if v := data.Manifest.Version; v < domain.MinFormatVersion || v > domain.FormatVersion {
+				return nil, fmt.Errorf("%w: %d", domain.ErrUnsupportedVersion, data.Manifest.Version)
+			}
+			hasManifest = true
+		case hdr.Name == domain.GenresFile:
+			err = decodeJSONLines(tr, &data.Genres)
+		case hdr.Name == domain.ArtistsFile:
+			err = decodeJSONLines(tr, &data.Artists)
+		case hdr.Name == domain.AlbumsFile:
+			err = decodeJSONLines(tr, &data.Albums)
+		case hdr.Name == domain.SongsFile:
+			err = decodeJSONLines(tr, &data.Songs)
+		case hdr.Name == domain.LyricsFile:
+			err = decodeJSONLines(tr, &data.Lyrics)
+		case hdr.Name == domain.ReviewsFile:
+			err = decodeJSONLines(tr, &data.Reviews)
+		case strings.HasPrefix(hdr.Name, domain.ImagesDir):
+			var img string
+			if img, err = extractImage(tr, tmpDir, strings.TrimPrefix(hdr.Name, domain.ImagesDir)); err == nil {
+				images = append(images, img)
+			}
+		default:
+			s.logger.Info("unknown snapshot entry, skipping", "name", hdr.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+	}
+
+	if !hasManifest {
+		return nil, domain.ErrManifestMissing
+	}
+
+	for _, f := range data.Manifest.Files {
+		if got := countRecords(data, f.Name); got != f.Count {
+			return nil, fmt.Errorf("%s: expected %d records, got %d", f.Name, f.Count, got)
+		}
+	}
+
+	res, err := s.repo.Restore(ctx, data)
+	if err != nil {
+		s.logger.Error("failed to restore snapshot", "error", err)
+		return nil, err
+	}
+
+	// база уже восстановлена, поэтому ошибка с отдельным файлом не прерывает перенос
+	for _, img := range images {
+		if err := moveImage(tmpDir, img); err != nil {
+			s.logger.Error("failed to move snapshot image", "path", img, "error", err)
+		}
+	}
+
+	return res, nil
+}
+
+func collectImages(data *domain.Data) []string {
+	seen := make(map[string]struct{})
+	var images []string
+
+	add := func(p string) {
+		if p == "" {
+			return
+		}
+		if _, ok := seen[p]; ok {
+			return
+		}
+		seen[p] = struct{}{}
+		images = append(images, p)
+	}
+
+	for _, g := range data.Genres {
+		add(g.ImageURL)
+	}
+	for _, a := range data.Albums {
+		add(a.ImageURL)
+	}
+	for _, s := range data.Songs {
+		add(s.ImageURL)
+	}
+
+	return images
+}
+
+func countRecords(data *domain.Data, name string) int {
+	switch name {
+	case domain.GenresFile:
+		return len(data.Genres)
+	case domain.ArtistsFile:
+		return len(data.Artists)
+	case domain.AlbumsFile:
+		return len(data.Albums)
+	case domain.SongsFile:
+		return len(data.Songs)
+	case domain.LyricsFile:
+		return len(data.Lyrics)
+	case domain.ReviewsFile:
+		return len(data.Reviews)
+	}
+	return 0
+}
+
+func encodeJSONLines[T any](records []T) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeJSONLines[T any](r io.Reader, out *[]T) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16<<20)
+
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var v T
+		if err := json.Unmarshal(line, &v); err != nil {
+			return err
+		}
+		*out = append(*out, v)
+	}
+
+	return sc.Err()
+}
+
+func writeTarFile(tw *tar.Writer, name string, body []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(body)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err := tw.Write(body)
+	return err
+}
+
+func writeTarImage(tw *tar.Writer, imagePath string) error {
+	f, err := os.Open(filepath.Join(staticDir, imagePath))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr := &tar.Header{
+		Name:    domain.ImagesDir + filepath.ToSlash(imagePath),
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err = tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// extractImage распаковывает изображение в dir и возвращает его путь относительно static/
+func extractImage(r io.Reader, dir, name string) (string, error) {
+	clean := path.Clean("/" + name)[1:]
+	if !strings.HasPrefix(clean, "uploads/") {
+		return "", fmt.Errorf("image path %q is outside uploads", name)
+	}
+
+	dst := filepath.Join(dir, filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	if _, err = io.Copy(out, r); err != nil {
+		return "", err
+	}
+	return clean, nil
+}
+
+// moveImage переносит распакованное изображение из dir в static/
+func moveImage(dir, img string) error {
+	dst := filepath.Join(staticDir, filepath.FromSlash(img))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	return os.Rename(filepath.Join(dir, filepath.FromSlash(img)), dst)
+}
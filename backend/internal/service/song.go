@@ -2,7 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+
+	"github.com/maYkiss56/tunes/internal/domain/audit"
+	"github.com/maYkiss56/tunes/internal/domain/library"
+	libraryDTO "github.com/maYkiss56/tunes/internal/domain/library/dto"
+	"github.com/maYkiss56/tunes/internal/domain/review"
+	"github.com/maYkiss56/tunes/internal/domain/slug"
 	domain "github.com/maYkiss56/tunes/internal/domain/song"
 	"github.com/maYkiss56/tunes/internal/domain/song/dto"
 	"github.com/maYkiss56/tunes/internal/logger"
@@ -11,57 +20,192 @@ import (
 type SongRepository interface {
 	CreateSong(ctx context.Context, song *domain.Song) error
 	GetSongRating(ctx context.Context, songID int) (int, int, int, error)
-	GetAllSongsSortedByRating(ctx context.Context) ([]dto.Response, error)
-	GetTopSongs(ctx context.Context, timeRange string, limit int) ([]dto.Response, error)
+	GetAllSongsSortedByRating(ctx context.Context, rank domain.Rank) ([]dto.Response, error)
+	GetTopSongs(ctx context.Context, timeRange string, limit int, rank domain.Rank) ([]dto.Response, error)
 	GetAllSongs(ctx context.Context) ([]dto.Response, error)
 	GetSongByID(ctx context.Context, id int) (*dto.Response, error)
-	UpdateSongRating(ctx context.Context, songID int) error
+	CountPublicLists(ctx context.Context, songID int) (int, error)
+	ApplyRatingDelta(ctx context.Context, songID int, d review.RatingDelta) error
 	UpdateSong(ctx context.Context, id int, update dto.UpdateSongRequest) error
 	DeleteSong(ctx context.Context, id int) error
+	RestoreSong(ctx context.Context, id int) error
+	GetSongBySlug(ctx context.Context, slug string) (*dto.Response, error)
+	GetSongSlugRedirect(ctx context.Context, slug string) (string, error)
+	SearchSongs(ctx context.Context, q string, limit int) ([]dto.SearchResult, error)
+	RefreshRankings(ctx context.Context) (int64, error)
 }
 
 type SongService struct {
-	repo   SongRepository
-	logger *logger.Logger
+	repo    SongRepository
+	library LibraryStatuses
+	auditor Auditor
+	uow     UnitOfWork
+	logger  *logger.Logger
 }
 
-func NewSongService(repo SongRepository, logger *logger.Logger) *SongService {
+func NewSongService(
+	repo SongRepository,
+	library LibraryStatuses,
+	auditor Auditor,
+	uow UnitOfWork,
+	logger *logger.Logger,
+) *SongService {
 	return &SongService{
-		repo:   repo,
-		logger: logger,
+		repo:    repo,
+		library: library,
+		auditor: auditor,
+		uow:     uow,
+		logger:  logger,
 	}
 }
 
+// CreateSong сохраняет песню и запись журнала аудита в одной транзакции
 func (s *SongService) CreateSong(ctx context.Context, song *domain.Song) error {
-	return s.repo.CreateSong(ctx, song)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateSong(ctx, song); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntitySong, song.ID, audit.ActionCreate, nil, s.auditState(ctx, song.ID))
+	})
 }
 
 func (s *SongService) GetSongRating(ctx context.Context, songID int) (int, int, int, error) {
 	return s.repo.GetSongRating(ctx, songID)
 }
 
-func (s *SongService) GetAllSongsSortedByRating(ctx context.Context) ([]dto.Response, error) {
-	return s.repo.GetAllSongsSortedByRating(ctx)
+func (s *SongService) GetAllSongsSortedByRating(
+	ctx context.Context,
+	viewerID int,
+	rank domain.Rank,
+) ([]dto.Response, error) {
+	songs, err := s.repo.GetAllSongsSortedByRating(ctx, rank)
+	if err != nil {
+		return nil, err
+	}
+
+	return songs, s.attachLibrary(ctx, viewerID, songPointers(songs))
+}
+
+func (s *SongService) GetTopSongs(
+	ctx context.Context,
+	viewerID int,
+	timeRange string,
+	limit int,
+	rank domain.Rank,
+) ([]dto.Response, error) {
+	songs, err := s.repo.GetTopSongs(ctx, timeRange, limit, rank)
+	if err != nil {
+		return nil, err
+	}
+
+	return songs, s.attachLibrary(ctx, viewerID, songPointers(songs))
+}
+
+// RefreshRankings пересчитывает ранги песен; нужен периодически,
+// так как байесовский априор сдвигается с каждым голосом
+func (s *SongService) RefreshRankings(ctx context.Context) error {
+	n, err := s.repo.RefreshRankings(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Song rankings refreshed", "songs", n)
+
+	return nil
 }
 
-func (s *SongService) GetTopSongs(ctx context.Context, timeRange string, limit int) ([]dto.Response, error) {
-	return s.repo.GetTopSongs(ctx, timeRange, limit)
+// RunRankRefresh периодически пересчитывает ранги до отмены контекста
+func (s *SongService) RunRankRefresh(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.RefreshRankings(ctx)
+		}
+	}
 }
 
-func (s *SongService) GetAllSongs(ctx context.Context) ([]dto.Response, error) {
+func (s *SongService) GetAllSongs(ctx context.Context, viewerID int) ([]dto.Response, error) {
 	songs, err := s.repo.GetAllSongs(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return songs, nil
+
+	return songs, s.attachLibrary(ctx, viewerID, songPointers(songs))
+}
+
+func (s *SongService) SearchSongs(ctx context.Context, viewerID int, q string, limit int) ([]dto.SearchResult, error) {
+	results, err := s.repo.SearchSongs(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	songs := make([]*dto.Response, 0, len(results))
+	for i := range results {
+		songs = append(songs, &results[i].Song)
+	}
+
+	return results, s.attachLibrary(ctx, viewerID, songs)
 }
 
 func (s *SongService) GetSongByID(ctx context.Context, id int) (*dto.Response, error) {
 	return s.repo.GetSongByID(ctx, id)
 }
 
-func (s *SongService) UpdateSongRating(ctx context.Context, songID int) error {
-	return s.repo.UpdateSongRating(ctx, songID)
+// GetSongDetails возвращает карточку песни: к GetSongByRef добавляется
+// число публичных списков, в которые она входит
+func (s *SongService) GetSongDetails(ctx context.Context, viewerID int, ref string) (*dto.Response, error) {
+	res, err := s.GetSongByRef(ctx, viewerID, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := s.repo.CountPublicLists(ctx, res.ID)
+	if err != nil {
+		return nil, err
+	}
+	res.ListCount = &lists
+
+	return res, nil
+}
+
+// GetSongByRef принимает id или слаг. Для слага из истории
+// возвращается *slug.MovedError с текущим слагом.
+func (s *SongService) GetSongByRef(ctx context.Context, viewerID int, ref string) (*dto.Response, error) {
+	var (
+		res *dto.Response
+		err error
+	)
+	id, isID := slug.ParseID(ref)
+	if isID {
+		res, err = s.repo.GetSongByID(ctx, id)
+	} else {
+		res, err = s.repo.GetSongBySlug(ctx, ref)
+	}
+	if err == nil {
+		return res, s.attachLibrary(ctx, viewerID, []*dto.Response{res})
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if isID {
+		return nil, domain.ErrNotFound
+	}
+
+	current, err := s.repo.GetSongSlugRedirect(ctx, ref)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return nil, &slug.MovedError{Slug: current}
 }
 
 func (s *SongService) UpdateSong(
@@ -69,9 +213,77 @@ func (s *SongService) UpdateSong(
 	id int,
 	update dto.UpdateSongRequest,
 ) error {
-	return s.repo.UpdateSong(ctx, id, update)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, id)
+
+		if err := s.repo.UpdateSong(ctx, id, update); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntitySong, id, audit.ActionUpdate, before, s.auditState(ctx, id))
+	})
 }
 
 func (s *SongService) DeleteSong(ctx context.Context, id int) error {
-	return s.repo.DeleteSong(ctx, id)
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		before := s.auditState(ctx, id)
+
+		if err := s.repo.DeleteSong(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntitySong, id, audit.ActionDelete, before, nil)
+	})
+}
+
+func (s *SongService) RestoreSong(ctx context.Context, id int) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.RestoreSong(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditor.Record(ctx, audit.EntitySong, id, audit.ActionRestore, nil, s.auditState(ctx, id))
+	})
+}
+
+// attachLibrary добавляет к песням отметки библиотеки зрителя одним запросом
+// на страницу; анонимному зрителю ничего не добавляется
+func (s *SongService) attachLibrary(ctx context.Context, viewerID int, songs []*dto.Response) error {
+	if viewerID == 0 || len(songs) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(songs))
+	for _, song := range songs {
+		ids = append(ids, song.ID)
+	}
+
+	statuses, err := s.library.GetStatuses(ctx, viewerID, library.KindSong, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, song := range songs {
+		status := libraryDTO.ToStatusResponse(statuses[song.ID])
+		song.Library = &status
+	}
+
+	return nil
+}
+
+func songPointers(songs []dto.Response) []*dto.Response {
+	res := make([]*dto.Response, 0, len(songs))
+	for i := range songs {
+		res = append(res, &songs[i])
+	}
+	return res
+}
+
+// auditState возвращает текущее состояние песни для журнала аудита
+func (s *SongService) auditState(ctx context.Context, id int) any {
+	song, err := s.repo.GetSongByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return song
 }
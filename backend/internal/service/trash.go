@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/maYkiss56/tunes/internal/domain/trash"
+	"github.com/maYkiss56/tunes/internal/domain/trash/dto"
+	"github.com/maYkiss56/tunes/internal/logger"
+)
+
+type TrashRepository interface {
+	ListTrash(ctx context.Context, entity string) ([]domain.Item, error)
+	Purge(ctx context.Context, before time.Time) (*domain.PurgeResult, error)
+}
+
+type TrashService struct {
+	repo      TrashRepository
+	retention time.Duration
+	logger    *logger.Logger
+}
+
+func NewTrashService(repo TrashRepository, retention time.Duration, logger *logger.Logger) *TrashService {
+	return &TrashService{
+		repo:      repo,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+func (s *TrashService) ListTrash(ctx context.Context, entity string) ([]dto.Response, error) {
+	items, err := s.repo.ListTrash(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.Response, 0, len(items))
+	for _, item := range items {
+		res = append(res, dto.ToResponse(item, s.retention))
+	}
+
+	return res, nil
+}
+
+// Purge удаляет всё, что пролежало в корзине дольше срока хранения
+func (s *TrashService) Purge(ctx context.Context) (*domain.PurgeResult, error) {
+	res, err := s.repo.Purge(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		s.logger.Error("failed to purge trash", "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("Trash purged",
+		"reviews", res.Reviews,
+		"songs", res.Songs,
+		"albums", res.Albums,
+		"artists", res.Artists,
+		"genres", res.Genres,
+	)
+
+	return res, nil
+}
+
+// RunPurge периодически очищает корзину до отмены контекста
+func (s *TrashService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.Purge(ctx)
+		}
+	}
+}
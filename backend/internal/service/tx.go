@@ -0,0 +1,9 @@
+package service
+
+import "context"
+
+// UnitOfWork выполняет fn в одной транзакции: репозитории, вызванные
+// с переданным контекстом, пишут в неё, ошибка fn откатывает всё
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
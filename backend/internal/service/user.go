@@ -14,7 +14,7 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetUserByID(ctx context.Context, id int) (*domain.User, error)
-	GetTopReviewers(ctx context.Context) ([]dto.TopResponse, error)
+	GetTopReviewers(ctx context.Context, by string) ([]dto.TopResponse, error)
 	UpdateUserAvatar(ctx context.Context, id int, req dto.UpdateAvatarRequest) error
 	UpdateUserPassword(ctx context.Context, id int, req dto.UpdatePasswordRequest) error
 	UpdateUserRequest(ctx context.Context, id int, req dto.UpdateUsersRequest) error
@@ -66,8 +66,8 @@ func (s *UserService) GetUserByID(ctx context.Context, id int) (*domain.User, er
 	return user, nil
 }
 
-func (s *UserService) GetTopReviewers(ctx context.Context) ([]dto.TopResponse, error) {
-	users, err := s.repo.GetTopReviewers(ctx)
+func (s *UserService) GetTopReviewers(ctx context.Context, by string) ([]dto.TopResponse, error) {
+	users, err := s.repo.GetTopReviewers(ctx, by)
 	if err != nil {
 		s.logger.Error("failed to get reviewers", "error", err)
 		return nil, err
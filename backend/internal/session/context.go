@@ -18,6 +18,14 @@ func FromContext(ctx context.Context) *Session {
 	return &s
 }
 
+// UserID возвращает id вошедшего пользователя, 0 для анонимного запроса
+func UserID(ctx context.Context) int {
+	if s := FromContext(ctx); s != nil {
+		return s.UserID
+	}
+	return 0
+}
+
 //
 // type sessionKey struct{}
 //
@@ -0,0 +1,9 @@
+drop index if exists artist_mbid_key;
+drop index if exists album_mbid_key;
+drop index if exists album_upc_key;
+drop index if exists song_mbid_key;
+drop index if exists song_isrc_key;
+
+alter table artist drop column if exists mbid;
+alter table album drop column if exists upc, drop column if exists mbid;
+alter table song drop column if exists isrc, drop column if exists mbid;
@@ -0,0 +1,16 @@
+alter table song
+    add column isrc varchar(12),
+    add column mbid uuid;
+
+alter table album
+    add column upc varchar(13),
+    add column mbid uuid;
+
+alter table artist
+    add column mbid uuid;
+
+create unique index song_isrc_key on song (isrc) where isrc is not null;
+create unique index song_mbid_key on song (mbid) where mbid is not null;
+create unique index album_upc_key on album (upc) where upc is not null;
+create unique index album_mbid_key on album (mbid) where mbid is not null;
+create unique index artist_mbid_key on artist (mbid) where mbid is not null;
@@ -0,0 +1,9 @@
+drop index if exists song_deleted_at_idx;
+drop index if exists album_deleted_at_idx;
+drop index if exists artist_deleted_at_idx;
+drop index if exists genre_deleted_at_idx;
+
+alter table song drop column if exists deleted_at;
+alter table album drop column if exists deleted_at;
+alter table artist drop column if exists deleted_at;
+alter table genre drop column if exists deleted_at;
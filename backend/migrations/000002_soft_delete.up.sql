@@ -0,0 +1,9 @@
+alter table genre add column deleted_at timestamptz;
+alter table artist add column deleted_at timestamptz;
+alter table album add column deleted_at timestamptz;
+alter table song add column deleted_at timestamptz;
+
+create index genre_deleted_at_idx on genre (deleted_at) where deleted_at is not null;
+create index artist_deleted_at_idx on artist (deleted_at) where deleted_at is not null;
+create index album_deleted_at_idx on album (deleted_at) where deleted_at is not null;
+create index song_deleted_at_idx on song (deleted_at) where deleted_at is not null;
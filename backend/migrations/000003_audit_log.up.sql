@@ -0,0 +1,27 @@
+create table audit_log (
+    id          bigserial primary key,
+    actor_id    int,
+    actor_email varchar(255) not null default '',
+    entity      varchar(32) not null,
+    entity_id   int not null,
+    action      varchar(32) not null,
+    before      jsonb,
+    after       jsonb,
+    changes     jsonb not null default '{}',
+    created_at  timestamptz not null default now()
+);
+
+create index audit_log_actor_idx on audit_log (actor_id, created_at desc);
+create index audit_log_entity_idx on audit_log (entity, entity_id, created_at desc);
+create index audit_log_created_at_idx on audit_log (created_at desc);
+
+-- журнал только дописывается
+create function audit_log_append_only() returns trigger as $$
+begin
+    raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+
+create trigger audit_log_append_only
+    before update or delete on audit_log
+    for each row execute function audit_log_append_only();
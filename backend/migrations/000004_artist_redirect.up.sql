@@ -0,0 +1,8 @@
+-- старый идентификатор артиста после слияния указывает на оставшегося
+create table artist_redirect (
+    from_id   int primary key,
+    to_id     int not null references artist (id) on delete cascade,
+    merged_at timestamptz not null default now()
+);
+
+create index artist_redirect_to_id_idx on artist_redirect (to_id);
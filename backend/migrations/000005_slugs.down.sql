@@ -0,0 +1,5 @@
+drop table if exists slug_history;
+
+alter table song drop column if exists slug;
+alter table album drop column if exists slug;
+alter table artist drop column if exists slug;
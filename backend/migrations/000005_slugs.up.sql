@@ -0,0 +1,20 @@
+alter table artist add column slug varchar(160);
+alter table album add column slug varchar(160);
+alter table song add column slug varchar(160);
+
+create unique index artist_slug_key on artist (slug);
+create unique index album_slug_key on album (slug);
+create unique index song_slug_key on song (slug);
+
+-- прежние слаги продолжают вести на сущность после переименования
+create table slug_history (
+    entity     varchar(16) not null,
+    slug       varchar(160) not null,
+    entity_id  int not null,
+    created_at timestamptz not null default now(),
+    primary key (entity, slug)
+);
+
+create index slug_history_entity_idx on slug_history (entity, entity_id);
+
+-- слаги для существующих строк заполняет команда `tunes slugs`
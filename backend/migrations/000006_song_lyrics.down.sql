@@ -0,0 +1,2 @@
+drop index if exists song_title_search_idx;
+drop table if exists song_lyrics;
@@ -0,0 +1,13 @@
+create table song_lyrics (
+    song_id    int primary key references song (id) on delete cascade,
+    plain      text not null,
+    lrc        text not null default '',
+    updated_at timestamptz not null default now(),
+    -- конфигурация simple одинаково работает для русского и английского текста
+    search     tsvector generated always as (to_tsvector('simple', plain)) stored
+);
+
+create index song_lyrics_search_idx on song_lyrics using gin (search);
+
+create index song_title_search_idx on song
+    using gin (to_tsvector('simple', title || ' ' || full_title));
@@ -0,0 +1,6 @@
+alter table song
+    drop column if exists score_histogram,
+    drop column if exists score_count,
+    drop column if exists score_avg;
+
+alter table review drop column if exists score;
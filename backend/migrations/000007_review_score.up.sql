@@ -0,0 +1,7 @@
+alter table review add column score smallint check (score between 1 and 10);
+
+alter table song
+    add column score_avg numeric(4, 2),
+    add column score_count int not null default 0,
+    -- score_histogram[i] — число оценок i
+    add column score_histogram int[] not null default array_fill(0, array[10]);
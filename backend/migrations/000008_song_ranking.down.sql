@@ -0,0 +1,13 @@
+drop index if exists song_rank_hot_idx;
+drop index if exists song_rank_bayesian_idx;
+drop index if exists song_rank_wilson_idx;
+
+alter table song
+    drop column if exists rank_hot,
+    drop column if exists rank_bayesian,
+    drop column if exists rank_wilson;
+
+drop view if exists song_rank_prior;
+drop function if exists hot_score(int, timestamptz);
+drop function if exists bayesian_average(int, int, double precision, double precision);
+drop function if exists wilson_lower_bound(int, int);
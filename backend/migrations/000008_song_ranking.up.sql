@@ -0,0 +1,46 @@
+-- нижняя граница доверительного интервала Уилсона для доли лайков (z = 1.96, 95%)
+create function wilson_lower_bound(pos int, neg int) returns double precision
+language sql immutable as $$
+    select case when pos + neg = 0 then 0 else
+        (p + z * z / (2 * n) - z * sqrt((p * (1 - p) + z * z / (4 * n)) / n)) / (1 + z * z / n)
+    end
+    from (select pos::float8 / nullif(pos + neg, 0) as p, (pos + neg)::float8 as n, 1.96::float8 as z) v
+$$;
+
+-- доля лайков, сглаженная к prior_mean с весом prior_weight голосов
+create function bayesian_average(pos int, neg int, prior_mean double precision, prior_weight double precision)
+returns double precision
+language sql immutable as $$
+    select (pos + prior_weight * prior_mean) / nullif(pos + neg + prior_weight, 0)
+$$;
+
+-- "горячесть": порядок баланса голосов плюс бонус за свежесть,
+-- каждые 12.5 часов весят как десятикратный перевес голосов
+create function hot_score(net int, created timestamptz) returns double precision
+language sql immutable as $$
+    select sign(net) * log(greatest(abs(net), 1)) + extract(epoch from created) / 45000
+$$;
+
+-- глобальный априор для байесовского среднего: общая доля лайков
+-- и среднее число голосов у песни, за которую голосовали
+create view song_rank_prior as
+select
+    coalesce(sum(like_count)::float8 / nullif(sum(like_count + dislike_count), 0), 0.5) as mean,
+    coalesce(avg(like_count + dislike_count) filter (where like_count + dislike_count > 0), 1)::float8 as weight
+from song
+where deleted_at is null;
+
+alter table song
+    add column rank_wilson double precision not null default 0,
+    add column rank_bayesian double precision not null default 0,
+    add column rank_hot double precision not null default 0;
+
+update song s set
+    rank_wilson = wilson_lower_bound(s.like_count, s.dislike_count),
+    rank_bayesian = coalesce(bayesian_average(s.like_count, s.dislike_count, p.mean, p.weight), 0),
+    rank_hot = hot_score(s.like_count - s.dislike_count, s.created_at)
+from song_rank_prior p;
+
+create index song_rank_wilson_idx on song (rank_wilson desc) where deleted_at is null;
+create index song_rank_bayesian_idx on song (rank_bayesian desc) where deleted_at is null;
+create index song_rank_hot_idx on song (rank_hot desc) where deleted_at is null;
@@ -0,0 +1,2 @@
+-- удалённые при слиянии дубли не восстанавливаются
+alter table review drop constraint if exists review_user_song_key;
@@ -0,0 +1,54 @@
+-- у пользователя остаётся одна рецензия на песню: самая свежая из дублей
+create temporary table review_duplicate_song as
+select distinct song_id
+from (
+    select song_id from review
+    group by user_id, song_id
+    having count(*) > 1
+) d;
+
+delete from review r
+using (
+    select id, row_number() over (partition by user_id, song_id order by updated_at desc, id desc) as n
+    from review
+) d
+where r.id = d.id and d.n > 1;
+
+-- пересчитываем счётчики и оценки затронутых песен
+update song s set
+    like_count = c.like_count,
+    dislike_count = c.dislike_count,
+    rating = c.like_count - c.dislike_count,
+    score_count = c.score_count,
+    score_avg = c.score_avg,
+    score_histogram = c.score_histogram
+from (
+    select s.id,
+    count(r.id) filter (where r.is_like = true) as like_count,
+    count(r.id) filter (where r.is_like = false) as dislike_count,
+    count(r.score) as score_count,
+    round(avg(r.score), 2) as score_avg,
+    array[
+        count(r.id) filter (where r.score = 1), count(r.id) filter (where r.score = 2),
+        count(r.id) filter (where r.score = 3), count(r.id) filter (where r.score = 4),
+        count(r.id) filter (where r.score = 5), count(r.id) filter (where r.score = 6),
+        count(r.id) filter (where r.score = 7), count(r.id) filter (where r.score = 8),
+        count(r.id) filter (where r.score = 9), count(r.id) filter (where r.score = 10)
+    ]::int[] as score_histogram
+    from song s
+    left join review r on r.song_id = s.id and r.is_valid = true
+    where s.id in (select song_id from review_duplicate_song)
+    group by s.id
+) c
+where s.id = c.id;
+
+-- глобальный априор сдвинулся, поэтому ранги пересчитываются у всех песен
+update song s set
+    rank_wilson = wilson_lower_bound(s.like_count, s.dislike_count),
+    rank_bayesian = coalesce(bayesian_average(s.like_count, s.dislike_count, p.mean, p.weight), 0),
+    rank_hot = hot_score(s.like_count - s.dislike_count, s.created_at)
+from song_rank_prior p;
+
+drop table review_duplicate_song;
+
+alter table review add constraint review_user_song_key unique (user_id, song_id);
@@ -0,0 +1 @@
+alter table song drop column if exists score_sum;
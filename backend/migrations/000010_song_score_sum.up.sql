@@ -0,0 +1,11 @@
+-- сумма оценок позволяет менять среднее приращениями без пересчёта по всем рецензиям
+alter table song add column score_sum int not null default 0;
+
+update song s set score_sum = c.score_sum
+from (
+    select song_id, sum(score) as score_sum
+    from review
+    where is_valid = true and score is not null
+    group by song_id
+) c
+where s.id = c.song_id;
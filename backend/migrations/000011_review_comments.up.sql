@@ -0,0 +1,21 @@
+create table review_comment (
+    id serial primary key,
+    review_id int not null references review (id) on delete cascade,
+    parent_id int references review_comment (id) on delete cascade,
+    user_id int not null references users (id) on delete cascade,
+    body text not null,
+    depth int not null default 0,
+    status varchar(16) not null default 'approved'
+        check (status in ('pending', 'approved', 'rejected')),
+    moderated_by int references users (id) on delete set null,
+    reason text not null default '',
+    edited_at timestamptz,
+    -- удалённый автором комментарий остаётся в ветке, чтобы не терять ответы
+    deleted_at timestamptz,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+);
+
+create index review_comment_thread_idx on review_comment (review_id, parent_id, created_at);
+create index review_comment_parent_idx on review_comment (parent_id);
+create index review_comment_status_idx on review_comment (status, created_at) where status <> 'approved';
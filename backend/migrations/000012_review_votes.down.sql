@@ -0,0 +1,8 @@
+drop index if exists review_helpful_idx;
+
+alter table review
+    drop column if exists unhelpful_count,
+    drop column if exists helpful_count;
+
+drop table if exists review_reaction;
+drop table if exists review_vote;
@@ -0,0 +1,25 @@
+create table review_vote (
+    review_id int not null references review (id) on delete cascade,
+    user_id int not null references users (id) on delete cascade,
+    helpful boolean not null,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+    primary key (review_id, user_id)
+);
+
+create table review_reaction (
+    review_id int not null references review (id) on delete cascade,
+    user_id int not null references users (id) on delete cascade,
+    emoji varchar(32) not null,
+    created_at timestamptz not null default now(),
+    primary key (review_id, user_id)
+);
+
+create index review_reaction_review_idx on review_reaction (review_id, emoji);
+
+-- счётчики голосов меняются приращениями в транзакции голосования
+alter table review
+    add column helpful_count int not null default 0,
+    add column unhelpful_count int not null default 0;
+
+create index review_helpful_idx on review (wilson_lower_bound(helpful_count, unhelpful_count) desc);
@@ -0,0 +1,3 @@
+alter table review drop column if exists edited_at;
+
+drop table if exists review_revision;
@@ -0,0 +1,16 @@
+-- прежние версии рецензии; текущая версия хранится в самой review
+create table review_revision (
+    id serial primary key,
+    review_id int not null references review (id) on delete cascade,
+    revision int not null,
+    body text not null,
+    is_like boolean not null,
+    score smallint,
+    -- кто заменил эту версию следующей
+    edited_by int references users (id) on delete set null,
+    created_at timestamptz not null,
+    replaced_at timestamptz not null default now(),
+    unique (review_id, revision)
+);
+
+alter table review add column edited_at timestamptz;
@@ -0,0 +1,30 @@
+alter table artist
+    drop column if exists like_count,
+    drop column if exists dislike_count,
+    drop column if exists rating,
+    drop column if exists score_avg,
+    drop column if exists score_count,
+    drop column if exists score_sum,
+    drop column if exists score_histogram;
+
+alter table album
+    drop column if exists like_count,
+    drop column if exists dislike_count,
+    drop column if exists rating,
+    drop column if exists score_avg,
+    drop column if exists score_count,
+    drop column if exists score_sum,
+    drop column if exists score_histogram;
+
+delete from review where song_id is null;
+
+drop index if exists review_artist_idx;
+drop index if exists review_album_idx;
+
+alter table review
+    drop constraint if exists review_user_artist_key,
+    drop constraint if exists review_user_album_key,
+    drop constraint if exists review_target_check,
+    drop column if exists artist_id,
+    drop column if exists album_id,
+    alter column song_id set not null;
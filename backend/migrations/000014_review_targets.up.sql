@@ -0,0 +1,30 @@
+-- рецензия относится ровно к одной песне, альбому или исполнителю
+alter table review
+    alter column song_id drop not null,
+    add column album_id int references album (id) on delete cascade,
+    add column artist_id int references artist (id) on delete cascade,
+    add constraint review_target_check check (num_nonnulls(song_id, album_id, artist_id) = 1),
+    add constraint review_user_album_key unique (user_id, album_id),
+    add constraint review_user_artist_key unique (user_id, artist_id);
+
+create index review_album_idx on review (album_id) where album_id is not null;
+create index review_artist_idx on review (artist_id) where artist_id is not null;
+
+-- счётчики альбомов и исполнителей ведутся так же, как у песен
+alter table album
+    add column like_count int not null default 0,
+    add column dislike_count int not null default 0,
+    add column rating int not null default 0,
+    add column score_avg numeric(4, 2),
+    add column score_count int not null default 0,
+    add column score_sum int not null default 0,
+    add column score_histogram int[] not null default array_fill(0, array[10]);
+
+alter table artist
+    add column like_count int not null default 0,
+    add column dislike_count int not null default 0,
+    add column rating int not null default 0,
+    add column score_avg numeric(4, 2),
+    add column score_count int not null default 0,
+    add column score_sum int not null default 0,
+    add column score_histogram int[] not null default array_fill(0, array[10]);
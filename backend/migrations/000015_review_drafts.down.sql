@@ -0,0 +1,10 @@
+drop index if exists review_drafts_idx;
+drop index if exists review_scheduled_idx;
+
+delete from review where status <> 'published';
+
+alter table review
+    drop constraint if exists review_publish_at_check,
+    drop column if exists published_at,
+    drop column if exists publish_at,
+    drop column if exists status;
@@ -0,0 +1,12 @@
+-- черновики видны только автору и не входят в счётчики до публикации
+alter table review
+    add column status varchar(16) not null default 'published'
+        check (status in ('draft', 'scheduled', 'published')),
+    add column publish_at timestamptz,
+    add column published_at timestamptz,
+    add constraint review_publish_at_check check (status <> 'scheduled' or publish_at is not null);
+
+update review set published_at = created_at;
+
+create index review_scheduled_idx on review (publish_at) where status = 'scheduled';
+create index review_drafts_idx on review (user_id, updated_at desc) where status <> 'published';
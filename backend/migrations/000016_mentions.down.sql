@@ -0,0 +1,2 @@
+drop table if exists notification;
+drop table if exists mention;
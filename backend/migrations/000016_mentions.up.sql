@@ -0,0 +1,27 @@
+-- упоминания из текста рецензии или биографии исполнителя, сопоставленные с сущностями
+create table mention (
+    id serial primary key,
+    review_id int references review (id) on delete cascade,
+    artist_id int references artist (id) on delete cascade,
+    target_type varchar(16) not null check (target_type in ('user', 'song', 'album', 'artist')),
+    target_id int not null,
+    -- упоминание, как оно записано в тексте: user:alice, song:123, artist:metallica
+    token varchar(180) not null,
+    created_at timestamptz not null default now(),
+    check (num_nonnulls(review_id, artist_id) = 1)
+);
+
+create unique index mention_review_key on mention (review_id, token) where review_id is not null;
+create unique index mention_artist_key on mention (artist_id, token) where artist_id is not null;
+create index mention_target_idx on mention (target_type, target_id);
+
+-- минимальная таблица уведомлений для упоминаний; состояние прочтения
+-- и индексы для ленты уведомлений добавляет 000019_notification_stream
+create table notification (
+    id serial primary key,
+    user_id int not null references users (id) on delete cascade,
+    type varchar(32) not null,
+    actor_id int references users (id) on delete set null,
+    payload jsonb not null default '{}',
+    created_at timestamptz not null default now()
+);
@@ -0,0 +1,9 @@
+drop index if exists review_vote_user_idx;
+drop index if exists review_user_published_idx;
+
+alter table users
+    drop column if exists following_count,
+    drop column if exists follower_count;
+
+drop table if exists user_block;
+drop table if exists user_follow;
@@ -0,0 +1,29 @@
+create table user_follow (
+    follower_id int not null references users (id) on delete cascade,
+    followee_id int not null references users (id) on delete cascade,
+    created_at timestamptz not null default now(),
+    primary key (follower_id, followee_id),
+    check (follower_id <> followee_id)
+);
+
+create index user_follow_followee_idx on user_follow (followee_id, created_at desc);
+
+-- блокировка разрывает подписки в обе стороны и запрещает новые
+create table user_block (
+    blocker_id int not null references users (id) on delete cascade,
+    blocked_id int not null references users (id) on delete cascade,
+    created_at timestamptz not null default now(),
+    primary key (blocker_id, blocked_id),
+    check (blocker_id <> blocked_id)
+);
+
+create index user_block_blocked_idx on user_block (blocked_id);
+
+-- счётчики меняются приращениями в транзакции подписки
+alter table users
+    add column follower_count int not null default 0,
+    add column following_count int not null default 0;
+
+-- лента собирается при чтении из свежих действий подписок
+create index review_user_published_idx on review (user_id, published_at desc) where status = 'published';
+create index review_vote_user_idx on review_vote (user_id, updated_at desc) where helpful;
@@ -0,0 +1,9 @@
+alter table users
+    drop column if exists digest_sent_at,
+    drop column if exists release_digest;
+
+drop table if exists artist_release;
+
+alter table artist drop column if exists follower_count;
+
+drop table if exists artist_follow;
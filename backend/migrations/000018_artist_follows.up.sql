@@ -0,0 +1,39 @@
+create table artist_follow (
+    user_id int not null references users (id) on delete cascade,
+    artist_id int not null references artist (id) on delete cascade,
+    created_at timestamptz not null default now(),
+    primary key (user_id, artist_id)
+);
+
+create index artist_follow_artist_idx on artist_follow (artist_id);
+
+alter table artist add column follower_count int not null default 0;
+
+-- релиз исполнителя: альбом или песня вне уже вышедшего альбома. Строка пишется
+-- в транзакции создания и служит очередью уведомлений, пока notified_at пуст
+create table artist_release (
+    id bigserial primary key,
+    artist_id int not null references artist (id) on delete cascade,
+    song_id int references song (id) on delete cascade,
+    album_id int references album (id) on delete cascade,
+    created_at timestamptz not null default now(),
+    notified_at timestamptz,
+    check (num_nonnulls(song_id, album_id) = 1)
+);
+
+create unique index artist_release_song_key on artist_release (song_id) where song_id is not null;
+create unique index artist_release_album_key on artist_release (album_id) where album_id is not null;
+create index artist_release_artist_idx on artist_release (artist_id, created_at desc);
+create index artist_release_pending_idx on artist_release (id) where notified_at is null;
+
+-- уже существующий каталог считается вышедшим и уведомлений не рассылает
+insert into artist_release (artist_id, album_id, notified_at)
+select artist_id, id, now() from album;
+
+insert into artist_release (artist_id, song_id, notified_at)
+select artist_id, id, now() from song where album_id is null;
+
+alter table users
+    add column release_digest varchar(16) not null default 'off'
+        check (release_digest in ('off', 'daily', 'weekly')),
+    add column digest_sent_at timestamptz;
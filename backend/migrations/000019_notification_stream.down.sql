@@ -0,0 +1,5 @@
+drop trigger if exists notification_notify_trg on notification;
+drop function if exists notification_notify();
+drop index if exists notification_unread_idx;
+drop index if exists notification_user_idx;
+alter table notification drop column if exists read_at;
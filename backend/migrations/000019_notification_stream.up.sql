@@ -0,0 +1,18 @@
+-- центр уведомлений: состояние прочтения и выборка ленты пользователя
+alter table notification add column read_at timestamptz;
+
+create index notification_user_idx on notification (user_id, created_at desc);
+create index notification_unread_idx on notification (user_id, id) where read_at is null;
+
+-- каждое новое уведомление сообщается слушателям канала notification на всех
+-- экземплярах; в сообщении только адресат и id, само уведомление читается из таблицы
+create function notification_notify() returns trigger as $$
+begin
+    perform pg_notify('notification', json_build_object('id', new.id, 'user_id', new.user_id)::text);
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger notification_notify_trg
+    after insert on notification
+    for each row execute function notification_notify();
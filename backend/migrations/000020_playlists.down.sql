@@ -0,0 +1,4 @@
+drop table if exists playlist_editor;
+drop table if exists playlist_like;
+drop table if exists playlist_item;
+drop table if exists playlist;
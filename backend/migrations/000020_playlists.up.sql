@@ -0,0 +1,54 @@
+-- пользовательские списки песен и альбомов; /api/lists
+create table playlist (
+    id serial primary key,
+    owner_id int not null references users (id) on delete cascade,
+    title varchar(120) not null,
+    description text not null default '',
+    visibility varchar(16) not null default 'public'
+        check (visibility in ('public', 'unlisted', 'private')),
+    -- ranked список выводится с местами 1..n, иначе как подборка
+    ranked boolean not null default true,
+    item_count int not null default 0,
+    like_count int not null default 0,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+);
+
+create index playlist_owner_idx on playlist (owner_id, updated_at desc);
+create index playlist_public_idx on playlist (updated_at desc) where visibility = 'public';
+
+-- места идут подряд с 1; при перестановке они временно совпадают до конца транзакции
+create table playlist_item (
+    id serial primary key,
+    playlist_id int not null references playlist (id) on delete cascade,
+    song_id int references song (id) on delete cascade,
+    album_id int references album (id) on delete cascade,
+    position int not null,
+    note text not null default '',
+    added_by int references users (id) on delete set null,
+    created_at timestamptz not null default now(),
+    check (num_nonnulls(song_id, album_id) = 1),
+    constraint playlist_item_position_key unique (playlist_id, position) deferrable initially deferred
+);
+
+create unique index playlist_item_song_key on playlist_item (playlist_id, song_id) where song_id is not null;
+create unique index playlist_item_album_key on playlist_item (playlist_id, album_id) where album_id is not null;
+create index playlist_item_song_idx on playlist_item (song_id) where song_id is not null;
+create index playlist_item_album_idx on playlist_item (album_id) where album_id is not null;
+
+create table playlist_like (
+    playlist_id int not null references playlist (id) on delete cascade,
+    user_id int not null references users (id) on delete cascade,
+    created_at timestamptz not null default now(),
+    primary key (playlist_id, user_id)
+);
+
+-- соавторы меняют состав и заметки, но не сам список
+create table playlist_editor (
+    playlist_id int not null references playlist (id) on delete cascade,
+    user_id int not null references users (id) on delete cascade,
+    added_at timestamptz not null default now(),
+    primary key (playlist_id, user_id)
+);
+
+create index playlist_editor_user_idx on playlist_editor (user_id);
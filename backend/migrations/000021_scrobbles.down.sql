@@ -0,0 +1,5 @@
+drop index if exists artist_nickname_lower_idx;
+drop table if exists scrobble_now_playing;
+drop table if exists scrobble;
+drop table if exists scrobble_token;
+drop table if exists scrobble_session;
@@ -0,0 +1,56 @@
+-- ключи сессий скробблеров; выдаются auth.getMobileSession и auth.getSession
+create table scrobble_session (
+    id serial primary key,
+    key varchar(32) not null unique,
+    user_id int not null references users (id) on delete cascade,
+    -- api_key клиента, по нему пользователь узнаёт скробблер в профиле
+    client varchar(64) not null default '',
+    created_at timestamptz not null default now(),
+    last_used_at timestamptz
+);
+
+create index scrobble_session_user_idx on scrobble_session (user_id);
+
+-- токены входа настольных клиентов: auth.getToken, подтверждение на сайте, auth.getSession
+create table scrobble_token (
+    token varchar(32) primary key,
+    client varchar(64) not null default '',
+    user_id int references users (id) on delete cascade,
+    created_at timestamptz not null default now()
+);
+
+-- прослушивания; строки клиента хранятся как есть, song_id и artist_id
+-- заполняются, если их удалось сопоставить с каталогом
+create table scrobble (
+    id bigserial primary key,
+    user_id int not null references users (id) on delete cascade,
+    artist_name varchar(255) not null,
+    track_name varchar(255) not null,
+    album_name varchar(255) not null default '',
+    song_id int references song (id) on delete set null,
+    artist_id int references artist (id) on delete set null,
+    duration int,
+    played_at timestamptz not null,
+    created_at timestamptz not null default now(),
+    -- повторная отправка того же прослушивания ничего не добавляет
+    constraint scrobble_user_played_key unique (user_id, played_at)
+);
+
+create index scrobble_user_idx on scrobble (user_id, played_at desc);
+create index scrobble_song_idx on scrobble (song_id) where song_id is not null;
+create index scrobble_artist_idx on scrobble (artist_id) where artist_id is not null;
+
+create table scrobble_now_playing (
+    user_id int primary key references users (id) on delete cascade,
+    artist_name varchar(255) not null,
+    track_name varchar(255) not null,
+    album_name varchar(255) not null default '',
+    song_id int references song (id) on delete set null,
+    artist_id int references artist (id) on delete set null,
+    duration int,
+    started_at timestamptz not null,
+    expires_at timestamptz not null
+);
+
+-- сопоставление строк скробблера с исполнителем без учёта регистра
+create index artist_nickname_lower_idx on artist (lower(nickname));
@@ -0,0 +1,19 @@
+-- личная библиотека: избранное и одна из полок want/listened на песню или альбом
+create table library_item (
+    id serial primary key,
+    user_id int not null references users (id) on delete cascade,
+    song_id int references song (id) on delete cascade,
+    album_id int references album (id) on delete cascade,
+    favorite boolean not null default false,
+    favorited_at timestamptz,
+    shelf varchar(16) check (shelf in ('want', 'listened')),
+    shelved_at timestamptz,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+    check (num_nonnulls(song_id, album_id) = 1)
+);
+
+create unique index library_item_song_key on library_item (user_id, song_id) where song_id is not null;
+create unique index library_item_album_key on library_item (user_id, album_id) where album_id is not null;
+create index library_item_favorite_idx on library_item (user_id, favorited_at desc) where favorite;
+create index library_item_shelf_idx on library_item (user_id, shelf, shelved_at desc) where shelf is not null;
@@ -0,0 +1 @@
+drop table if exists song_rank_prior_cache;
@@ -0,0 +1,12 @@
+-- априор байесовского среднего кэшируется одной строкой: представление
+-- song_rank_prior читает весь каталог, а приращения рейтинга идут в транзакции рецензии.
+-- Строку обновляет периодический пересчёт рангов.
+create table song_rank_prior_cache (
+    id boolean primary key default true check (id),
+    mean double precision not null,
+    weight double precision not null,
+    refreshed_at timestamptz not null default now()
+);
+
+insert into song_rank_prior_cache (mean, weight)
+select mean, weight from song_rank_prior;
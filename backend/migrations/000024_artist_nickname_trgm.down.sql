@@ -0,0 +1 @@
+drop index if exists artist_nickname_trgm_idx;
@@ -0,0 +1,7 @@
+-- кандидатов в дубли исполнителей отбирает база по триграммам имени,
+-- точную близость по Левенштейну считает сервис только для них
+create extension if not exists pg_trgm;
+
+create index if not exists artist_nickname_trgm_idx
+    on artist using gin (lower(nickname) gin_trgm_ops)
+    where deleted_at is null;
@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHeader = errors.New("mail header must not contain line breaks")
+
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func NewConfig(host, port, username, password, from string) *Config {
+	return &Config{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+// Client отправляет простые текстовые письма через SMTP-сервер
+type Client struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewClient(cfg *Config) *Client {
+	c := &Client{
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		from: cfg.From,
+	}
+	if cfg.Username != "" {
+		c.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return c
+}
+
+func (c *Client) Send(to, subject, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return ErrInvalidHeader
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", c.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(c.addr, c.auth, c.from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}